@@ -2,12 +2,50 @@ package identity
 
 import (
 	"context"
+	"fmt"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/graph"
+	identityapi "github.com/microsoft/azure-devops-go-api/azuredevops/v7/identity"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/location"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/memberentitlementmanagement"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/webapi"
+	"strings"
 )
 
-func GetUsers(ctx context.Context, connection *azuredevops.Connection) (*graph.PagedGraphUsers, error) {
+// GetCurrentUser returns the identity the personal access token authenticates as
+func GetCurrentUser(ctx context.Context, connection *azuredevops.Connection) (*webapi.IdentityRef, error) {
+	locationClient := location.NewClient(ctx, connection)
+
+	connectionData, err := locationClient.GetConnectionData(ctx, location.GetConnectionDataArgs{})
+	if err != nil {
+		return nil, err
+	}
+
+	user := connectionData.AuthenticatedUser
+	if user == nil || user.Id == nil {
+		return nil, fmt.Errorf("connection data has no authenticated user")
+	}
+
+	id := user.Id.String()
+	return &webapi.IdentityRef{
+		Id:          &id,
+		DisplayName: user.ProviderDisplayName,
+	}, nil
+}
+
+// Reviewer is a user or group that can be asked to review a pull request
+type Reviewer struct {
+	Id          string // identity ID, which is what pull request reviewers are keyed by
+	DisplayName string
+	UniqueName  string // mail address for users, [scope]\name for groups
+	IsGroup     bool
+}
+
+// Subject descriptors resolved per identities request, keeping the URL short
+const identityBatchSize = 50
+
+// GetUserReviewers returns the organization's licensed users
+func GetUserReviewers(ctx context.Context, connection *azuredevops.Connection) (*[]Reviewer, error) {
 	entitlementClient, err := memberentitlementmanagement.NewClient(ctx, connection)
 	if err != nil {
 		return nil, err
@@ -23,16 +61,96 @@ func GetUsers(ctx context.Context, connection *azuredevops.Connection) (*graph.P
 		return nil, err
 	}
 	// accounts with email addresses and with basic or basic + test plans
-	var filteredUsers []graph.GraphUser
+	reviewers := []Reviewer{}
 	if entitlements.Members != nil {
 		for _, member := range *entitlements.Members {
-			if member.User != nil && member.User.MailAddress != nil && *member.User.MailAddress != "" {
-				filteredUsers = append(filteredUsers, *member.User)
+			// The entitlement ID is the ID of the user's identity
+			if member.Id == nil || member.User == nil || member.User.MailAddress == nil || *member.User.MailAddress == "" {
+				continue
+			}
+			reviewer := Reviewer{Id: member.Id.String(), UniqueName: *member.User.MailAddress}
+			if member.User.DisplayName != nil {
+				reviewer.DisplayName = *member.User.DisplayName
 			}
+			reviewers = append(reviewers, reviewer)
 		}
 	}
 
-	return &graph.PagedGraphUsers{
-		GraphUsers: &filteredUsers,
-	}, nil
+	return &reviewers, nil
+}
+
+// GetGroupReviewers returns the organization's groups, including project teams
+func GetGroupReviewers(ctx context.Context, connection *azuredevops.Connection) (*[]Reviewer, error) {
+	graphClient, err := graph.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []graph.GraphGroup
+	var continuationToken *string
+	for {
+		page, err := graphClient.ListGroups(ctx, graph.ListGroupsArgs{ContinuationToken: continuationToken})
+		if err != nil {
+			return nil, err
+		}
+		if page.GraphGroups != nil {
+			groups = append(groups, *page.GraphGroups...)
+		}
+		if page.ContinuationToken == nil || len(*page.ContinuationToken) == 0 || (*page.ContinuationToken)[0] == "" {
+			break
+		}
+		continuationToken = &(*page.ContinuationToken)[0]
+	}
+
+	// Graph descriptors are not identity IDs, so resolve them through the identities API
+	identityClient, err := identityapi.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewers := []Reviewer{}
+	for start := 0; start < len(groups); start += identityBatchSize {
+		batch := groups[start:min(start+identityBatchSize, len(groups))]
+
+		var descriptors []string
+		byDescriptor := make(map[string]graph.GraphGroup)
+		for _, group := range batch {
+			if group.Descriptor != nil {
+				descriptors = append(descriptors, *group.Descriptor)
+				byDescriptor[*group.Descriptor] = group
+			}
+		}
+		if len(descriptors) == 0 {
+			continue
+		}
+
+		subjectDescriptors := strings.Join(descriptors, ",")
+		identities, err := identityClient.ReadIdentities(ctx, identityapi.ReadIdentitiesArgs{SubjectDescriptors: &subjectDescriptors})
+		if err != nil {
+			return nil, err
+		}
+		if identities == nil {
+			continue
+		}
+
+		for _, resolved := range *identities {
+			if resolved.Id == nil || resolved.SubjectDescriptor == nil {
+				continue
+			}
+			group, ok := byDescriptor[*resolved.SubjectDescriptor]
+			if !ok {
+				continue
+			}
+			reviewer := Reviewer{Id: resolved.Id.String(), IsGroup: true}
+			if group.DisplayName != nil {
+				reviewer.DisplayName = *group.DisplayName
+			}
+			if group.PrincipalName != nil {
+				reviewer.UniqueName = *group.PrincipalName
+			}
+			reviewers = append(reviewers, reviewer)
+		}
+	}
+
+	return &reviewers, nil
 }
@@ -2,9 +2,15 @@ package pipelines
 
 import (
 	"context"
+	"fmt"
+	"github.com/google/uuid"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/build"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/pipelines"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/pipelinesapproval"
+	"sort"
+	"strings"
 )
 
 func GetPipelines(ctx context.Context, connection *azuredevops.Connection, projectName string) (*[]pipelines.Pipeline, error) {
@@ -70,3 +76,297 @@ func GetRunTimeline(ctx context.Context, connection *azuredevops.Connection, pro
 	}
 	return timeline, nil
 }
+
+// GetLogLines returns the lines of a build log from startLine, counting from 1, to its current end
+func GetLogLines(ctx context.Context, connection *azuredevops.Connection, projectName string, buildID int, logID int, startLine int) (*[]string, error) {
+	buildClient, err := build.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	start := uint64(startLine)
+	logLinesArgs := build.GetBuildLogLinesArgs{
+		Project:   &projectName,
+		BuildId:   &buildID,
+		LogId:     &logID,
+		StartLine: &start,
+	}
+
+	lines, err := buildClient.GetBuildLogLines(ctx, logLinesArgs)
+
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+type Variable struct {
+	Name  string
+	Value string
+}
+
+func GetDefinition(ctx context.Context, connection *azuredevops.Connection, projectName string, definitionID int) (*build.BuildDefinition, error) {
+	buildClient, err := build.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	definitionArgs := build.GetDefinitionArgs{
+		Project:      &projectName,
+		DefinitionId: &definitionID,
+	}
+
+	definition, err := buildClient.GetDefinition(ctx, definitionArgs)
+
+	if err != nil {
+		return nil, err
+	}
+	return definition, nil
+}
+
+// QueueVariables returns the definition's variables that can be set when queueing, sorted by name.
+// Secret values are not returned by the API, so they start out empty.
+func QueueVariables(definition build.BuildDefinition) []Variable {
+	var variables []Variable
+	if definition.Variables == nil {
+		return variables
+	}
+	for name, variable := range *definition.Variables {
+		if variable.AllowOverride == nil || !*variable.AllowOverride {
+			continue
+		}
+		value := ""
+		if variable.Value != nil && (variable.IsSecret == nil || !*variable.IsSecret) {
+			value = *variable.Value
+		}
+		variables = append(variables, Variable{Name: name, Value: value})
+	}
+	sort.Slice(variables, func(i, j int) bool {
+		return strings.ToLower(variables[i].Name) < strings.ToLower(variables[j].Name)
+	})
+	return variables
+}
+
+// GetPipelineYaml reads the definition's YAML file from a branch. It is empty for classic
+// pipelines and for repositories hosted outside Azure Repos.
+func GetPipelineYaml(ctx context.Context, connection *azuredevops.Connection, projectName string, definition build.BuildDefinition, refName string) (string, error) {
+	process, ok := definition.Process.(map[string]interface{})
+	if !ok {
+		return "", nil
+	}
+	path, ok := process["yamlFilename"].(string)
+	if !ok || path == "" || definition.Repository == nil || definition.Repository.Id == nil ||
+		definition.Repository.Type == nil || *definition.Repository.Type != "TfsGit" {
+		return "", nil
+	}
+
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return "", err
+	}
+
+	branchName := strings.TrimPrefix(refName, "refs/heads/")
+	includeContent := true
+	itemArgs := git.GetItemArgs{
+		RepositoryId:   definition.Repository.Id,
+		Path:           &path,
+		Project:        &projectName,
+		IncludeContent: &includeContent,
+		VersionDescriptor: &git.GitVersionDescriptor{
+			Version:     &branchName,
+			VersionType: &git.GitVersionTypeValues.Branch,
+		},
+	}
+
+	item, err := gitClient.GetItem(ctx, itemArgs)
+	if err != nil {
+		return "", err
+	}
+	if item == nil || item.Content == nil {
+		return "", nil
+	}
+	return *item.Content, nil
+}
+
+// PreviewPipeline returns the YAML a run of the branch would use, with templates expanded
+func PreviewPipeline(ctx context.Context, connection *azuredevops.Connection, projectName string, pipelineID int, refName string) (string, error) {
+	PipelineClient := pipelines.NewClient(ctx, connection)
+	runParameters := runParameters(refName, nil, nil, nil)
+	previewRun := true
+	runParameters.PreviewRun = &previewRun
+	previewArgs := pipelines.PreviewArgs{
+		RunParameters: runParameters,
+		Project:       &projectName,
+		PipelineId:    &pipelineID,
+	}
+
+	preview, err := PipelineClient.Preview(ctx, previewArgs)
+
+	if err != nil {
+		return "", err
+	}
+	if preview.FinalYaml == nil {
+		return "", nil
+	}
+	return *preview.FinalYaml, nil
+}
+
+// RunPipeline queues a run of the pipeline on the branch
+func RunPipeline(ctx context.Context, connection *azuredevops.Connection, projectName string, pipelineID int, refName string, templateParameters map[string]string, variables map[string]string, stagesToSkip []string) (*pipelines.Run, error) {
+	PipelineClient := pipelines.NewClient(ctx, connection)
+	runArgs := pipelines.RunPipelineArgs{
+		RunParameters: runParameters(refName, templateParameters, variables, stagesToSkip),
+		Project:       &projectName,
+		PipelineId:    &pipelineID,
+	}
+
+	run, err := PipelineClient.RunPipeline(ctx, runArgs)
+
+	if err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func runParameters(refName string, templateParameters map[string]string, variables map[string]string, stagesToSkip []string) *pipelines.RunPipelineParameters {
+	repositories := map[string]pipelines.RepositoryResourceParameters{
+		"self": {RefName: &refName},
+	}
+	runParameters := &pipelines.RunPipelineParameters{
+		Resources: &pipelines.RunResourcesParameters{
+			Repositories: &repositories,
+		},
+	}
+	if len(templateParameters) > 0 {
+		runParameters.TemplateParameters = &templateParameters
+	}
+	if len(variables) > 0 {
+		runVariables := make(map[string]pipelines.Variable)
+		for name, value := range variables {
+			runVariables[name] = pipelines.Variable{Value: &value}
+		}
+		runParameters.Variables = &runVariables
+	}
+	if len(stagesToSkip) > 0 {
+		runParameters.StagesToSkip = &stagesToSkip
+	}
+	return runParameters
+}
+
+// CancelRun asks the service to cancel a queued or running build
+func CancelRun(ctx context.Context, connection *azuredevops.Connection, projectName string, buildID int) error {
+	buildClient, err := build.NewClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+
+	updateArgs := build.UpdateBuildArgs{
+		Build:   &build.Build{Status: &build.BuildStatusValues.Cancelling},
+		Project: &projectName,
+		BuildId: &buildID,
+	}
+
+	_, err = buildClient.UpdateBuild(ctx, updateArgs)
+	return err
+}
+
+// RetryStage reruns the failed and canceled jobs of a finished stage. stageRefName is the
+// stage's identifier from the timeline.
+func RetryStage(ctx context.Context, connection *azuredevops.Connection, projectName string, buildID int, stageRefName string) error {
+	buildClient, err := build.NewClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+
+	forceRetryAllJobs := false
+	stageArgs := build.UpdateStageArgs{
+		UpdateParameters: &build.UpdateStageParameters{
+			ForceRetryAllJobs: &forceRetryAllJobs,
+			State:             &build.StageUpdateTypeValues.Retry,
+		},
+		BuildId:      &buildID,
+		StageRefName: &stageRefName,
+		Project:      &projectName,
+	}
+
+	return buildClient.UpdateStage(ctx, stageArgs)
+}
+
+// RerunRun queues a new build of the same commit with the parameters and variables the build was queued with
+func RerunRun(ctx context.Context, connection *azuredevops.Connection, projectName string, buildID int) (*build.Build, error) {
+	buildClient, err := build.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := buildClient.GetBuild(ctx, build.GetBuildArgs{
+		Project: &projectName,
+		BuildId: &buildID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if previous.Definition == nil || previous.Definition.Id == nil {
+		return nil, fmt.Errorf("build %d has no definition", buildID)
+	}
+
+	queueArgs := build.QueueBuildArgs{
+		Build: &build.Build{
+			Definition:         &build.DefinitionReference{Id: previous.Definition.Id},
+			SourceBranch:       previous.SourceBranch,
+			SourceVersion:      previous.SourceVersion,
+			Parameters:         previous.Parameters,
+			TemplateParameters: previous.TemplateParameters,
+		},
+		Project: &projectName,
+	}
+
+	queued, err := buildClient.QueueBuild(ctx, queueArgs)
+
+	if err != nil {
+		return nil, err
+	}
+	return queued, nil
+}
+
+// GetApprovals returns the approvals with their steps, which list the assigned approvers
+func GetApprovals(ctx context.Context, connection *azuredevops.Connection, projectName string, approvalIDs []uuid.UUID) (*[]pipelinesapproval.Approval, error) {
+	approvalClient, err := pipelinesapproval.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	expand := pipelinesapproval.ApprovalDetailsExpandParameterValues.Steps
+	approvalsArgs := pipelinesapproval.QueryApprovalsArgs{
+		Project:     &projectName,
+		ApprovalIds: &approvalIDs,
+		Expand:      &expand,
+	}
+
+	approvals, err := approvalClient.QueryApprovals(ctx, approvalsArgs)
+
+	if err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+// UpdateApproval approves or rejects an approval as the signed in user
+func UpdateApproval(ctx context.Context, connection *azuredevops.Connection, projectName string, approvalID uuid.UUID, status pipelinesapproval.ApprovalStatus, comment string) error {
+	approvalClient, err := pipelinesapproval.NewClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+
+	updateArgs := pipelinesapproval.UpdateApprovalsArgs{
+		UpdateParameters: &[]pipelinesapproval.ApprovalUpdateParameters{{
+			ApprovalId: &approvalID,
+			Status:     &status,
+			Comment:    &comment,
+		}},
+		Project: &projectName,
+	}
+
+	_, err = approvalClient.UpdateApprovals(ctx, updateArgs)
+	return err
+}
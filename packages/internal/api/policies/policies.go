@@ -0,0 +1,98 @@
+package policies
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/policy"
+)
+
+// Policy type ID of the build validation policy
+var buildPolicyTypeId = uuid.MustParse("0609b952-1397-4640-95ec-e00a01b2c241")
+
+// PRArtifactId builds the artifact ID policy evaluations use to identify a pull request
+func PRArtifactId(projectId string, pullRequestId int) string {
+	return fmt.Sprintf("vstfs:///CodeReview/CodeReviewId/%s/%d", projectId, pullRequestId)
+}
+
+func GetPRPolicyEvaluations(ctx context.Context, connection *azuredevops.Connection, ProjectName string, projectId string, pullRequestId int) (*[]policy.PolicyEvaluationRecord, error) {
+	policyClient, err := policy.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	artifactId := PRArtifactId(projectId, pullRequestId)
+	evaluationsArgs := policy.GetPolicyEvaluationsArgs{
+		Project:    &ProjectName,
+		ArtifactId: &artifactId,
+	}
+
+	evaluations, err := policyClient.GetPolicyEvaluations(ctx, evaluationsArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluations, nil
+}
+
+func RequeuePolicyEvaluation(ctx context.Context, connection *azuredevops.Connection, ProjectName string, evaluationId uuid.UUID) (*policy.PolicyEvaluationRecord, error) {
+	policyClient, err := policy.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	requeueArgs := policy.RequeuePolicyEvaluationArgs{
+		Project:      &ProjectName,
+		EvaluationId: &evaluationId,
+	}
+
+	evaluation, err := policyClient.RequeuePolicyEvaluation(ctx, requeueArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluation, nil
+}
+
+// EvaluationName returns the policy type name, with the build name for build validations
+func EvaluationName(evaluation policy.PolicyEvaluationRecord) string {
+	name := "Policy"
+	if evaluation.Configuration == nil {
+		return name
+	}
+	if evaluation.Configuration.Type != nil && evaluation.Configuration.Type.DisplayName != nil {
+		name = *evaluation.Configuration.Type.DisplayName
+	}
+
+	if settings, ok := evaluation.Configuration.Settings.(map[string]interface{}); ok {
+		if displayName, ok := settings["displayName"].(string); ok && displayName != "" {
+			name = fmt.Sprintf("%s: %s", name, displayName)
+		}
+	}
+	return name
+}
+
+func IsBuildValidation(evaluation policy.PolicyEvaluationRecord) bool {
+	return evaluation.Configuration != nil && evaluation.Configuration.Type != nil &&
+		evaluation.Configuration.Type.Id != nil && *evaluation.Configuration.Type.Id == buildPolicyTypeId
+}
+
+func IsBlocking(evaluation policy.PolicyEvaluationRecord) bool {
+	return evaluation.Configuration != nil && evaluation.Configuration.IsBlocking != nil && *evaluation.Configuration.IsBlocking
+}
+
+// IsFailed reports whether the evaluation was rejected or broke while running
+func IsFailed(evaluation policy.PolicyEvaluationRecord) bool {
+	return evaluation.Status != nil &&
+		(*evaluation.Status == policy.PolicyEvaluationStatusValues.Rejected || *evaluation.Status == policy.PolicyEvaluationStatusValues.Broken)
+}
+
+// IsPending reports whether a blocking policy still prevents the PR from completing
+func IsPending(evaluation policy.PolicyEvaluationRecord) bool {
+	if !IsBlocking(evaluation) || evaluation.Status == nil {
+		return false
+	}
+	return *evaluation.Status != policy.PolicyEvaluationStatusValues.Approved &&
+		*evaluation.Status != policy.PolicyEvaluationStatusValues.NotApplicable
+}
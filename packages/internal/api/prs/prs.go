@@ -2,26 +2,139 @@ package prs
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/google/uuid"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/core"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/webapi"
+	"io"
+	"strings"
+	"time"
 )
 
-func GetPRs(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string) (*[]git.GitPullRequest, error) {
+type PRFileChange struct {
+	Path             string
+	OriginalPath     string
+	ChangeType       git.VersionControlChangeType
+	ObjectId         string
+	OriginalObjectId string
+	ChangeTrackingId int
+	// The iterations the change was computed between, CompareTo 0 being the merge base
+	IterationId int
+	CompareTo   int
+}
+
+// PRFilter narrows the pull requests returned by GetPRs, empty fields are not filtered on
+type PRFilter struct {
+	Status        git.PullRequestStatus // defaults to active
+	CreatorId     string
+	ReviewerId    string
+	TargetRefName string
+	Label         string // matched after fetching, as the search criteria cannot filter on labels
+}
+
+// Pull requests fetched per page while looking for a label, and the most looked through
+const (
+	labelPageSize = 100
+	labelMaxPRs   = 2000
+)
+
+func GetPRs(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, filter PRFilter) (*[]git.GitPullRequest, error) {
 	gitClient, err := git.NewClient(ctx, connection)
 	if err != nil {
 		return nil, err
 	}
 
-	PRStatus := git.PullRequestStatusValues.Active
+	searchCriteria, err := filter.searchCriteria()
+	if err != nil {
+		return nil, err
+	}
+
+	return filter.query(func(top *int, skip *int) (*[]git.GitPullRequest, error) {
+		getPRsArgs := git.GetPullRequestsArgs{RepositoryId: &RepositoryId, Project: &ProjectName, SearchCriteria: searchCriteria, Top: top, Skip: skip}
+		return gitClient.GetPullRequests(ctx, getPRsArgs)
+	})
+}
 
-	getPRsArgs := git.GetPullRequestsArgs{RepositoryId: &RepositoryId, Project: &ProjectName, SearchCriteria: &git.GitPullRequestSearchCriteria{Status: &PRStatus}}
-	pullRequests, err := gitClient.GetPullRequests(ctx, getPRsArgs)
+// GetProjectPRs queries pull requests across every repository in a project
+func GetProjectPRs(ctx context.Context, connection *azuredevops.Connection, ProjectName string, filter PRFilter) (*[]git.GitPullRequest, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
 
+	searchCriteria, err := filter.searchCriteria()
 	if err != nil {
 		return nil, err
 	}
-	return pullRequests, nil
+
+	return filter.query(func(top *int, skip *int) (*[]git.GitPullRequest, error) {
+		getPRsArgs := git.GetPullRequestsByProjectArgs{Project: &ProjectName, SearchCriteria: searchCriteria, Top: top, Skip: skip}
+		return gitClient.GetPullRequestsByProject(ctx, getPRsArgs)
+	})
+}
+
+// query runs a pull request search. Without a label the server's first page is returned.
+// With one, the search is paged through, up to labelMaxPRs pull requests, so that labelled
+// pull requests beyond the first page are not missed.
+func (filter PRFilter) query(get func(top *int, skip *int) (*[]git.GitPullRequest, error)) (*[]git.GitPullRequest, error) {
+	if filter.Label == "" {
+		return get(nil, nil)
+	}
+
+	labelled := []git.GitPullRequest{}
+	for skip := 0; skip < labelMaxPRs; skip += labelPageSize {
+		top := labelPageSize
+		page, err := get(&top, &skip)
+		if err != nil {
+			return nil, err
+		}
+		if page == nil {
+			break
+		}
+		for _, pr := range *page {
+			for _, name := range LabelNames(pr) {
+				if strings.EqualFold(name, filter.Label) {
+					labelled = append(labelled, pr)
+					break
+				}
+			}
+		}
+		if len(*page) < labelPageSize {
+			break
+		}
+	}
+	return &labelled, nil
+}
+
+func (filter PRFilter) searchCriteria() (*git.GitPullRequestSearchCriteria, error) {
+	PRStatus := filter.Status
+	if PRStatus == "" {
+		PRStatus = git.PullRequestStatusValues.Active
+	}
+	searchCriteria := &git.GitPullRequestSearchCriteria{Status: &PRStatus}
+
+	if filter.CreatorId != "" {
+		creatorId, err := uuid.Parse(filter.CreatorId)
+		if err != nil {
+			return nil, fmt.Errorf("invalid creator ID %q: %v", filter.CreatorId, err)
+		}
+		searchCriteria.CreatorId = &creatorId
+	}
+	if filter.ReviewerId != "" {
+		reviewerId, err := uuid.Parse(filter.ReviewerId)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reviewer ID %q: %v", filter.ReviewerId, err)
+		}
+		searchCriteria.ReviewerId = &reviewerId
+	}
+	if filter.TargetRefName != "" {
+		targetRefName := filter.TargetRefName
+		searchCriteria.TargetRefName = &targetRefName
+	}
+	return searchCriteria, nil
 }
 
 func GetBranches(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string) (*[]git.GitRef, error) {
@@ -49,6 +162,46 @@ func GetBranches(ctx context.Context, connection *azuredevops.Connection, Projec
 	return &emptyBranches, nil
 }
 
+// Caps the commits fetched for a PR's commit range
+const maxBranchCommits = 100
+
+// GetBranchCommits returns the commits on the source branch that are not on the target branch, newest first
+func GetBranchCommits(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, sourceRefName string, targetRefName string) (*[]git.GitCommitRef, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceBranch := strings.TrimPrefix(sourceRefName, "refs/heads/")
+	targetBranch := strings.TrimPrefix(targetRefName, "refs/heads/")
+	getCommitsArgs := git.GetCommitsBatchArgs{
+		RepositoryId: &RepositoryId,
+		Project:      &ProjectName,
+		Top:          intPtr(maxBranchCommits),
+		// History is walked from the compare version until it reaches the item version
+		SearchCriteria: &git.GitQueryCommitsCriteria{
+			ItemVersion: &git.GitVersionDescriptor{
+				Version:     &targetBranch,
+				VersionType: &git.GitVersionTypeValues.Branch,
+			},
+			CompareVersion: &git.GitVersionDescriptor{
+				Version:     &sourceBranch,
+				VersionType: &git.GitVersionTypeValues.Branch,
+			},
+		},
+	}
+
+	commits, err := gitClient.GetCommitsBatch(ctx, getCommitsArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	if commits == nil {
+		return &[]git.GitCommitRef{}, nil
+	}
+	return commits, nil
+}
+
 func CreatePR(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, req *git.GitPullRequest) (*git.GitPullRequest, error) {
 	gitClient, err := git.NewClient(ctx, connection)
 	if err != nil {
@@ -69,175 +222,386 @@ func CreatePR(ctx context.Context, connection *azuredevops.Connection, ProjectNa
 	return pr, nil
 }
 
+// GetLatestBranch returns the branch with the most recent commit, other than the default branch
 func GetLatestBranch(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string) (*git.GitRef, error) {
 	gitClient, err := git.NewClient(ctx, connection)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get commits to find the most recently updated branch
-	getCommitsArgs := git.GetCommitsArgs{
+	// Branch stats carry each branch's head commit, compared against the default branch
+	getBranchesArgs := git.GetBranchesArgs{
 		RepositoryId: &RepositoryId,
 		Project:      &ProjectName,
-		SearchCriteria: &git.GitQueryCommitsCriteria{
-			Top: intPtr(50), // Get last 50 commits
-		},
 	}
 
-	_, err = gitClient.GetCommits(ctx, getCommitsArgs)
-	if err != nil {
-		// Fallback to branch list if commits fail
-		branches, err := GetBranches(ctx, connection, ProjectName, RepositoryId)
-		if err != nil || branches == nil || len(*branches) == 0 {
-			return nil, err
-		}
-
-		// Find the first non-main branch
-		for _, branch := range *branches {
-			if branch.Name != nil {
-				branchName := *branch.Name
-				if branchName != "refs/heads/main" && branchName != "refs/heads/master" {
-					return &branch, nil
-				}
-			}
-		}
-		return nil, nil
-	}
-
-	// Find branches by recent commits
-	branches, err := GetBranches(ctx, connection, ProjectName, RepositoryId)
+	branches, err := gitClient.GetBranches(ctx, getBranchesArgs)
 	if err != nil || branches == nil {
 		return nil, err
 	}
 
-	// Return the first non-main branch for now
-	// In a production app, you'd check commit dates to find the most recent
-	for _, branch := range *branches {
-		if branch.Name != nil {
-			branchName := *branch.Name
-			if branchName != "refs/heads/main" && branchName != "refs/heads/master" {
-				return &branch, nil
-			}
+	var latest *git.GitBranchStats
+	var latestDate time.Time
+	for i, branch := range *branches {
+		if branch.Name == nil || (branch.IsBaseVersion != nil && *branch.IsBaseVersion) {
+			continue
+		}
+		if branch.Commit == nil || branch.Commit.Committer == nil || branch.Commit.Committer.Date == nil {
+			continue
 		}
+		if commitDate := branch.Commit.Committer.Date.Time; latest == nil || commitDate.After(latestDate) {
+			latest = &(*branches)[i]
+			latestDate = commitDate
+		}
+	}
+
+	if latest == nil {
+		return nil, nil
 	}
 
-	return nil, nil
+	name := "refs/heads/" + *latest.Name
+	return &git.GitRef{Name: &name, ObjectId: latest.Commit.CommitId}, nil
 }
 
 func intPtr(i int) *int {
 	return &i
 }
 
-func ApprovePR(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, vote int, comment string) error {
+// VotePR casts a vote as the given reviewer, adding them to the PR if they are not a reviewer yet.
+// Valid votes are 10 (approved), 5 (approved with suggestions), 0 (no vote), -5 (waiting for author) and -10 (rejected).
+func VotePR(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, reviewerId string, vote int, comment string) (*git.IdentityRefWithVote, error) {
 	gitClient, err := git.NewClient(ctx, connection)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	switch vote {
+	case 10, 5, 0, -5, -10:
+	default:
+		return nil, fmt.Errorf("invalid vote value %d", vote)
+	}
+
+	// Creating the reviewer with a vote updates it in place when it already exists
+	reviewerArgs := git.CreatePullRequestReviewerArgs{
+		RepositoryId:  &RepositoryId,
+		PullRequestId: &pullRequestId,
+		Project:       &ProjectName,
+		ReviewerId:    &reviewerId,
+		Reviewer: &git.IdentityRefWithVote{
+			Vote: &vote,
+		},
+	}
+
+	reviewer, err := gitClient.CreatePullRequestReviewer(ctx, reviewerArgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update reviewer vote: %v", err)
 	}
 
-	// Step 1: Always add a comment first - this ensures the user becomes a reviewer
-	actualComment := comment
-	if actualComment == "" {
-		// Add a default comment if none provided
-		if vote == 10 {
-			actualComment = "Approved"
-		} else if vote == -10 {
-			actualComment = "Declined"
-		} else {
-			actualComment = "Reviewed"
+	if comment != "" {
+		if _, err := CreatePRThread(ctx, connection, ProjectName, RepositoryId, pullRequestId, comment); err != nil {
+			return reviewer, fmt.Errorf("vote submitted but failed to add comment: %v", err)
 		}
 	}
 
-	threadArgs := git.CreateThreadArgs{
+	return reviewer, nil
+}
+
+// SetPRReviewer adds a reviewer to the PR, or updates whether an existing one is required.
+// No vote is sent, since only reviewers can cast their own, so the server keeps any vote already cast.
+func SetPRReviewer(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, reviewerId string, isRequired bool) (*git.IdentityRefWithVote, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewerArgs := git.CreatePullRequestReviewerArgs{
 		RepositoryId:  &RepositoryId,
 		PullRequestId: &pullRequestId,
 		Project:       &ProjectName,
-		CommentThread: &git.GitPullRequestCommentThread{
-			Comments: &[]git.Comment{
-				{
-					Content: &actualComment,
-				},
-			},
+		ReviewerId:    &reviewerId,
+		Reviewer: &git.IdentityRefWithVote{
+			IsRequired: &isRequired,
 		},
 	}
 
-	_, err = gitClient.CreateThread(ctx, threadArgs)
+	reviewer, err := gitClient.CreatePullRequestReviewer(ctx, reviewerArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return reviewer, nil
+}
+
+func RemovePRReviewer(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, reviewerId string) error {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+
+	deleteArgs := git.DeletePullRequestReviewerArgs{
+		RepositoryId:  &RepositoryId,
+		PullRequestId: &pullRequestId,
+		Project:       &ProjectName,
+		ReviewerId:    &reviewerId,
+	}
+
+	return gitClient.DeletePullRequestReviewer(ctx, deleteArgs)
+}
+
+func CompletePR(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, completionOptions *git.GitPullRequestCompletionOptions) (*git.GitPullRequest, error) {
+	gitClient, err := git.NewClient(ctx, connection)
 	if err != nil {
-		return fmt.Errorf("failed to add comment: %v", err)
+		return nil, err
 	}
 
-	// Step 2: Get the PR to find all reviewers including the current user
+	// The server requires the source commit the completion was decided on
 	getPRArgs := git.GetPullRequestArgs{
 		RepositoryId:  &RepositoryId,
 		PullRequestId: &pullRequestId,
 		Project:       &ProjectName,
 	}
 
-	pr, err := gitClient.GetPullRequest(ctx, getPRArgs)
+	current, err := gitClient.GetPullRequest(ctx, getPRArgs)
 	if err != nil {
-		return fmt.Errorf("failed to get PR details: %v", err)
+		return nil, fmt.Errorf("failed to get PR details: %v", err)
 	}
 
-	// Step 3: Find and update the current user's reviewer vote
-	if pr.Reviewers == nil || len(*pr.Reviewers) == 0 {
-		return fmt.Errorf("no reviewers found on PR")
+	// Update PR to completed status
+	prUpdate := &git.GitPullRequest{
+		Status:                &git.PullRequestStatusValues.Completed,
+		CompletionOptions:     completionOptions,
+		LastMergeSourceCommit: current.LastMergeSourceCommit,
 	}
 
-	// Set appropriate flags based on vote value
-	isRequired := false
-	isFlagged := false
-	hasDeclined := false
+	updateArgs := git.UpdatePullRequestArgs{
+		RepositoryId:           &RepositoryId,
+		PullRequestId:          &pullRequestId,
+		Project:                &ProjectName,
+		GitPullRequestToUpdate: prUpdate,
+	}
 
-	switch vote {
-	case -10: // Rejected
-		hasDeclined = true
-	case -5: // Waiting for author
-		isFlagged = true
-	}
-
-	// Try to update each reviewer until one succeeds (the current user should be among them)
-	var lastError error
-	for _, reviewer := range *pr.Reviewers {
-		if reviewer.Id != nil {
-			updateArgs := git.UpdatePullRequestReviewerArgs{
-				RepositoryId:  &RepositoryId,
-				PullRequestId: &pullRequestId,
-				Project:       &ProjectName,
-				ReviewerId:    reviewer.Id,
-				Reviewer: &git.IdentityRefWithVote{
-					Vote:        &vote,
-					IsRequired:  &isRequired,
-					IsFlagged:   &isFlagged,
-					HasDeclined: &hasDeclined,
-				},
-			}
+	pr, err := gitClient.UpdatePullRequest(ctx, updateArgs)
+	if err != nil {
+		return nil, err
+	}
 
-			_, err = gitClient.UpdatePullRequestReviewer(ctx, updateArgs)
-			if err == nil {
-				// Successfully updated - we found the right reviewer
-				return nil
-			}
-			lastError = err
-			// Continue to try next reviewer
+	return pr, nil
+}
+
+// BypassAndCompletePR completes the PR while bypassing branch policies, which needs the
+// "Bypass policies when completing pull requests" permission
+func BypassAndCompletePR(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, reason string, completionOptions *git.GitPullRequestCompletionOptions) (*git.GitPullRequest, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("a bypass reason is required")
+	}
+
+	options := git.GitPullRequestCompletionOptions{}
+	if completionOptions != nil {
+		options = *completionOptions
+	}
+	bypassPolicy := true
+	options.BypassPolicy = &bypassPolicy
+	options.BypassReason = &reason
+
+	return CompletePR(ctx, connection, ProjectName, RepositoryId, pullRequestId, &options)
+}
+
+// IsPermissionError reports whether the server rejected a request because the user lacks permission
+func IsPermissionError(err error) bool {
+	statusCode := errorStatusCode(err)
+	if statusCode == 401 || statusCode == 403 {
+		return true
+	}
+
+	// TF401027 is returned when a Git permission such as PullRequestBypassPolicy is missing
+	return err != nil && strings.Contains(err.Error(), "TF401027")
+}
+
+// errorStatusCode returns the HTTP status of a failed request, or 0 when it is not known
+func errorStatusCode(err error) int {
+	var wrappedError azuredevops.WrappedError
+	var wrappedErrorPtr *azuredevops.WrappedError
+	var statusCode *int
+	if errors.As(err, &wrappedError) {
+		statusCode = wrappedError.StatusCode
+	} else if errors.As(err, &wrappedErrorPtr) {
+		statusCode = wrappedErrorPtr.StatusCode
+	}
+	if statusCode == nil {
+		return 0
+	}
+	return *statusCode
+}
+
+func AddPRLabel(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, name string) (*core.WebApiTagDefinition, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	createLabelArgs := git.CreatePullRequestLabelArgs{
+		Label:         &core.WebApiCreateTagRequestData{Name: &name},
+		RepositoryId:  &RepositoryId,
+		PullRequestId: &pullRequestId,
+		Project:       &ProjectName,
+	}
+
+	label, err := gitClient.CreatePullRequestLabel(ctx, createLabelArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return label, nil
+}
+
+func RemovePRLabel(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, labelIdOrName string) error {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+
+	deleteLabelArgs := git.DeletePullRequestLabelsArgs{
+		RepositoryId:  &RepositoryId,
+		PullRequestId: &pullRequestId,
+		LabelIdOrName: &labelIdOrName,
+		Project:       &ProjectName,
+	}
+
+	return gitClient.DeletePullRequestLabels(ctx, deleteLabelArgs)
+}
+
+// LabelNames returns the names of the PR's active labels
+func LabelNames(pr git.GitPullRequest) []string {
+	var names []string
+	if pr.Labels == nil {
+		return names
+	}
+	for _, label := range *pr.Labels {
+		if label.Name != nil && (label.Active == nil || *label.Active) {
+			names = append(names, *label.Name)
 		}
 	}
+	return names
+}
+
+// GetPRWorkItemRefs returns references to the work items linked to the PR
+func GetPRWorkItemRefs(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int) (*[]webapi.ResourceRef, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	refsArgs := git.GetPullRequestWorkItemRefsArgs{
+		RepositoryId:  &RepositoryId,
+		PullRequestId: &pullRequestId,
+		Project:       &ProjectName,
+	}
 
-	if lastError != nil {
-		return fmt.Errorf("failed to update reviewer vote: %v", lastError)
+	refs, err := gitClient.GetPullRequestWorkItemRefs(ctx, refsArgs)
+	if err != nil {
+		return nil, err
 	}
 
-	return fmt.Errorf("no valid reviewers found to update")
+	return refs, nil
 }
 
-func CompletePR(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, completionOptions *git.GitPullRequestCompletionOptions) (*git.GitPullRequest, error) {
+// CreateCherryPick starts a server-side cherry-pick of the PR's changes onto ontoRefName,
+// writing the result to the new generatedRefName branch
+func CreateCherryPick(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, ontoRefName string, generatedRefName string) (*git.GitCherryPick, error) {
 	gitClient, err := git.NewClient(ctx, connection)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update PR to completed status
-	prUpdate := &git.GitPullRequest{
-		Status:            &git.PullRequestStatusValues.Completed,
-		CompletionOptions: completionOptions,
+	cherryPickArgs := git.CreateCherryPickArgs{
+		CherryPickToCreate: refOperationParameters(pullRequestId, ontoRefName, generatedRefName),
+		Project:            &ProjectName,
+		RepositoryId:       &RepositoryId,
+	}
+
+	return gitClient.CreateCherryPick(ctx, cherryPickArgs)
+}
+
+func GetCherryPick(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, cherryPickId int) (*git.GitCherryPick, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	cherryPickArgs := git.GetCherryPickArgs{
+		Project:      &ProjectName,
+		CherryPickId: &cherryPickId,
+		RepositoryId: &RepositoryId,
+	}
+
+	return gitClient.GetCherryPick(ctx, cherryPickArgs)
+}
+
+// CreateRevert starts a server-side revert of the PR's changes onto ontoRefName,
+// writing the result to the new generatedRefName branch
+func CreateRevert(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, ontoRefName string, generatedRefName string) (*git.GitRevert, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	revertArgs := git.CreateRevertArgs{
+		RevertToCreate: refOperationParameters(pullRequestId, ontoRefName, generatedRefName),
+		Project:        &ProjectName,
+		RepositoryId:   &RepositoryId,
+	}
+
+	return gitClient.CreateRevert(ctx, revertArgs)
+}
+
+func GetRevert(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, revertId int) (*git.GitRevert, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	revertArgs := git.GetRevertArgs{
+		Project:      &ProjectName,
+		RevertId:     &revertId,
+		RepositoryId: &RepositoryId,
+	}
+
+	return gitClient.GetRevert(ctx, revertArgs)
+}
+
+func refOperationParameters(pullRequestId int, ontoRefName string, generatedRefName string) *git.GitAsyncRefOperationParameters {
+	return &git.GitAsyncRefOperationParameters{
+		GeneratedRefName: &generatedRefName,
+		OntoRefName:      &ontoRefName,
+		Source: &git.GitAsyncRefOperationSource{
+			PullRequestId: &pullRequestId,
+		},
+	}
+}
+
+func AbandonPR(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int) (*git.GitPullRequest, error) {
+	return updatePR(ctx, connection, ProjectName, RepositoryId, pullRequestId, &git.GitPullRequest{
+		Status: &git.PullRequestStatusValues.Abandoned,
+	})
+}
+
+func ReactivatePR(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int) (*git.GitPullRequest, error) {
+	return updatePR(ctx, connection, ProjectName, RepositoryId, pullRequestId, &git.GitPullRequest{
+		Status: &git.PullRequestStatusValues.Active,
+	})
+}
+
+// SetPRDraft converts an active PR to a draft, or publishes a draft when isDraft is false
+func SetPRDraft(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, isDraft bool) (*git.GitPullRequest, error) {
+	return updatePR(ctx, connection, ProjectName, RepositoryId, pullRequestId, &git.GitPullRequest{
+		IsDraft: &isDraft,
+	})
+}
+
+func updatePR(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, prUpdate *git.GitPullRequest) (*git.GitPullRequest, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
 	}
 
 	updateArgs := git.UpdatePullRequestArgs{
@@ -255,6 +619,60 @@ func CompletePR(ctx context.Context, connection *azuredevops.Connection, Project
 	return pr, nil
 }
 
+// SetAutoComplete makes the PR complete with the given options once all policies pass
+func SetAutoComplete(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, setBy *webapi.IdentityRef, completionOptions *git.GitPullRequestCompletionOptions) (*git.GitPullRequest, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	if setBy == nil || setBy.Id == nil {
+		return nil, fmt.Errorf("current user identity is unknown")
+	}
+
+	updateArgs := git.UpdatePullRequestArgs{
+		RepositoryId:  &RepositoryId,
+		PullRequestId: &pullRequestId,
+		Project:       &ProjectName,
+		GitPullRequestToUpdate: &git.GitPullRequest{
+			AutoCompleteSetBy: &webapi.IdentityRef{Id: setBy.Id},
+			CompletionOptions: completionOptions,
+		},
+	}
+
+	pr, err := gitClient.UpdatePullRequest(ctx, updateArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// CancelAutoComplete clears auto-complete, which the API expects as the empty identity
+func CancelAutoComplete(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int) (*git.GitPullRequest, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	emptyIdentity := "00000000-0000-0000-0000-000000000000"
+	updateArgs := git.UpdatePullRequestArgs{
+		RepositoryId:  &RepositoryId,
+		PullRequestId: &pullRequestId,
+		Project:       &ProjectName,
+		GitPullRequestToUpdate: &git.GitPullRequest{
+			AutoCompleteSetBy: &webapi.IdentityRef{Id: &emptyIdentity},
+		},
+	}
+
+	pr, err := gitClient.UpdatePullRequest(ctx, updateArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
 func GetPRDetails(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int) (*git.GitPullRequest, error) {
 	gitClient, err := git.NewClient(ctx, connection)
 	if err != nil {
@@ -295,6 +713,312 @@ func GetPRComments(ctx context.Context, connection *azuredevops.Connection, Proj
 	return threads, nil
 }
 
+func CreatePRThread(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, content string) (*git.GitPullRequestCommentThread, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	activeStatus := git.CommentThreadStatusValues.Active
+	threadArgs := git.CreateThreadArgs{
+		RepositoryId:  &RepositoryId,
+		PullRequestId: &pullRequestId,
+		Project:       &ProjectName,
+		CommentThread: &git.GitPullRequestCommentThread{
+			Comments: &[]git.Comment{
+				{
+					Content:     &content,
+					CommentType: &git.CommentTypeValues.Text,
+				},
+			},
+			Status: &activeStatus,
+		},
+	}
+
+	thread, err := gitClient.CreateThread(ctx, threadArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return thread, nil
+}
+
+// CreatePRFileThread starts a thread anchored to lines of a file in the iteration the change belongs to
+func CreatePRFileThread(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, file PRFileChange, threadContext git.CommentThreadContext, content string) (*git.GitPullRequestCommentThread, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	// Comparing against the merge base is expressed as starting from the first iteration
+	firstIteration := file.CompareTo
+	if firstIteration == 0 {
+		firstIteration = 1
+	}
+	secondIteration := file.IterationId
+
+	threadContext.FilePath = &file.Path
+	activeStatus := git.CommentThreadStatusValues.Active
+	thread := &git.GitPullRequestCommentThread{
+		Comments: &[]git.Comment{
+			{
+				Content:     &content,
+				CommentType: &git.CommentTypeValues.Text,
+			},
+		},
+		Status:        &activeStatus,
+		ThreadContext: &threadContext,
+	}
+	if file.ChangeTrackingId > 0 && secondIteration > 0 {
+		thread.PullRequestThreadContext = &git.GitPullRequestCommentThreadContext{
+			ChangeTrackingId: &file.ChangeTrackingId,
+			IterationContext: &git.CommentIterationContext{
+				FirstComparingIteration:  &firstIteration,
+				SecondComparingIteration: &secondIteration,
+			},
+		}
+	}
+
+	threadArgs := git.CreateThreadArgs{
+		RepositoryId:  &RepositoryId,
+		PullRequestId: &pullRequestId,
+		Project:       &ProjectName,
+		CommentThread: thread,
+	}
+
+	created, err := gitClient.CreateThread(ctx, threadArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func ReplyToPRThread(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, threadId int, parentCommentId int, content string) (*git.Comment, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &git.Comment{
+		Content:     &content,
+		CommentType: &git.CommentTypeValues.Text,
+	}
+	if parentCommentId > 0 {
+		comment.ParentCommentId = &parentCommentId
+	}
+
+	createCommentArgs := git.CreateCommentArgs{
+		Comment:       comment,
+		RepositoryId:  &RepositoryId,
+		PullRequestId: &pullRequestId,
+		ThreadId:      &threadId,
+		Project:       &ProjectName,
+	}
+
+	created, err := gitClient.CreateComment(ctx, createCommentArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func UpdatePRThreadStatus(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, threadId int, status git.CommentThreadStatus) (*git.GitPullRequestCommentThread, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	updateThreadArgs := git.UpdateThreadArgs{
+		CommentThread: &git.GitPullRequestCommentThread{
+			Status: &status,
+		},
+		RepositoryId:  &RepositoryId,
+		PullRequestId: &pullRequestId,
+		ThreadId:      &threadId,
+		Project:       &ProjectName,
+	}
+
+	thread, err := gitClient.UpdateThread(ctx, updateThreadArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return thread, nil
+}
+
+func GetPRIterations(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int) (*[]git.GitPullRequestIteration, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	getIterationsArgs := git.GetPullRequestIterationsArgs{
+		RepositoryId:   &RepositoryId,
+		PullRequestId:  &pullRequestId,
+		Project:        &ProjectName,
+		IncludeCommits: boolPtr(true),
+	}
+
+	iterations, err := gitClient.GetPullRequestIterations(ctx, getIterationsArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return iterations, nil
+}
+
+// GetPRIterationChanges lists the files changed in an iteration. A compareTo of 0
+// compares against the merge base of the source and target branches.
+func GetPRIterationChanges(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int, iterationId int, compareTo int) (*[]PRFileChange, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []PRFileChange{}
+	skip := 0
+	for {
+		getChangesArgs := git.GetPullRequestIterationChangesArgs{
+			RepositoryId:  &RepositoryId,
+			PullRequestId: &pullRequestId,
+			IterationId:   &iterationId,
+			Project:       &ProjectName,
+			Top:           intPtr(2000),
+			Skip:          intPtr(skip),
+			CompareTo:     intPtr(compareTo),
+		}
+
+		page, err := gitClient.GetPullRequestIterationChanges(ctx, getChangesArgs)
+		if err != nil {
+			return nil, err
+		}
+		if page == nil {
+			break
+		}
+
+		if page.ChangeEntries != nil {
+			for _, entry := range *page.ChangeEntries {
+				if change, ok := toFileChange(entry); ok {
+					change.IterationId = iterationId
+					change.CompareTo = compareTo
+					changes = append(changes, change)
+				}
+			}
+		}
+
+		if page.NextSkip == nil || *page.NextSkip == 0 {
+			break
+		}
+		skip = *page.NextSkip
+	}
+
+	return &changes, nil
+}
+
+// GetPRChanges lists the files changed in the latest iteration of a pull request
+func GetPRChanges(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, pullRequestId int) (*[]PRFileChange, error) {
+	iterations, err := GetPRIterations(ctx, connection, ProjectName, RepositoryId, pullRequestId)
+	if err != nil {
+		return nil, err
+	}
+
+	if iterations == nil || len(*iterations) == 0 {
+		emptyChanges := []PRFileChange{}
+		return &emptyChanges, nil
+	}
+
+	latest := (*iterations)[len(*iterations)-1]
+	if latest.Id == nil {
+		return nil, fmt.Errorf("latest iteration has no ID")
+	}
+
+	return GetPRIterationChanges(ctx, connection, ProjectName, RepositoryId, pullRequestId, *latest.Id, 0)
+}
+
+// GetFileContent fetches the raw content of a blob by its object ID
+func GetFileContent(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, objectId string) (string, error) {
+	if objectId == "" {
+		return "", nil
+	}
+
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return "", err
+	}
+
+	getBlobArgs := git.GetBlobContentArgs{
+		RepositoryId: &RepositoryId,
+		Sha1:         &objectId,
+		Project:      &ProjectName,
+	}
+
+	reader, err := gitClient.GetBlobContent(ctx, getBlobArgs)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// The change item is untyped in the SDK, so pull the fields we need out of the decoded JSON
+func toFileChange(entry git.GitPullRequestChange) (PRFileChange, bool) {
+	item, ok := entry.Item.(map[string]interface{})
+	if !ok {
+		return PRFileChange{}, false
+	}
+
+	if isFolder, ok := item["isFolder"].(bool); ok && isFolder {
+		return PRFileChange{}, false
+	}
+	if objectType, ok := item["gitObjectType"].(string); ok && objectType != "blob" {
+		return PRFileChange{}, false
+	}
+
+	change := PRFileChange{}
+	change.Path, _ = item["path"].(string)
+	change.ObjectId, _ = item["objectId"].(string)
+	change.OriginalObjectId, _ = item["originalObjectId"].(string)
+	if entry.OriginalPath != nil {
+		change.OriginalPath = *entry.OriginalPath
+	}
+	if entry.ChangeType != nil {
+		change.ChangeType = *entry.ChangeType
+	}
+	if entry.ChangeTrackingId != nil {
+		change.ChangeTrackingId = *entry.ChangeTrackingId
+	}
+
+	// Deleted files only carry the original blob
+	if HasChangeType(change.ChangeType, git.VersionControlChangeTypeValues.Delete) {
+		if change.OriginalObjectId == "" {
+			change.OriginalObjectId = change.ObjectId
+		}
+		change.ObjectId = ""
+	}
+
+	return change, change.Path != ""
+}
+
+// HasChangeType reports whether a change carries the given type. Changes combine several
+// types as a comma separated list such as "edit, rename", and names like undelete contain
+// others, so the list is split rather than searched.
+func HasChangeType(changeType git.VersionControlChangeType, want git.VersionControlChangeType) bool {
+	for _, part := range strings.Split(string(changeType), ",") {
+		if strings.TrimSpace(part) == string(want) {
+			return true
+		}
+	}
+	return false
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -302,3 +1026,55 @@ func stringPtr(s string) *string {
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+// Folders searched for PR templates, in the order Azure DevOps checks them
+var prTemplateFolders = []string{"/.azuredevops/", "/.vsts/", "/docs/", "/"}
+
+var prTemplateExtensions = []string{".md", ".txt"}
+
+// GetPRTemplate returns the description template for PRs into the target branch. A branch
+// specific template is preferred over the default one, and both are read from the default
+// branch like the web UI does. An empty string is returned when the repo has no template.
+func GetPRTemplate(ctx context.Context, connection *azuredevops.Connection, ProjectName string, RepositoryId string, targetRefName string) (string, error) {
+	gitClient, err := git.NewClient(ctx, connection)
+	if err != nil {
+		return "", err
+	}
+
+	var candidates []string
+	if branchName := strings.TrimPrefix(targetRefName, "refs/heads/"); branchName != "" {
+		for _, folder := range prTemplateFolders {
+			for _, extension := range prTemplateExtensions {
+				candidates = append(candidates, folder+"pull_request_template/branches/"+branchName+extension)
+			}
+		}
+	}
+	for _, folder := range prTemplateFolders {
+		for _, extension := range prTemplateExtensions {
+			candidates = append(candidates, folder+"pull_request_template"+extension)
+		}
+	}
+
+	includeContent := true
+	for _, path := range candidates {
+		getItemArgs := git.GetItemArgs{
+			RepositoryId:   &RepositoryId,
+			Path:           &path,
+			Project:        &ProjectName,
+			IncludeContent: &includeContent,
+		}
+
+		item, err := gitClient.GetItem(ctx, getItemArgs)
+		if err != nil {
+			if errorStatusCode(err) == 404 {
+				continue
+			}
+			return "", err
+		}
+		if item != nil && item.Content != nil {
+			return *item.Content, nil
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,65 @@
+package prs
+
+import (
+	"testing"
+
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
+)
+
+func TestHasChangeType(t *testing.T) {
+	values := git.VersionControlChangeTypeValues
+	tests := []struct {
+		changeType git.VersionControlChangeType
+		want       git.VersionControlChangeType
+		has        bool
+	}{
+		{"delete", values.Delete, true},
+		{"undelete", values.Delete, false},
+		{"undelete", values.Undelete, true},
+		{"edit, rename", values.Rename, true},
+		{"edit,rename", values.Rename, true},
+		{"edit, sourceRename", values.Rename, false},
+		{"delete, sourceRename", values.Delete, true},
+		{"", values.Edit, false},
+	}
+
+	for _, test := range tests {
+		if got := HasChangeType(test.changeType, test.want); got != test.has {
+			t.Errorf("HasChangeType(%q, %q) = %v, want %v", test.changeType, test.want, got, test.has)
+		}
+	}
+}
+
+func TestToFileChange(t *testing.T) {
+	entry := func(changeType git.VersionControlChangeType, item map[string]interface{}) git.GitPullRequestChange {
+		return git.GitPullRequestChange{ChangeType: &changeType, Item: item}
+	}
+	blob := map[string]interface{}{"path": "/a.go", "objectId": "new", "originalObjectId": "old", "gitObjectType": "blob"}
+
+	// A deleted file is diffed from its last content to nothing
+	deleted, ok := toFileChange(entry("delete", map[string]interface{}{"path": "/a.go", "objectId": "old"}))
+	if !ok || deleted.ObjectId != "" || deleted.OriginalObjectId != "old" {
+		t.Errorf("deleted file = %+v, %v", deleted, ok)
+	}
+
+	// A restored file keeps the content it was restored with
+	restored, ok := toFileChange(entry("undelete", blob))
+	if !ok || restored.ObjectId != "new" || restored.OriginalObjectId != "old" {
+		t.Errorf("restored file = %+v, %v", restored, ok)
+	}
+
+	original := "/old.go"
+	moved := entry("edit, rename", blob)
+	moved.OriginalPath = &original
+	renamed, ok := toFileChange(moved)
+	if !ok || renamed.ObjectId != "new" || renamed.OriginalPath != "/old.go" {
+		t.Errorf("renamed file = %+v, %v", renamed, ok)
+	}
+
+	if _, ok := toFileChange(entry("add", map[string]interface{}{"path": "/dir", "isFolder": true})); ok {
+		t.Error("folder listed as a file change")
+	}
+	if _, ok := toFileChange(entry("edit", map[string]interface{}{"path": "/sub", "gitObjectType": "commit"})); ok {
+		t.Error("submodule listed as a file change")
+	}
+}
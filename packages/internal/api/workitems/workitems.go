@@ -0,0 +1,140 @@
+package workitems
+
+import (
+	"context"
+	"fmt"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
+	"strconv"
+	"strings"
+)
+
+// Fields shown wherever work items are listed
+var summaryFields = []string{"System.Id", "System.Title", "System.State", "System.WorkItemType"}
+
+const maxSearchResults = 20
+
+// SearchWorkItems finds work items in the project by ID, or by title when the query is not a number
+func SearchWorkItems(ctx context.Context, connection *azuredevops.Connection, ProjectName string, query string) (*[]workitemtracking.WorkItem, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return &[]workitemtracking.WorkItem{}, nil
+	}
+
+	if id, err := strconv.Atoi(strings.TrimPrefix(query, "#")); err == nil {
+		return GetWorkItems(ctx, connection, ProjectName, []int{id})
+	}
+
+	witClient, err := workitemtracking.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	// WIQL string literals escape quotes by doubling them
+	escaped := strings.ReplaceAll(query, "'", "''")
+	wiql := fmt.Sprintf("SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project AND [System.Title] CONTAINS '%s' ORDER BY [System.ChangedDate] DESC", escaped)
+	top := maxSearchResults
+	queryArgs := workitemtracking.QueryByWiqlArgs{
+		Wiql:    &workitemtracking.Wiql{Query: &wiql},
+		Project: &ProjectName,
+		Top:     &top,
+	}
+
+	result, err := witClient.QueryByWiql(ctx, queryArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	if result.WorkItems != nil {
+		for _, ref := range *result.WorkItems {
+			if ref.Id != nil {
+				ids = append(ids, *ref.Id)
+			}
+		}
+	}
+	return GetWorkItems(ctx, connection, ProjectName, ids)
+}
+
+// GetWorkItems returns the summary fields of the given work items, skipping any that no longer exist
+func GetWorkItems(ctx context.Context, connection *azuredevops.Connection, ProjectName string, ids []int) (*[]workitemtracking.WorkItem, error) {
+	if len(ids) == 0 {
+		return &[]workitemtracking.WorkItem{}, nil
+	}
+
+	witClient, err := workitemtracking.NewClient(ctx, connection)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := summaryFields
+	errorPolicy := workitemtracking.WorkItemErrorPolicyValues.Omit
+	getArgs := workitemtracking.GetWorkItemsArgs{
+		Ids:         &ids,
+		Project:     &ProjectName,
+		Fields:      &fields,
+		ErrorPolicy: &errorPolicy,
+	}
+
+	items, err := witClient.GetWorkItems(ctx, getArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Omitted work items come back as empty entries
+	found := []workitemtracking.WorkItem{}
+	if items != nil {
+		for _, item := range *items {
+			if item.Id != nil {
+				found = append(found, item)
+			}
+		}
+	}
+	return &found, nil
+}
+
+// LinkPullRequest adds a pull request artifact link to the work item, as the web UI does
+func LinkPullRequest(ctx context.Context, connection *azuredevops.Connection, ProjectName string, workItemId int, projectId string, repositoryId string, pullRequestId int) error {
+	witClient, err := workitemtracking.NewClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+
+	artifactUrl := fmt.Sprintf("vstfs:///Git/PullRequestId/%s%%2F%s%%2F%d", projectId, repositoryId, pullRequestId)
+	path := "/relations/-"
+	document := []webapi.JsonPatchOperation{
+		{
+			Op:   &webapi.OperationValues.Add,
+			Path: &path,
+			Value: map[string]interface{}{
+				"rel": "ArtifactLink",
+				"url": artifactUrl,
+				"attributes": map[string]interface{}{
+					"name": "Pull Request",
+				},
+			},
+		},
+	}
+
+	updateArgs := workitemtracking.UpdateWorkItemArgs{
+		Document: &document,
+		Id:       &workItemId,
+		Project:  &ProjectName,
+	}
+
+	_, err = witClient.UpdateWorkItem(ctx, updateArgs)
+	return err
+}
+
+// Field returns a work item field as text, or an empty string when it is not set
+func Field(item workitemtracking.WorkItem, name string) string {
+	if item.Fields == nil {
+		return ""
+	}
+	value, ok := (*item.Fields)[name]
+	if !ok || value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -10,8 +10,23 @@ import (
 )
 
 type Config struct {
-	AzureOrgURL string `json:"azure_org_url"`
-	AzurePAT    string `json:"azure_pat"`
+	AzureOrgURL        string                        `json:"azure_org_url"`
+	AzurePAT           string                        `json:"azure_pat"`
+	CompletionDefaults map[string]CompletionDefaults `json:"completion_defaults,omitempty"`
+}
+
+// CompletionDefaults holds the PR completion options used for a repository, keyed by repository ID
+type CompletionDefaults struct {
+	MergeStrategy       string `json:"merge_strategy"`
+	DeleteSourceBranch  bool   `json:"delete_source_branch"`
+	TransitionWorkItems bool   `json:"transition_work_items"`
+}
+
+// Repos without saved defaults start on a merge commit, as the web UI does
+var defaultCompletion = CompletionDefaults{
+	MergeStrategy:       "merge",
+	DeleteSourceBranch:  true,
+	TransitionWorkItems: false,
 }
 
 func getXDGConfigPath() string {
@@ -65,20 +80,63 @@ func (c *Config) IsComplete() bool {
 	return c.AzureOrgURL != "" && c.AzurePAT != ""
 }
 
-func (c *Config) Save() error {
+func (c *Config) GetCompletionDefaults(repoID string) CompletionDefaults {
+	if defaults, ok := c.CompletionDefaults[repoID]; ok {
+		return defaults
+	}
+	return defaultCompletion
+}
+
+// SetCompletionDefaults stores the completion options for a repository. Only the
+// completion_defaults entry of the config file is rewritten, so credentials read from
+// the environment are never written to disk.
+func (c *Config) SetCompletionDefaults(repoID string, defaults CompletionDefaults) error {
+	if c.CompletionDefaults == nil {
+		c.CompletionDefaults = make(map[string]CompletionDefaults)
+	}
+	c.CompletionDefaults[repoID] = defaults
+
 	configPath := getXDGConfigPath()
 
-	// Ensure directory exists
-	dir := filepath.Dir(configPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %v", err)
+	// Keep the rest of the file as it is
+	fields := make(map[string]json.RawMessage)
+	if data, err := os.ReadFile(configPath); err == nil {
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return fmt.Errorf("failed to parse config file: %v", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	completionDefaults, err := json.Marshal(c.CompletionDefaults)
+	if err != nil {
+		return fmt.Errorf("failed to marshal completion defaults: %v", err)
+	}
+	fields["completion_defaults"] = completionDefaults
+
+	data, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %v", err)
 	}
+	return writeConfigFile(configPath, data)
+}
 
+func (c *Config) Save() error {
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %v", err)
 	}
 
+	return writeConfigFile(getXDGConfigPath(), data)
+}
+
+func writeConfigFile(configPath string, data []byte) error {
+	// Ensure directory exists
+	dir := filepath.Dir(configPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+
 	if err := os.WriteFile(configPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write config file: %v", err)
 	}
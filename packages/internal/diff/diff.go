@@ -0,0 +1,292 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+type LineKind int
+
+const (
+	Context LineKind = iota
+	Added
+	Removed
+	HunkHeader
+)
+
+type Line struct {
+	Kind LineKind
+	// Line numbers are 1-based and 0 when the line does not exist on that side
+	OldLine int
+	NewLine int
+	Text    string
+}
+
+// Above this many edits the diff falls back to replacing the whole file
+const maxEditDistance = 4000
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opInsert
+	opDelete
+)
+
+type op struct {
+	kind   opKind
+	oldIdx int
+	newIdx int
+}
+
+func SplitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	return strings.Split(text, "\n")
+}
+
+func IsBinary(content string) bool {
+	sample := content
+	if len(sample) > 8000 {
+		sample = sample[:8000]
+	}
+	return strings.IndexByte(sample, 0) >= 0
+}
+
+// Unified returns the diff between oldText and newText as unified diff lines,
+// grouped into hunks with the given number of context lines around each change.
+func Unified(oldText, newText string, context int) []Line {
+	oldLines := SplitLines(oldText)
+	newLines := SplitLines(newText)
+	ops := editScript(oldLines, newLines)
+
+	var result []Line
+	i := 0
+	for i < len(ops) {
+		// Skip to the next change
+		if ops[i].kind == opEqual {
+			i++
+			continue
+		}
+
+		// Expand the hunk backwards by the context size
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		// Extend the hunk forward while changes are within 2*context of each other
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == opEqual {
+				run++
+			}
+			if run < len(ops) && run-end <= 2*context {
+				end = run
+				continue
+			}
+			end += context
+			if end > run {
+				end = run
+			}
+			break
+		}
+		if end > len(ops) {
+			end = len(ops)
+		}
+
+		result = append(result, hunk(ops[start:end], oldLines, newLines)...)
+		i = end
+	}
+
+	return result
+}
+
+func hunk(ops []op, oldLines, newLines []string) []Line {
+	oldStart, newStart := 0, 0
+	oldCount, newCount := 0, 0
+	var lines []Line
+
+	for _, o := range ops {
+		switch o.kind {
+		case opEqual:
+			if oldStart == 0 {
+				oldStart = o.oldIdx + 1
+			}
+			if newStart == 0 {
+				newStart = o.newIdx + 1
+			}
+			oldCount++
+			newCount++
+			lines = append(lines, Line{Kind: Context, OldLine: o.oldIdx + 1, NewLine: o.newIdx + 1, Text: oldLines[o.oldIdx]})
+		case opDelete:
+			if oldStart == 0 {
+				oldStart = o.oldIdx + 1
+			}
+			if newStart == 0 {
+				newStart = o.newIdx + 1
+			}
+			oldCount++
+			lines = append(lines, Line{Kind: Removed, OldLine: o.oldIdx + 1, Text: oldLines[o.oldIdx]})
+		case opInsert:
+			if oldStart == 0 {
+				oldStart = o.oldIdx + 1
+			}
+			if newStart == 0 {
+				newStart = o.newIdx + 1
+			}
+			newCount++
+			lines = append(lines, Line{Kind: Added, NewLine: o.newIdx + 1, Text: newLines[o.newIdx]})
+		}
+	}
+
+	// Unified diff convention: an empty side starts at the line before the hunk
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+
+	header := Line{
+		Kind: HunkHeader,
+		Text: fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart, oldCount, newStart, newCount),
+	}
+	return append([]Line{header}, lines...)
+}
+
+// editScript computes the shortest edit script between a and b using Myers' algorithm.
+// Each op carries the position it applies to in both sequences.
+func editScript(a, b []string) []op {
+	n, m := len(a), len(b)
+
+	// Trim common prefix and suffix to keep the search space small
+	prefix := 0
+	for prefix < n && prefix < m && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < n-prefix && suffix < m-prefix && a[n-1-suffix] == b[m-1-suffix] {
+		suffix++
+	}
+
+	middle := myers(a[prefix:n-suffix], b[prefix:m-suffix])
+
+	ops := make([]op, 0, prefix+len(middle)+suffix)
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, op{kind: opEqual, oldIdx: i, newIdx: i})
+	}
+	for _, o := range middle {
+		o.oldIdx += prefix
+		o.newIdx += prefix
+		ops = append(ops, o)
+	}
+	for i := 0; i < suffix; i++ {
+		ops = append(ops, op{kind: opEqual, oldIdx: n - suffix + i, newIdx: m - suffix + i})
+	}
+	return ops
+}
+
+func myers(a, b []string) []op {
+	n, m := len(a), len(b)
+	if n == 0 && m == 0 {
+		return nil
+	}
+
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+
+	found := false
+	for d := 0; d <= max && d <= maxEditDistance; d++ {
+		// Only the diagonals reachable from the previous round are needed for backtracking
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+
+	if !found {
+		return replaceAll(n, m)
+	}
+
+	var reversed []op
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		snapshot := trace[d]
+		at := func(k int) int { return snapshot[k+d+1] }
+
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, op{kind: opEqual, oldIdx: x, newIdx: y})
+		}
+
+		if d > 0 {
+			if x == prevX {
+				y--
+				reversed = append(reversed, op{kind: opInsert, oldIdx: x, newIdx: y})
+			} else {
+				x--
+				reversed = append(reversed, op{kind: opDelete, oldIdx: x, newIdx: y})
+			}
+		}
+	}
+
+	ops := make([]op, len(reversed))
+	for i, o := range reversed {
+		ops[len(reversed)-1-i] = o
+	}
+	return ops
+}
+
+func replaceAll(n, m int) []op {
+	ops := make([]op, 0, n+m)
+	for i := 0; i < n; i++ {
+		ops = append(ops, op{kind: opDelete, oldIdx: i, newIdx: 0})
+	}
+	for j := 0; j < m; j++ {
+		ops = append(ops, op{kind: opInsert, oldIdx: n, newIdx: j})
+	}
+	return ops
+}
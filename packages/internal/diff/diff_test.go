@@ -0,0 +1,171 @@
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// render writes diff lines the way a unified diff prints them
+func render(lines []Line) []string {
+	var rendered []string
+	for _, line := range lines {
+		switch line.Kind {
+		case HunkHeader:
+			rendered = append(rendered, line.Text)
+		case Added:
+			rendered = append(rendered, "+"+line.Text)
+		case Removed:
+			rendered = append(rendered, "-"+line.Text)
+		case Context:
+			rendered = append(rendered, " "+line.Text)
+		}
+	}
+	return rendered
+}
+
+// numbered returns count lines named prefix0, prefix1, ...
+func numbered(prefix string, count int) []string {
+	lines := make([]string, count)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%s%d", prefix, i)
+	}
+	return lines
+}
+
+func count(lines []Line, kind LineKind) int {
+	total := 0
+	for _, line := range lines {
+		if line.Kind == kind {
+			total++
+		}
+	}
+	return total
+}
+
+func TestUnifiedNoChanges(t *testing.T) {
+	if lines := Unified("a\nb\n", "a\nb\n", 3); lines != nil {
+		t.Errorf("identical files gave %q", render(lines))
+	}
+	// Line endings and a missing final newline are not changes
+	if lines := Unified("a\r\nb\r\n", "a\nb", 3); lines != nil {
+		t.Errorf("line ending change gave %q", render(lines))
+	}
+}
+
+func TestUnifiedEmptySide(t *testing.T) {
+	// An empty side starts at line 0, as git prints added and deleted files
+	added := render(Unified("", "x\ny\n", 3))
+	if want := []string{"@@ -0,0 +1,2 @@", "+x", "+y"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added file = %q, want %q", added, want)
+	}
+	deleted := render(Unified("x\ny\n", "", 3))
+	if want := []string{"@@ -1,2 +0,0 @@", "-x", "-y"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("deleted file = %q, want %q", deleted, want)
+	}
+	// A pure insertion keeps the old side anchored on the line before it
+	inserted := render(Unified("a\nb\n", "a\nnew\nb\n", 0))
+	if want := []string{"@@ -1,0 +2,1 @@", "+new"}; !reflect.DeepEqual(inserted, want) {
+		t.Errorf("insertion = %q, want %q", inserted, want)
+	}
+}
+
+// TestUnifiedHunkMerging checks the boundary where two changes stop sharing a hunk:
+// they do while the unchanged run between them is at most twice the context.
+func TestUnifiedHunkMerging(t *testing.T) {
+	old := numbered("l", 20)
+	for gap := 1; gap <= 6; gap++ {
+		changed := append([]string(nil), old...)
+		changed[5] = "first"
+		changed[5+gap+1] = "second"
+
+		lines := Unified(strings.Join(old, "\n"), strings.Join(changed, "\n"), 2)
+		hunks := count(lines, HunkHeader)
+		want := 1
+		if gap > 4 {
+			want = 2
+		}
+		if hunks != want {
+			t.Errorf("gap of %d lines gave %d hunks, want %d:\n%s", gap, hunks, want, strings.Join(render(lines), "\n"))
+		}
+	}
+}
+
+func TestUnifiedContextClippedAtFileEdges(t *testing.T) {
+	got := render(Unified("a\nb\nc\n", "A\nb\nC\n", 5))
+	want := []string{"@@ -1,3 +1,3 @@", "-a", "+A", " b", "-c", "+C"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unified() = %q, want %q", got, want)
+	}
+}
+
+func TestUnifiedLineNumbers(t *testing.T) {
+	got := Unified("a\nb\nc\n", "a\nx\nc\n", 1)
+	want := []Line{
+		{Kind: HunkHeader, Text: "@@ -1,3 +1,3 @@"},
+		{Kind: Context, OldLine: 1, NewLine: 1, Text: "a"},
+		{Kind: Removed, OldLine: 2, Text: "b"},
+		{Kind: Added, NewLine: 2, Text: "x"},
+		{Kind: Context, OldLine: 3, NewLine: 3, Text: "c"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unified() = %+v, want %+v", got, want)
+	}
+}
+
+// TestUnifiedEditDistanceCutoff checks both sides of maxEditDistance. The only shared
+// line sits between two blocks that are fully replaced, so it is kept as context only
+// while the search is allowed to run far enough to find it.
+func TestUnifiedEditDistanceCutoff(t *testing.T) {
+	for _, block := range []int{maxEditDistance / 2, maxEditDistance/2 + 1} {
+		oldText := strings.Join(append(numbered("old", block), "shared"), "\n")
+		newText := strings.Join(append([]string{"shared"}, numbered("new", block)...), "\n")
+
+		lines := Unified(oldText, newText, 3)
+		if removed, added := count(lines, Removed), count(lines, Added); removed+added < 2*block {
+			t.Fatalf("block of %d: %d removed and %d added lines", block, removed, added)
+		}
+
+		withinLimit := 2*block <= maxEditDistance
+		if kept := count(lines, Context) == 1; kept != withinLimit {
+			t.Errorf("block of %d: shared line kept as context = %v, want %v", block, kept, withinLimit)
+		}
+		if !withinLimit {
+			header := fmt.Sprintf("@@ -1,%d +1,%d @@", block+1, block+1)
+			if len(lines) != 2*block+3 || lines[0].Text != header {
+				t.Errorf("block of %d: fallback should replace the whole file under %q, got %q", block, header, lines[0].Text)
+			}
+		}
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"\n", []string{""}},
+		{"one\r\ntwo", []string{"one", "two"}},
+		{"one\n\n", []string{"one", ""}},
+	}
+	for _, test := range tests {
+		if got := SplitLines(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("SplitLines(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestIsBinary(t *testing.T) {
+	if IsBinary("plain text\n") {
+		t.Error("text reported as binary")
+	}
+	if !IsBinary("PNG\x00\x01") {
+		t.Error("NUL byte not reported as binary")
+	}
+	// Only the start of the file is sampled
+	if IsBinary(strings.Repeat("a", 8000) + "\x00") {
+		t.Error("NUL byte past the sample reported as binary")
+	}
+}
@@ -1,6 +1,8 @@
 package git
 
 import (
+	"fmt"
+	"os"
 	"os/exec"
 	"regexp"
 	"strings"
@@ -64,3 +66,40 @@ func IsGitRepository() bool {
 	err := cmd.Run()
 	return err == nil
 }
+
+// GetCurrentBranch returns the checked out branch name, or an empty string when HEAD is detached
+func GetCurrentBranch() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	branch := strings.TrimSpace(string(output))
+	if branch == "HEAD" {
+		return "", nil
+	}
+	return branch, nil
+}
+
+// PushBranch pushes the branch to origin and sets it as the upstream. It runs while the TUI
+// owns the terminal, so git and ssh are told never to prompt and fail instead when they
+// need credentials that are not cached.
+func PushBranch(branch string) error {
+	cmd := exec.Command("git", "push", "--set-upstream", "origin", branch)
+	sshCommand := os.Getenv("GIT_SSH_COMMAND")
+	if sshCommand == "" {
+		sshCommand = "ssh"
+	}
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GCM_INTERACTIVE=never",
+		"GIT_SSH_COMMAND="+sshCommand+" -o BatchMode=yes",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		// Keep git's explanation on one line so it fits in a status message
+		return fmt.Errorf("%v: %s (push from a terminal if git needs credentials)", err, strings.ReplaceAll(strings.TrimSpace(string(output)), "\n", "; "))
+	}
+	return nil
+}
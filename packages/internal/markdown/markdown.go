@@ -0,0 +1,195 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+type LineKind int
+
+const (
+	Text LineKind = iota
+	Heading
+	ListItem
+	Quote
+	Code
+	Rule
+	Blank
+)
+
+type Line struct {
+	Kind LineKind
+	// Heading level from 1 to 6, 0 for other kinds
+	Level int
+	// Display text, including any indentation and list bullet
+	Text string
+}
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	bulletPattern   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	numberedPattern = regexp.MustCompile(`^(\s*)(\d+[.)])\s+(.*)$`)
+	rulePattern     = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	linkPattern     = regexp.MustCompile(`!?\[([^\]]*)\]\(([^)]*)\)`)
+	commentPattern  = regexp.MustCompile(`(?s)<!--.*?-->`)
+	emphasisMarkers = strings.NewReplacer("**", "", "__", "", "`", "")
+)
+
+// Parse lays out markdown as terminal lines no wider than width. Block structure such as
+// headings, lists, quotes and code blocks is kept, inline markup is reduced to plain text
+// and HTML comments, which PR templates use for hints, are dropped.
+func Parse(text string, width int) []Line {
+	if width < 10 {
+		width = 10
+	}
+
+	text = commentPattern.ReplaceAllString(strings.ReplaceAll(text, "\r\n", "\n"), "")
+
+	var lines []Line
+	inCode := false
+	for _, raw := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(raw)
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			lines = append(lines, Line{Kind: Code, Text: "  " + truncate(strings.ReplaceAll(raw, "\t", "    "), width-2)})
+			continue
+		}
+
+		if trimmed == "" {
+			// Collapse runs of blank lines
+			if len(lines) > 0 && lines[len(lines)-1].Kind != Blank {
+				lines = append(lines, Line{Kind: Blank})
+			}
+			continue
+		}
+
+		if rulePattern.MatchString(trimmed) {
+			lines = append(lines, Line{Kind: Rule, Text: strings.Repeat("─", width)})
+			continue
+		}
+
+		if matches := headingPattern.FindStringSubmatch(trimmed); matches != nil {
+			for _, wrapped := range wrap(inline(matches[2]), width) {
+				lines = append(lines, Line{Kind: Heading, Level: len(matches[1]), Text: wrapped})
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, ">") {
+			quoted := strings.TrimSpace(strings.TrimLeft(trimmed, "> "))
+			for _, wrapped := range wrap(inline(quoted), width-2) {
+				lines = append(lines, Line{Kind: Quote, Text: "│ " + wrapped})
+			}
+			continue
+		}
+
+		if matches := bulletPattern.FindStringSubmatch(raw); matches != nil {
+			lines = append(lines, listItem(matches[1], bullet(matches[2]), width)...)
+			continue
+		}
+
+		if matches := numberedPattern.FindStringSubmatch(raw); matches != nil {
+			lines = append(lines, listItem(matches[1], matches[2]+" "+inline(matches[3]), width)...)
+			continue
+		}
+
+		for _, wrapped := range wrap(inline(trimmed), width) {
+			lines = append(lines, Line{Kind: Text, Text: wrapped})
+		}
+	}
+
+	// Drop trailing blank lines left by removed comments
+	for len(lines) > 0 && lines[len(lines)-1].Kind == Blank {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// bullet renders a list item's marker, turning task list checkboxes into check marks
+func bullet(item string) string {
+	switch {
+	case strings.HasPrefix(item, "[ ] "):
+		return "☐ " + inline(item[4:])
+	case strings.HasPrefix(item, "[x] "), strings.HasPrefix(item, "[X] "):
+		return "☑ " + inline(item[4:])
+	}
+	return "• " + inline(item)
+}
+
+// listItem wraps a list item, indenting continuation lines under the item text
+func listItem(indent string, item string, width int) []Line {
+	depth := len(strings.ReplaceAll(indent, "\t", "    ")) / 2
+	prefix := strings.Repeat("  ", depth)
+
+	// Continuation lines hang under the text after the marker
+	marker := strings.Index(item, " ") + 1
+	hanging := prefix + strings.Repeat(" ", len([]rune(item[:marker])))
+
+	var lines []Line
+	for i, wrapped := range wrap(item, width-len([]rune(prefix))) {
+		if i == 0 {
+			lines = append(lines, Line{Kind: ListItem, Text: prefix + wrapped})
+		} else {
+			lines = append(lines, Line{Kind: ListItem, Text: hanging + strings.TrimSpace(wrapped)})
+		}
+	}
+	return lines
+}
+
+// inline strips emphasis and code markers and shows links as their text
+func inline(text string) string {
+	text = linkPattern.ReplaceAllStringFunc(text, func(link string) string {
+		matches := linkPattern.FindStringSubmatch(link)
+		if matches[1] == "" {
+			return matches[2]
+		}
+		return matches[1]
+	})
+	return emphasisMarkers.Replace(text)
+}
+
+// wrap breaks text on spaces into lines of at most width runes, splitting longer words
+func wrap(text string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(text) {
+		for len([]rune(word)) > width {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			runes := []rune(word)
+			lines = append(lines, string(runes[:width]))
+			word = string(runes[width:])
+		}
+
+		if current == "" {
+			current = word
+		} else if len([]rune(current))+1+len([]rune(word)) <= width {
+			current += " " + word
+		} else {
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" || len(lines) == 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if width < 1 || len(runes) <= width {
+		return text
+	}
+	return string(runes[:width])
+}
@@ -0,0 +1,147 @@
+package markdown
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// texts returns the display text of each line
+func texts(lines []Line) []string {
+	var result []string
+	for _, line := range lines {
+		result = append(result, line.Text)
+	}
+	return result
+}
+
+// TestParseTemplate runs a typical PR template through, with the HTML comment hints
+// that templates carry and that must not show up in the description
+func TestParseTemplate(t *testing.T) {
+	template := "## Summary ##\r\n" +
+		"<!-- Describe the change.\r\n  Link the work item. -->\r\n" +
+		"\r\n" +
+		"## Checklist\r\n" +
+		"- [ ] Tests added\r\n" +
+		"- [x] Docs updated\r\n" +
+		"<!-- trailing hint -->\r\n\r\n"
+
+	want := []Line{
+		{Kind: Heading, Level: 2, Text: "Summary"},
+		{Kind: Blank},
+		{Kind: Heading, Level: 2, Text: "Checklist"},
+		{Kind: ListItem, Text: "☐ Tests added"},
+		{Kind: ListItem, Text: "☑ Docs updated"},
+	}
+	if got := Parse(template, 40); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseBlocks(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		width int
+		want  []string
+	}{
+		{
+			name:  "nested and numbered lists",
+			text:  "- one\n  * two\n\t- three\n1. first\n10) tenth",
+			width: 40,
+			want:  []string{"• one", "  • two", "    • three", "1. first", "10) tenth"},
+		},
+		{
+			name:  "wrapped items hang under the text",
+			text:  "- alpha beta gamma\n12. delta epsilon",
+			width: 12,
+			want:  []string{"• alpha beta", "  gamma", "12. delta", "    epsilon"},
+		},
+		{
+			name:  "quotes wrap inside the bar",
+			text:  "> > quoted **bold** text",
+			width: 12,
+			want:  []string{"│ quoted", "│ bold text"},
+		},
+		{
+			name:  "rules need three markers",
+			text:  "- - -\n--\n___",
+			width: 10,
+			want:  []string{strings.Repeat("─", 10), "--", strings.Repeat("─", 10)},
+		},
+		{
+			name:  "seven hashes are text",
+			text:  "####### deep",
+			width: 40,
+			want:  []string{"####### deep"},
+		},
+		{
+			name:  "width has a floor",
+			text:  "abcdefghijklmno",
+			width: 3,
+			want:  []string{"abcdefghij", "klmno"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := texts(Parse(test.text, test.width)); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Parse() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseCode(t *testing.T) {
+	text := "```go\n\tif **x** {\n# not a heading\n\n- not a list\n" + strings.Repeat("y", 30) + "\n```\n**after**"
+	want := []Line{
+		{Kind: Code, Text: "      if **x** {"},
+		{Kind: Code, Text: "  # not a heading"},
+		{Kind: Code, Text: "  "},
+		{Kind: Code, Text: "  - not a list"},
+		{Kind: Code, Text: "  " + strings.Repeat("y", 18)},
+		{Kind: Text, Text: "after"},
+	}
+	if got := Parse(text, 20); !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() = %+v, want %+v", got, want)
+	}
+
+	// An unclosed fence runs to the end rather than swallowing nothing
+	unclosed := Parse("~~~\nkeep *this*", 20)
+	if len(unclosed) != 1 || unclosed[0].Kind != Code || unclosed[0].Text != "  keep *this*" {
+		t.Errorf("unclosed fence = %+v", unclosed)
+	}
+}
+
+func TestInline(t *testing.T) {
+	tests := map[string]string{
+		"**bold** and __strong__":              "bold and strong",
+		"run `make test`":                      "run make test",
+		"see [the docs](https://example.com)":  "see the docs",
+		"![](diagram.png) and ![alt](pic.png)": "diagram.png and alt",
+		"[#12](../pulls/12), [#13](x)":         "#12, #13",
+		"*single* stays":                       "*single* stays",
+	}
+	for text, want := range tests {
+		if got := inline(text); got != want {
+			t.Errorf("inline(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	if got := wrap("", 10); !reflect.DeepEqual(got, []string{""}) {
+		t.Errorf("empty text wraps to %q", got)
+	}
+	if got := wrap("  spaced   out  ", 20); !reflect.DeepEqual(got, []string{"spaced out"}) {
+		t.Errorf("runs of spaces wrap to %q", got)
+	}
+	// Words longer than the width are split, the next word starts a new line
+	if got, want := wrap("a abcdefgh b", 3), []string{"a", "abc", "def", "gh", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("long word wraps to %q, want %q", got, want)
+	}
+	// Width is counted in runes, not bytes
+	if got, want := wrap("héllo wörld", 5), []string{"héllo", "wörld"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("accented text wraps to %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,167 @@
+package pipelineyaml
+
+import (
+	"strings"
+)
+
+type Parameter struct {
+	Name        string
+	DisplayName string
+	Type        string
+	Default     string
+	// Allowed values, empty when any value is accepted
+	Values []string
+}
+
+// Parameters reads the runtime parameters a pipeline declares. Only scalar fields are read,
+// so the defaults of object parameters are left empty.
+func Parameters(text string) []Parameter {
+	var parameters []Parameter
+	itemIndent := -1
+	inValues := false
+	for _, line := range block(text, "parameters") {
+		indent, trimmed := splitIndent(line)
+		if trimmed == "" {
+			continue
+		}
+
+		// Each list item starts a parameter, its fields follow one level deeper
+		if strings.HasPrefix(trimmed, "- ") && (itemIndent == -1 || indent == itemIndent) {
+			itemIndent = indent
+			parameters = append(parameters, Parameter{})
+			inValues = false
+			trimmed = strings.TrimSpace(trimmed[2:])
+			indent += 2
+		} else if itemIndent == -1 {
+			// The older form maps each parameter name to its default
+			if key, value, ok := keyValue(trimmed); ok {
+				parameters = append(parameters, Parameter{Name: key, Default: unquote(value)})
+			}
+			continue
+		}
+		if len(parameters) == 0 {
+			continue
+		}
+		parameter := &parameters[len(parameters)-1]
+
+		if inValues && strings.HasPrefix(trimmed, "- ") {
+			parameter.Values = append(parameter.Values, unquote(strings.TrimSpace(trimmed[2:])))
+			continue
+		}
+		if indent != itemIndent+2 {
+			// Nested under an object default
+			continue
+		}
+
+		key, value, ok := keyValue(trimmed)
+		if !ok {
+			continue
+		}
+		inValues = false
+		switch key {
+		case "name":
+			parameter.Name = unquote(value)
+		case "displayName":
+			parameter.DisplayName = unquote(value)
+		case "type":
+			parameter.Type = unquote(value)
+		case "default":
+			if value != "|" && value != ">" && !strings.HasPrefix(value, "{") && !strings.HasPrefix(value, "[") {
+				parameter.Default = unquote(value)
+			}
+		case "values":
+			inValues = value == ""
+			if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+				for _, allowed := range strings.Split(strings.Trim(value, "[]"), ",") {
+					parameter.Values = append(parameter.Values, unquote(strings.TrimSpace(allowed)))
+				}
+			}
+		}
+	}
+
+	// Booleans are picked from their two values like other restricted parameters
+	for i, parameter := range parameters {
+		if parameter.Type == "boolean" && len(parameter.Values) == 0 {
+			parameters[i].Values = []string{"true", "false"}
+		}
+	}
+	return parameters
+}
+
+// Stages returns the names of the pipeline's top-level stages
+func Stages(text string) []string {
+	var stages []string
+	listIndent := -1
+	for _, line := range block(text, "stages") {
+		indent, trimmed := splitIndent(line)
+		if !strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+		if listIndent == -1 {
+			listIndent = indent
+		}
+		if indent != listIndent {
+			continue
+		}
+		if key, value, ok := keyValue(strings.TrimSpace(trimmed[2:])); ok && key == "stage" && value != "" {
+			stages = append(stages, unquote(value))
+		}
+	}
+	return stages
+}
+
+// block returns the lines under a top-level key, up to the next top-level key
+func block(text string, key string) []string {
+	var lines []string
+	inBlock := false
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		indent, trimmed := splitIndent(line)
+		if trimmed == "" {
+			continue
+		}
+		if indent == 0 && !strings.HasPrefix(trimmed, "- ") {
+			if inBlock {
+				break
+			}
+			inBlock = trimmed == key+":"
+			continue
+		}
+		if inBlock {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// splitIndent returns a line's indentation and its text without a trailing comment
+func splitIndent(line string) (int, string) {
+	trimmed := strings.TrimLeft(line, " ")
+	indent := len(line) - len(trimmed)
+	if strings.HasPrefix(trimmed, "#") {
+		return indent, ""
+	}
+	// A # only starts a comment outside quotes
+	for i := 1; i < len(trimmed); i++ {
+		if trimmed[i] == '#' && trimmed[i-1] == ' ' &&
+			strings.Count(trimmed[:i], "\"")%2 == 0 && strings.Count(trimmed[:i], "'")%2 == 0 {
+			trimmed = trimmed[:i]
+			break
+		}
+	}
+	return indent, strings.TrimSpace(trimmed)
+}
+
+func keyValue(text string) (string, string, bool) {
+	colon := strings.Index(text, ":")
+	if colon <= 0 || strings.ContainsAny(text[:colon], " \"'") {
+		return "", "", false
+	}
+	return text[:colon], strings.TrimSpace(text[colon+1:]), true
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
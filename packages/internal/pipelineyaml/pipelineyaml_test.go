@@ -0,0 +1,191 @@
+package pipelineyaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParameters(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want []Parameter
+	}{
+		{
+			name: "list form",
+			yaml: `trigger:
+- main
+parameters:
+- name: environment
+  displayName: Target environment
+  type: string
+  default: staging
+  values:
+  - staging
+  - production
+- type: boolean
+  name: runTests
+  default: true
+steps:
+- script: echo hi
+`,
+			want: []Parameter{
+				{Name: "environment", DisplayName: "Target environment", Type: "string", Default: "staging", Values: []string{"staging", "production"}},
+				{Name: "runTests", Type: "boolean", Default: "true", Values: []string{"true", "false"}},
+			},
+		},
+		{
+			name: "older map form",
+			yaml: "parameters:\n  image: ubuntu-latest\n  retries: \"3\"\n  endpoint: http://host:8080/api\n",
+			want: []Parameter{
+				{Name: "image", Default: "ubuntu-latest"},
+				{Name: "retries", Default: "3"},
+				{Name: "endpoint", Default: "http://host:8080/api"},
+			},
+		},
+		{
+			name: "indented list with inline values",
+			yaml: `parameters:
+  - name: region
+    default: 'eu'
+    values: [eu, "us", 'ap']
+  - name: size
+    values:
+      - small
+      - large
+`,
+			want: []Parameter{
+				{Name: "region", Default: "eu", Values: []string{"eu", "us", "ap"}},
+				{Name: "size", Values: []string{"small", "large"}},
+			},
+		},
+		{
+			name: "comments, including a # inside quotes",
+			yaml: `# pipeline options
+parameters:
+- name: tag # the image tag
+  default: "v1 # not a comment"
+  # values are free form
+- name: channel
+  default: stable # picked by default
+`,
+			want: []Parameter{
+				{Name: "tag", Default: "v1 # not a comment"},
+				{Name: "channel", Default: "stable"},
+			},
+		},
+		{
+			// Only scalars are read, a nested key named like a field must not overwrite it
+			name: "object and multi-line defaults are skipped",
+			yaml: `parameters:
+- name: matrix
+  type: object
+  default:
+    linux:
+      name: ubuntu
+    windows:
+      name: windows
+- name: pools
+  type: object
+  default: [a, b]
+- name: settings
+  type: object
+  default: {retries: 2}
+- name: script
+  type: string
+  default: |
+    echo one
+- name: after
+  default: done
+`,
+			want: []Parameter{
+				{Name: "matrix", Type: "object"},
+				{Name: "pools", Type: "object"},
+				{Name: "settings", Type: "object"},
+				{Name: "script", Type: "string"},
+				{Name: "after", Default: "done"},
+			},
+		},
+		{
+			name: "template parameters under extends are not runtime parameters",
+			yaml: `extends:
+  template: base.yml
+  parameters:
+    image: ubuntu
+`,
+			want: nil,
+		},
+		{
+			name: "windows line endings",
+			yaml: "parameters:\r\n- name: debug\r\n  type: boolean\r\n  default: false\r\n",
+			want: []Parameter{
+				{Name: "debug", Type: "boolean", Default: "false", Values: []string{"true", "false"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Parameters(test.yaml); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Parameters() = %#v, want %#v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestStages(t *testing.T) {
+	yaml := `parameters:
+- name: env
+  default: dev
+stages:
+- stage: Build
+  jobs:
+  - job: compile
+    steps:
+    - script: make
+- template: stages/lint.yml
+- stage: 'Deploy'
+  dependsOn: Build
+  jobs:
+  - deployment: web
+    environment: prod
+# - stage: Disabled
+- stage: Verify # smoke tests
+variables:
+  stage: not-a-stage
+`
+	if got, want := Stages(yaml), []string{"Build", "Deploy", "Verify"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Stages() = %q, want %q", got, want)
+	}
+
+	// Nested stage-like items are at a deeper indent than the first stage
+	indented := "stages:\n  - stage: One\n    jobs:\n      - stage: NotAStage\n  - stage: Two\n"
+	if got, want := Stages(indented), []string{"One", "Two"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Stages() of an indented list = %q, want %q", got, want)
+	}
+
+	if got := Stages("jobs:\n- job: build\n"); got != nil {
+		t.Errorf("Stages() of a single-stage pipeline = %q, want none", got)
+	}
+}
+
+func TestSplitIndent(t *testing.T) {
+	tests := []struct {
+		line       string
+		wantIndent int
+		wantText   string
+	}{
+		{"", 0, ""},
+		{"    # comment", 4, ""},
+		{"  key: value # note", 2, "key: value"},
+		{"key: 'a # b'", 0, "key: 'a # b'"},
+		{"key: a#b", 0, "key: a#b"},
+	}
+
+	for _, test := range tests {
+		indent, text := splitIndent(test.line)
+		if indent != test.wantIndent || text != test.wantText {
+			t.Errorf("splitIndent(%q) = %d, %q, want %d, %q", test.line, indent, text, test.wantIndent, test.wantText)
+		}
+	}
+}
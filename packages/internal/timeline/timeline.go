@@ -0,0 +1,177 @@
+package timeline
+
+import (
+	"github.com/google/uuid"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/build"
+	"sort"
+	"time"
+)
+
+type Node struct {
+	Record   build.TimelineRecord
+	Children []*Node
+}
+
+type Row struct {
+	Node *Node
+	// Nesting level, 0 for top-level records such as stages
+	Depth int
+}
+
+// Results from worst to best, used to pick the result a parent shows for its children
+var resultRank = map[build.TaskResult]int{
+	build.TaskResultValues.Failed:              0,
+	build.TaskResultValues.Canceled:            1,
+	build.TaskResultValues.Abandoned:           2,
+	build.TaskResultValues.SucceededWithIssues: 3,
+	build.TaskResultValues.Succeeded:           4,
+	build.TaskResultValues.Skipped:             5,
+}
+
+// Build nests the records under their parents by ParentId and orders siblings by Order.
+// Phases are left out with their jobs moved up to the stage, as the web UI shows them.
+// Records whose parent is missing from the timeline are kept at the top level.
+func Build(records []build.TimelineRecord) []*Node {
+	nodes := make(map[uuid.UUID]*Node)
+	for _, record := range records {
+		if record.Id != nil {
+			nodes[*record.Id] = &Node{Record: record}
+		}
+	}
+
+	var roots []*Node
+	for _, record := range records {
+		if record.Id == nil {
+			continue
+		}
+		node := nodes[*record.Id]
+		if parent := visibleParent(record, nodes); parent != nil {
+			parent.Children = append(parent.Children, node)
+		} else if !isPhase(record) {
+			roots = append(roots, node)
+		}
+	}
+
+	sortNodes(roots)
+	return roots
+}
+
+// visibleParent returns the closest ancestor that is not a phase
+func visibleParent(record build.TimelineRecord, nodes map[uuid.UUID]*Node) *Node {
+	if isPhase(record) {
+		return nil
+	}
+	for record.ParentId != nil {
+		parent, ok := nodes[*record.ParentId]
+		if !ok {
+			return nil
+		}
+		if !isPhase(parent.Record) {
+			return parent
+		}
+		record = parent.Record
+	}
+	return nil
+}
+
+func isPhase(record build.TimelineRecord) bool {
+	return record.Type != nil && *record.Type == "Phase"
+}
+
+func sortNodes(nodes []*Node) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return order(nodes[i]) < order(nodes[j])
+	})
+	for _, node := range nodes {
+		sortNodes(node.Children)
+	}
+}
+
+func order(node *Node) int {
+	if node.Record.Order == nil {
+		return 0
+	}
+	return *node.Record.Order
+}
+
+// Flatten lists the nodes depth first, leaving out the children of nodes that are not expanded
+func Flatten(nodes []*Node, expanded func(*Node) bool) []Row {
+	var rows []Row
+	var walk func(nodes []*Node, depth int)
+	walk = func(nodes []*Node, depth int) {
+		for _, node := range nodes {
+			rows = append(rows, Row{Node: node, Depth: depth})
+			if len(node.Children) > 0 && expanded(node) {
+				walk(node.Children, depth+1)
+			}
+		}
+	}
+	walk(nodes, 0)
+	return rows
+}
+
+// Status returns the state and result of a node. A completed record reports its own result,
+// otherwise both are worked out from the children, with the worst finished result winning.
+func Status(node *Node) (build.TimelineRecordState, *build.TaskResult) {
+	record := node.Record
+	if record.State != nil && *record.State == build.TimelineRecordStateValues.Completed && record.Result != nil {
+		return *record.State, record.Result
+	}
+	if len(node.Children) == 0 {
+		state := build.TimelineRecordStateValues.Pending
+		if record.State != nil {
+			state = *record.State
+		}
+		return state, record.Result
+	}
+
+	completed := 0
+	started := false
+	var worst *build.TaskResult
+	for _, child := range node.Children {
+		state, result := Status(child)
+		if state != build.TimelineRecordStateValues.Pending {
+			started = true
+		}
+		if state == build.TimelineRecordStateValues.Completed {
+			completed++
+		}
+		if result != nil && (worst == nil || resultRank[*result] < resultRank[*worst]) {
+			worst = result
+		}
+	}
+
+	switch {
+	case completed == len(node.Children):
+		return build.TimelineRecordStateValues.Completed, worst
+	case started:
+		return build.TimelineRecordStateValues.InProgress, worst
+	}
+	return build.TimelineRecordStateValues.Pending, nil
+}
+
+// Progress counts the children that have completed
+func Progress(node *Node) (completed int, total int) {
+	for _, child := range node.Children {
+		if state, _ := Status(child); state == build.TimelineRecordStateValues.Completed {
+			completed++
+		}
+	}
+	return completed, len(node.Children)
+}
+
+// Duration returns how long the record ran, up to now while it is still running.
+// It is false for records that have not started.
+func Duration(record build.TimelineRecord, now time.Time) (time.Duration, bool) {
+	if record.StartTime == nil || record.StartTime.Time.IsZero() {
+		return 0, false
+	}
+	end := now
+	if record.FinishTime != nil && !record.FinishTime.Time.IsZero() {
+		end = record.FinishTime.Time
+	}
+	if end.Before(record.StartTime.Time) {
+		return 0, true
+	}
+	return end.Sub(record.StartTime.Time), true
+}
@@ -0,0 +1,200 @@
+package timeline
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/build"
+)
+
+// ids hands out a stable id per record name so records can name their parent
+var ids = map[string]uuid.UUID{}
+
+func id(name string) *uuid.UUID {
+	if _, ok := ids[name]; !ok {
+		ids[name] = uuid.New()
+	}
+	value := ids[name]
+	return &value
+}
+
+func record(name string, recordType string, parent string, order int) build.TimelineRecord {
+	r := build.TimelineRecord{Id: id(name), Name: &name, Type: &recordType, Order: &order}
+	if parent != "" {
+		r.ParentId = id(parent)
+	}
+	return r
+}
+
+func state(r build.TimelineRecord, value build.TimelineRecordState) build.TimelineRecord {
+	r.State = &value
+	return r
+}
+
+func finished(r build.TimelineRecord, result build.TaskResult) build.TimelineRecord {
+	r = state(r, build.TimelineRecordStateValues.Completed)
+	r.Result = &result
+	return r
+}
+
+func node(r build.TimelineRecord, children ...*Node) *Node {
+	return &Node{Record: r, Children: children}
+}
+
+// outline lists the rows as name@depth
+func outline(rows []Row) []string {
+	var names []string
+	for _, row := range rows {
+		names = append(names, fmt.Sprintf("%s@%d", *row.Node.Record.Name, row.Depth))
+	}
+	return names
+}
+
+func TestBuild(t *testing.T) {
+	noOrder := record("unordered", "Stage", "", 0)
+	noOrder.Order = nil
+	records := []build.TimelineRecord{
+		record("test", "Task", "job", 2),
+		record("Deploy", "Stage", "", 2),
+		record("job", "Job", "inner", 1),
+		record("checkout", "Task", "job", 1),
+		// Phases nest, both are skipped
+		record("inner", "Phase", "outer", 1),
+		record("outer", "Phase", "Build", 1),
+		record("Build", "Stage", "", 1),
+		// A job whose phase lost its stage moves to the top level, the phase does not
+		record("lonely", "Job", "stray", 3),
+		record("stray", "Phase", "gone", 1),
+		// A task whose parent is not in the timeline is kept
+		record("orphan", "Task", "missing", 4),
+		noOrder,
+		{Name: new(string)},
+	}
+
+	got := outline(Flatten(Build(records), func(*Node) bool { return true }))
+	want := []string{"unordered@0", "Build@0", "job@1", "checkout@2", "test@2", "Deploy@0", "lonely@0", "orphan@0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Build() = %v, want %v", got, want)
+	}
+}
+
+func TestFlattenCollapsed(t *testing.T) {
+	roots := Build([]build.TimelineRecord{
+		record("A", "Stage", "", 1),
+		record("a1", "Job", "A", 1),
+		record("a1.1", "Task", "a1", 1),
+		record("B", "Stage", "", 2),
+		record("b1", "Job", "B", 1),
+	})
+
+	expanded := map[string]bool{"A": true, "a1": false, "B": false}
+	got := outline(Flatten(roots, func(n *Node) bool { return expanded[*n.Record.Name] }))
+	if want := []string{"A@0", "a1@1", "B@0"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Flatten() = %v, want %v", got, want)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	completed := build.TimelineRecordStateValues.Completed
+	inProgress := build.TimelineRecordStateValues.InProgress
+	pending := build.TimelineRecordStateValues.Pending
+	succeeded := build.TaskResultValues.Succeeded
+	skipped := build.TaskResultValues.Skipped
+	canceled := build.TaskResultValues.Canceled
+	failed := build.TaskResultValues.Failed
+
+	tests := []struct {
+		name       string
+		node       *Node
+		wantState  build.TimelineRecordState
+		wantResult *build.TaskResult
+	}{
+		{
+			name:       "own result wins over the children",
+			node:       node(finished(record("s1", "Stage", "", 1), canceled), node(finished(record("j1", "Job", "s1", 1), failed))),
+			wantState:  completed,
+			wantResult: &canceled,
+		},
+		{
+			name:       "completed without a result falls back to the children",
+			node:       node(state(record("s2", "Stage", "", 1), completed), node(finished(record("j2", "Job", "s2", 1), skipped)), node(finished(record("j3", "Job", "s2", 2), succeeded))),
+			wantState:  completed,
+			wantResult: &succeeded,
+		},
+		{
+			name:       "failed ranks below canceled",
+			node:       node(record("s3", "Stage", "", 1), node(finished(record("j4", "Job", "s3", 1), canceled)), node(finished(record("j5", "Job", "s3", 2), failed))),
+			wantState:  completed,
+			wantResult: &failed,
+		},
+		{
+			name:       "one finished child puts the parent in progress",
+			node:       node(record("s4", "Stage", "", 1), node(finished(record("j6", "Job", "s4", 1), succeeded)), node(state(record("j7", "Job", "s4", 2), pending))),
+			wantState:  inProgress,
+			wantResult: &succeeded,
+		},
+		{
+			name:       "grandchild running",
+			node:       node(record("s5", "Stage", "", 1), node(record("j8", "Job", "s5", 1), node(state(record("t1", "Task", "j8", 1), inProgress)))),
+			wantState:  inProgress,
+			wantResult: nil,
+		},
+		{
+			name:       "leaf without state",
+			node:       node(record("t2", "Task", "", 1)),
+			wantState:  pending,
+			wantResult: nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state, result := Status(test.node)
+			if state != test.wantState || !reflect.DeepEqual(result, test.wantResult) {
+				t.Errorf("Status() = %v, %v, want %v, %v", state, result, test.wantState, test.wantResult)
+			}
+		})
+	}
+}
+
+func TestProgress(t *testing.T) {
+	// The middle job has no state of its own but all its tasks are done
+	stage := node(record("p", "Stage", "", 1),
+		node(finished(record("p1", "Job", "p", 1), build.TaskResultValues.Succeeded)),
+		node(record("p2", "Job", "p", 2), node(finished(record("p2.1", "Task", "p2", 1), build.TaskResultValues.Failed))),
+		node(state(record("p3", "Job", "p", 3), build.TimelineRecordStateValues.InProgress)),
+	)
+	if completed, total := Progress(stage); completed != 2 || total != 3 {
+		t.Errorf("Progress() = %d/%d, want 2/3", completed, total)
+	}
+	if completed, total := Progress(node(record("empty", "Stage", "", 1))); completed != 0 || total != 0 {
+		t.Errorf("Progress() of a stage without jobs = %d/%d, want 0/0", completed, total)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	now := start.Add(5 * time.Minute)
+	at := func(t time.Time) *azuredevops.Time { return &azuredevops.Time{Time: t} }
+
+	if _, ok := Duration(build.TimelineRecord{FinishTime: at(start)}, now); ok {
+		t.Error("record without a start time has a duration")
+	}
+	if _, ok := Duration(build.TimelineRecord{StartTime: at(time.Time{})}, now); ok {
+		t.Error("record with a zero start time has a duration")
+	}
+	if got, _ := Duration(build.TimelineRecord{StartTime: at(start), FinishTime: at(time.Time{})}, now); got != 5*time.Minute {
+		t.Errorf("running record lasted %v, want it measured up to now", got)
+	}
+	if got, _ := Duration(build.TimelineRecord{StartTime: at(start), FinishTime: at(start.Add(90 * time.Second))}, now); got != 90*time.Second {
+		t.Errorf("finished record lasted %v, want 1m30s", got)
+	}
+	// Agent clocks can put the finish before the start
+	if got, ok := Duration(build.TimelineRecord{StartTime: at(start), FinishTime: at(start.Add(-time.Second))}, now); got != 0 || !ok {
+		t.Errorf("skewed record = %v, %v, want 0, true", got, ok)
+	}
+}
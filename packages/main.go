@@ -3,25 +3,41 @@ package main
 import (
 	"aztui/packages/internal/api/identity"
 	"aztui/packages/internal/api/pipelines"
+	"aztui/packages/internal/api/policies"
 	"aztui/packages/internal/api/projects"
 	"aztui/packages/internal/api/prs"
 	"aztui/packages/internal/api/repos"
+	"aztui/packages/internal/api/workitems"
 	"aztui/packages/internal/autodetect"
 	"aztui/packages/internal/config"
+	"aztui/packages/internal/diff"
+	gitutil "aztui/packages/internal/git"
+	"aztui/packages/internal/markdown"
+	"aztui/packages/internal/pipelineyaml"
+	"aztui/packages/internal/timeline"
 	"context"
 	"fmt"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/build"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/core"
 	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/git"
-	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/graph"
 	pipeline "github.com/microsoft/azure-devops-go-api/azuredevops/v7/pipelines"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/pipelinesapproval"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/policy"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/webapi"
+	"github.com/microsoft/azure-devops-go-api/azuredevops/v7/workitemtracking"
 	"log"
 	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -47,6 +63,27 @@ var searchStyle = lipgloss.NewStyle().
 	BorderForeground(lipgloss.Color("12")).
 	Padding(0, 1)
 
+var diffAddedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))   // Green
+var diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("1")) // Red
+var diffHunkStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))    // Cyan
+var diffGutterStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+var diffThreadStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3")) // Yellow
+
+var diffRangeStyle = lipgloss.NewStyle().
+	Background(lipgloss.Color("237"))
+
+// Lines reserved under the diff for thread previews and the comment input
+const prDiffFooterLines = 4
+
+// Lines reserved above the run log for its status
+const runLogHeaderLines = 2
+
+// Branches listed at once in the run pipeline dialog
+const maxRunBranchLines = 8
+
+var logTimestampPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z `)
+var ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
 type projectLoadedMsg struct {
 	repos []git.GitRepository
 }
@@ -63,6 +100,38 @@ type timelineLoadedMsg struct {
 	timeline *build.Timeline
 }
 
+type runApprovalsLoadedMsg struct {
+	approvals []pipelinesapproval.Approval
+	err       error
+}
+
+type runOptionsLoadedMsg struct {
+	refName    string
+	branches   []git.GitRef // only fetched when the dialog opens
+	parameters []pipelineyaml.Parameter
+	variables  []pipelines.Variable
+	stages     []string
+	err        error
+}
+
+type runQueuedMsg struct {
+	run *pipeline.Run
+	err error
+}
+
+type runActionCompleteMsg struct {
+	action string // cancel, retry or rerun
+	runId  int    // the run queued by a rerun
+	err    error
+}
+
+type runLogLoadedMsg struct {
+	logID     int
+	startLine int // lines already shown when the fetch started
+	lines     []string
+	err       error
+}
+
 type refreshMsg struct{}
 
 type autoRefreshMsg struct{}
@@ -75,20 +144,54 @@ type autoDetectCompleteMsg struct {
 
 type prsLoadedMsg struct {
 	prs []git.GitPullRequest
+	err error
 }
 
 type branchesLoadedMsg struct {
 	branches []git.GitRef
 }
 
+type latestBranchLoadedMsg struct {
+	branch *git.GitRef
+}
+
+type localBranchLoadedMsg struct {
+	branch string
+}
+
+type branchPushedMsg struct {
+	branch string
+	err    error
+}
+
 type usersLoadedMsg struct {
-	users []graph.GraphUser
+	users []identity.Reviewer
+}
+
+type currentUserLoadedMsg struct {
+	user *webapi.IdentityRef
 }
 
 type prCreatedMsg struct {
 	pr *git.GitPullRequest
 }
 
+type prTemplateLoadedMsg struct {
+	targetRefName string
+	template      string
+}
+
+type prCommitsLoadedMsg struct {
+	sourceRefName string
+	targetRefName string
+	commits       []git.GitCommitRef
+}
+
+type prDescEditedMsg struct {
+	path string
+	err  error
+}
+
 type prDetailsLoadedMsg struct {
 	pr *git.GitPullRequest
 }
@@ -97,12 +200,51 @@ type prCommentsLoadedMsg struct {
 	comments []git.GitPullRequestCommentThread
 }
 
+type dashboardPRsLoadedMsg struct {
+	prs []git.GitPullRequest
+}
+
+type workItemsSearchedMsg struct {
+	query string
+	items []workitemtracking.WorkItem
+}
+
+type prWorkItemsLoadedMsg struct {
+	items []workitemtracking.WorkItem
+}
+
+type prPoliciesLoadedMsg struct {
+	evaluations []policy.PolicyEvaluationRecord
+}
+
+type prIterationsLoadedMsg struct {
+	iterations []git.GitPullRequestIteration
+}
+
+type prFilesLoadedMsg struct {
+	files []prs.PRFileChange
+}
+
+type prDiffLoadedMsg struct {
+	path   string
+	lines  []diff.Line
+	binary bool
+	err    error
+}
+
 type prActionCompleteMsg struct {
 	action  string
 	success bool
 	message string
 }
 
+type prCherryPickStatusMsg struct {
+	id     int
+	status git.GitAsyncOperationStatus
+	detail *git.GitAsyncRefOperationDetail
+	err    error
+}
+
 type repoOption struct {
 	name string
 	desc string
@@ -116,7 +258,8 @@ type model struct {
 	timeline         *build.Timeline
 	prs              []git.GitPullRequest
 	branches         []git.GitRef
-	users            []graph.GraphUser
+	users            []identity.Reviewer // users and groups that can review PRs
+	currentUser      *webapi.IdentityRef
 	showRepoOptions  bool
 	showPipelines    bool
 	showRuns         bool
@@ -132,6 +275,7 @@ type model struct {
 	pipelinesScroll  int
 	runsScroll       int
 	timelineScroll   int
+	timelineExpanded map[uuid.UUID]bool // expand and collapse choices, other records use timelineDefaultExpanded
 	prsScroll        int
 	selectedProject  *core.TeamProjectReference
 	selectedRepo     *git.GitRepository
@@ -170,17 +314,29 @@ type model struct {
 	// PR Creation Modal fields
 	prCreateMode      bool
 	prTitleInput      textinput.Model
-	prDescInput       textinput.Model
+	prDescInput       textarea.Model
+	prDescTemplate    string // template last filled into the description, replaced while left unedited
 	prSourceBranch    *git.GitRef
 	prTargetBranch    *git.GitRef
-	prReviewers       []graph.GraphUser
+	prDefaultSource   string // ref to preselect as the source once branches are loaded
+	prDefaultTarget   string // ref to preselect as the target, empty for the repository default
+	prLocalBranch     string // checked out branch when the selected repo is the local checkout
+	pushingBranch     bool
+	prCommits         []git.GitCommitRef // commits between the source and target branches, newest first
+	loadingPRCommits  bool
+	prGeneratedTitle  string // suggestions last filled from the commits, replaced while left unedited
+	prGeneratedDesc   string
+	prReviewers       []git.IdentityRefWithVote
 	prCreateStep      int
 	reviewerSearch    string
-	filteredReviewers []graph.GraphUser
+	filteredReviewers []identity.Reviewer
+	prCreateDraft     bool
+	prWorkItems       []workitemtracking.WorkItem
 	// PR Details fields
 	showPRDetails     bool
 	prDetails         *git.GitPullRequest
 	prComments        []git.GitPullRequestCommentThread
+	prPolicies        []policy.PolicyEvaluationRecord
 	loadingPRDetails  bool
 	loadingPRComments bool
 	prDetailsSpinner  spinner.Model
@@ -193,13 +349,195 @@ type model struct {
 	prOverrideMode  bool
 	prActionMessage string
 	prActionTime    time.Time
+	// PR Diff fields
+	showPRFiles    bool
+	showPRDiff     bool
+	prFiles        []prs.PRFileChange
+	prFilesScroll  int
+	selectedPRFile *prs.PRFileChange
+	prDiffLines    []diff.Line
+	prDiffBinary   bool
+	prDiffError    string
+	prDiffScroll   int
+	prDiffAnchor   int // start of a line range selection, -1 when none
+	loadingPRFiles bool
+	loadingPRDiff  bool
+	// PR Iteration fields
+	showPRIterations      bool
+	prIterations          []git.GitPullRequestIteration // newest first
+	loadingPRIterations   bool
+	prIterationBase       int  // iteration marked to compare against, 0 when none
+	prFilesIteration      int  // iteration the file list shows, 0 for the latest
+	prFilesCompareTo      int  // iteration the file list is compared to, 0 for the merge base
+	prFilesFromIterations bool // the file list was opened from the iteration list
+	// PR Thread fields
+	showPRThreads        bool
+	prThreadsScroll      int
+	prReplyMode          bool
+	prReplyThreadId      int // 0 starts a new thread
+	prReplyInline        bool
+	prThreadStatusMode   bool
+	prThreadStatusCursor int
+	prVoteMode           bool
+	prVoteCursor         int
+	prAbandonConfirm     bool
+	// PR Completion fields
+	prCompleteMode         bool
+	prCompleteStep         int // 0: strategy, 1: message, 2: delete branch, 3: work items, 4: save defaults
+	prMergeStrategy        int
+	prMergeMessageInput    textinput.Model
+	prDeleteSourceBranch   bool
+	prTransitionWorkItems  bool
+	prSaveCompleteDefaults bool
+	prCompleteAuto         bool   // the dialog sets auto-complete instead of completing now
+	prCompleteBypass       string // override reason, the dialog bypasses policies when set
+	// PR List Filter fields
+	prStatusFilter   int // index into prStatusFilters
+	prFilterMine     bool
+	prFilterReviewer bool
+	prFilterTarget   string // target branch ref name, empty for any
+	prLabelFilter    string
+	prLabelOptions   []string // labels seen on the listed PRs, cycled through by the label filter
+	prsError         string   // why the PR list failed to load
+	// My PRs Dashboard fields
+	showDashboard    bool
+	dashboardPRs     []git.GitPullRequest // ordered by dashboard group
+	loadingDashboard bool
+	prFromDashboard  bool     // PR details was opened from the dashboard
+	dashboardScope   []string // projects the dashboard was opened on, opening a PR changes the selected one
+	// Work Item fields
+	workItemSearch     string
+	workItemSearched   string // query of the current results, Enter searches again when they differ
+	workItemResults    []workitemtracking.WorkItem
+	searchingWorkItems bool
+	prLinkedWorkItems  []workitemtracking.WorkItem
+	prLinkWorkItemMode bool
+	// PR Reviewer fields
+	prReviewerMode          bool   // editing the reviewers of an existing PR
+	prReviewerRemoveConfirm string // required reviewer waiting for a second Ctrl+D to be removed
+	// PR Label fields
+	prLabelMode  bool
+	prLabelInput string
+	// PR Cherry-pick fields
+	prCherryPickMode    bool // choosing the branch to cherry-pick or revert a completed PR onto
+	prCherryPickRevert  bool
+	prCherryPickFilter  string
+	prCherryPickRunning bool
+	prCherryPickID      int // operation last started, 0 until the server accepts it
+	prCherryPickStatus  git.GitAsyncOperationStatus
+	prCherryPickError   string
+	prCherryPickOnto    string
+	prCherryPickRef     string          // branch the operation writes its commits to
+	prCherryPickCreated map[string]bool // branches created this session, the branch list only has them once reloaded
+	// Run Log fields
+	showRunLog     bool
+	runLogRecordId uuid.UUID // timeline record whose log is shown
+	runLogName     string
+	runLogId       int // 0 until the step has written a log
+	runLogLines    []string
+	loadingRunLog  bool
+	runLogScroll   int
+	runLogFollow   bool // keep the newest lines in view as they arrive
+	runLogTailing  bool // the step is still running, so new lines are fetched on refresh
+	// Run Pipeline fields
+	showRunPipeline    bool
+	runPipelineStep    int // 0 branch, 1 parameters, 2 variables, 3 stages to skip
+	loadingRunOptions  bool
+	runBranches        []git.GitRef
+	runBranchFilter    string
+	runBranch          string
+	runParameters      []pipelineyaml.Parameter
+	runParameterValues map[string]string
+	runVariables       []pipelines.Variable
+	runVariableValues  map[string]string
+	runStages          []string
+	runSkipStages      map[string]bool
+	queueingRun        bool
+	queuedRunId        int // run to open once the runs list has loaded
+	runPipelineError   string
+	// Run Action fields
+	runCancelConfirm bool
+	runActionMessage string
+	runActionTime    time.Time
+	// Run Approval fields
+	showRunApprovals  bool
+	runApprovals      map[uuid.UUID]pipelinesapproval.Approval
+	runApprovalMode   bool // typing the comment for an approve or reject
+	runApprovalReject bool
+	runApprovalInput  textinput.Model
+}
+
+type mergeStrategyOption struct {
+	strategy git.GitPullRequestMergeStrategy
+	name     string
+	// Key used for per-repo defaults in the config file
+	configKey string
+}
+
+var mergeStrategyOptions = []mergeStrategyOption{
+	{strategy: git.GitPullRequestMergeStrategyValues.NoFastForward, name: "Merge commit", configKey: "merge"},
+	{strategy: git.GitPullRequestMergeStrategyValues.Squash, name: "Squash", configKey: "squash"},
+	{strategy: git.GitPullRequestMergeStrategyValues.Rebase, name: "Rebase and fast-forward", configKey: "rebase"},
+	{strategy: git.GitPullRequestMergeStrategyValues.RebaseMerge, name: "Semi-linear merge", configKey: "rebaseMerge"},
+}
+
+const prCompleteSteps = 5
+
+// PR create form steps: 0 title, 1 description, 2 source branch, 3 target branch, 4 reviewers, 5 work items, 6 draft
+const prCreateSteps = 7
+
+type voteOption struct {
+	vote  int
+	name  string
+	color lipgloss.Color
+}
+
+var voteOptions = []voteOption{
+	{vote: 10, name: "Approve", color: lipgloss.Color("2")},
+	{vote: 5, name: "Approve with suggestions", color: lipgloss.Color("2")},
+	{vote: 0, name: "No vote", color: lipgloss.Color("240")},
+	{vote: -5, name: "Wait for author", color: lipgloss.Color("3")},
+	{vote: -10, name: "Reject", color: lipgloss.Color("1")},
+}
+
+func voteLabel(vote int) string {
+	for _, option := range voteOptions {
+		if option.vote == vote {
+			return option.name
+		}
+	}
+	return fmt.Sprintf("%d", vote)
+}
+
+var prStatusFilters = []git.PullRequestStatus{
+	git.PullRequestStatusValues.Active,
+	git.PullRequestStatusValues.Completed,
+	git.PullRequestStatusValues.Abandoned,
+	git.PullRequestStatusValues.All,
+}
+
+// Dashboard groups, in display order
+const (
+	dashboardNeedsVote = iota
+	dashboardWaiting
+	dashboardReady
+)
+
+var dashboardGroupNames = []string{"Needs my vote", "Waiting on others", "Mine, ready to complete"}
+
+var threadStatusOptions = []git.CommentThreadStatus{
+	git.CommentThreadStatusValues.Active,
+	git.CommentThreadStatusValues.Fixed,
+	git.CommentThreadStatusValues.WontFix,
+	git.CommentThreadStatusValues.Closed,
+	git.CommentThreadStatusValues.ByDesign,
 }
 
 func (m model) Init() tea.Cmd {
 	if m.showConfigModal {
 		return m.configModal.Init()
 	}
-	return tea.Batch(m.projectsSpinner.Tick, loadProjects(m.config), autoDetectProjectAndRepo(m.config))
+	return tea.Batch(m.projectsSpinner.Tick, loadProjects(m.config), autoDetectProjectAndRepo(m.config), loadCurrentUser(m.config))
 }
 
 func loadProjects(cfg *config.Config) tea.Cmd {
@@ -279,14 +617,89 @@ func loadRunTimeline(projectName string, buildID int, cfg *config.Config) tea.Cm
 	}
 }
 
-func loadRepoPRs(projectName string, repoID string, cfg *config.Config) tea.Cmd {
+func loadRunApprovals(projectName string, approvalIDs []uuid.UUID, cfg *config.Config) tea.Cmd {
 	return func() tea.Msg {
 		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
 		ctx := context.Background()
 
-		prsList, err := prs.GetPRs(ctx, connection, projectName, repoID)
+		approvals, err := pipelines.GetApprovals(ctx, connection, projectName, approvalIDs)
 		if err != nil {
-			log.Fatal(err)
+			log.Printf("Error getting approvals: %v", err)
+			return runApprovalsLoadedMsg{err: err}
+		}
+		return runApprovalsLoadedMsg{approvals: *approvals}
+	}
+}
+
+// loadRunOptions reads what can be set when queueing the pipeline on a branch. With no
+// branch it uses the pipeline's default branch and also lists the branches to pick from.
+func loadRunOptions(projectName string, pipelineID int, refName string, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
+		ctx := context.Background()
+
+		definition, err := pipelines.GetDefinition(ctx, connection, projectName, pipelineID)
+		if err != nil {
+			log.Printf("Error getting pipeline definition: %v", err)
+			return runOptionsLoadedMsg{refName: refName, err: err}
+		}
+
+		msg := runOptionsLoadedMsg{refName: refName, variables: pipelines.QueueVariables(*definition)}
+		if refName == "" && definition.Repository != nil {
+			if definition.Repository.DefaultBranch != nil {
+				msg.refName = *definition.Repository.DefaultBranch
+			}
+			if definition.Repository.Id != nil && definition.Repository.Type != nil && *definition.Repository.Type == "TfsGit" {
+				branches, err := prs.GetBranches(ctx, connection, projectName, *definition.Repository.Id)
+				if err != nil {
+					log.Printf("Error getting pipeline branches: %v", err)
+				} else {
+					msg.branches = *branches
+				}
+			}
+		}
+
+		yaml, err := pipelines.GetPipelineYaml(ctx, connection, projectName, *definition, msg.refName)
+		if err != nil {
+			log.Printf("Error getting pipeline YAML: %v", err)
+		}
+		msg.parameters = pipelineyaml.Parameters(yaml)
+
+		// Templates can add stages, so read them from the expanded YAML when it can be previewed
+		finalYaml, err := pipelines.PreviewPipeline(ctx, connection, projectName, pipelineID, msg.refName)
+		if err != nil {
+			log.Printf("Error previewing pipeline: %v", err)
+			finalYaml = yaml
+		}
+		msg.stages = pipelineyaml.Stages(finalYaml)
+		return msg
+	}
+}
+
+// loadRunLog fetches the lines of a step log after the ones already shown
+func loadRunLog(projectName string, buildID int, logID int, startLine int, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
+		ctx := context.Background()
+
+		lines, err := pipelines.GetLogLines(ctx, connection, projectName, buildID, logID, startLine+1)
+		if err != nil {
+			log.Printf("Error getting log lines: %v", err)
+			return runLogLoadedMsg{logID: logID, startLine: startLine, err: err}
+		}
+		return runLogLoadedMsg{logID: logID, startLine: startLine, lines: *lines}
+	}
+}
+
+func loadRepoPRs(projectName string, repoID string, filter prs.PRFilter, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
+		ctx := context.Background()
+
+		prsList, err := prs.GetPRs(ctx, connection, projectName, repoID, filter)
+		if err != nil {
+			log.Printf("Error getting PRs: %v", err)
+			return prsLoadedMsg{err: err}
 		}
 		return prsLoadedMsg{prs: *prsList}
 	}
@@ -310,14 +723,35 @@ func loadOrgUsers(cfg *config.Config) tea.Cmd {
 		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
 		ctx := context.Background()
 
-		usersList, err := identity.GetUsers(ctx, connection)
+		users, err := identity.GetUserReviewers(ctx, connection)
 		if err != nil {
-			log.Fatal(err)
+			log.Printf("Error getting users: %v", err)
+			users = &[]identity.Reviewer{}
+		}
+
+		// Groups need graph access the token may not have, so users are still offered without them
+		groups, err := identity.GetGroupReviewers(ctx, connection)
+		if err != nil {
+			log.Printf("Error getting groups: %v", err)
+			return usersLoadedMsg{users: *users}
 		}
-		if usersList.GraphUsers != nil {
-			return usersLoadedMsg{users: *usersList.GraphUsers}
+
+		return usersLoadedMsg{users: append(*users, *groups...)}
+	}
+}
+
+func loadCurrentUser(cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
+		ctx := context.Background()
+
+		user, err := identity.GetCurrentUser(ctx, connection)
+		if err != nil {
+			log.Printf("Error getting current user: %v", err)
+			return currentUserLoadedMsg{user: nil}
 		}
-		return usersLoadedMsg{users: []graph.GraphUser{}}
+
+		return currentUserLoadedMsg{user: user}
 	}
 }
 
@@ -332,12 +766,58 @@ func loadLatestBranch(projectName string, repoID string, cfg *config.Config) tea
 			return nil
 		}
 
-		if branch != nil {
-			return branchesLoadedMsg{branches: []git.GitRef{*branch}}
+		return latestBranchLoadedMsg{branch: branch}
+	}
+}
+
+func loadLocalBranch() tea.Cmd {
+	return func() tea.Msg {
+		branch, err := gitutil.GetCurrentBranch()
+		if err != nil {
+			log.Printf("Error getting local branch: %v", err)
+		}
+		return localBranchLoadedMsg{branch: branch}
+	}
+}
+
+func pushBranch(branch string) tea.Cmd {
+	return func() tea.Msg {
+		return branchPushedMsg{branch: branch, err: gitutil.PushBranch(branch)}
+	}
+}
+
+// isLocalRepo reports whether the selected repo is the one checked out in the working directory
+func (m model) isLocalRepo() bool {
+	return m.autoDetectResult != nil && m.autoDetectResult.ShouldAutoLoad && m.autoDetectResult.Repository != nil &&
+		m.autoDetectResult.Repository.Id != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil &&
+		*m.autoDetectResult.Repository.Id == *m.selectedRepo.Id
+}
+
+func (m model) findBranch(refName string) *git.GitRef {
+	for i, branch := range m.branches {
+		if branch.Name != nil && *branch.Name == refName {
+			return &m.branches[i]
 		}
+	}
+	return nil
+}
 
+// selectDefaultSource preselects the default source branch once both it and the branch list are known
+func (m *model) selectDefaultSource() tea.Cmd {
+	if m.prSourceBranch != nil || m.prDefaultSource == "" || m.loadingBranches {
+		return nil
+	}
+	m.prSourceBranch = m.findBranch(m.prDefaultSource)
+	if m.prSourceBranch == nil {
 		return nil
 	}
+	m.loadingPRCommits = true
+	return m.loadBranchCommits()
+}
+
+// localBranchUnpushed reports whether the checked out branch does not exist on the server yet
+func (m model) localBranchUnpushed() bool {
+	return m.prLocalBranch != "" && !m.loadingBranches && m.findBranch("refs/heads/"+m.prLocalBranch) == nil
 }
 
 func loadPRDetails(projectName string, repoID string, prID int, cfg *config.Config) tea.Cmd {
@@ -355,1523 +835,6180 @@ func loadPRDetails(projectName string, repoID string, prID int, cfg *config.Conf
 	}
 }
 
-func loadPRComments(projectName string, repoID string, prID int, cfg *config.Config) tea.Cmd {
+// loadDashboardPRs loads active PRs the user created or reviews across the given projects
+func loadDashboardPRs(projectNames []string, userID string, cfg *config.Config) tea.Cmd {
 	return func() tea.Msg {
 		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
 		ctx := context.Background()
 
-		comments, err := prs.GetPRComments(ctx, connection, projectName, repoID, prID)
-		if err != nil {
-			log.Printf("Error getting PR comments: %v", err)
-			return prCommentsLoadedMsg{comments: []git.GitPullRequestCommentThread{}}
+		var dashboardPRs []git.GitPullRequest
+		seen := make(map[int]bool)
+		for _, projectName := range projectNames {
+			for _, filter := range []prs.PRFilter{{CreatorId: userID}, {ReviewerId: userID}} {
+				prsList, err := prs.GetProjectPRs(ctx, connection, projectName, filter)
+				if err != nil {
+					log.Printf("Error getting PRs for project %s: %v", projectName, err)
+					continue
+				}
+				for _, pr := range *prsList {
+					if pr.PullRequestId == nil || seen[*pr.PullRequestId] {
+						continue
+					}
+					seen[*pr.PullRequestId] = true
+					dashboardPRs = append(dashboardPRs, pr)
+				}
+			}
 		}
 
-		if comments != nil {
-			return prCommentsLoadedMsg{comments: *comments}
-		}
+		sort.SliceStable(dashboardPRs, func(i, j int) bool {
+			return dashboardGroup(dashboardPRs[i], userID) < dashboardGroup(dashboardPRs[j], userID)
+		})
 
-		return prCommentsLoadedMsg{comments: []git.GitPullRequestCommentThread{}}
+		return dashboardPRsLoadedMsg{prs: dashboardPRs}
 	}
 }
 
-func (m model) approvePR(vote int, comment string) tea.Cmd {
-	return func() tea.Msg {
-		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
-			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
-			return prActionCompleteMsg{action: "approve", success: false}
-		}
+// dashboardGroup sorts a PR into the dashboard group it belongs to for the given user
+func dashboardGroup(pr git.GitPullRequest, userID string) int {
+	isMine := pr.CreatedBy != nil && pr.CreatedBy.Id != nil && strings.EqualFold(*pr.CreatedBy.Id, userID)
 
-		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
-		ctx := context.Background()
+	if !isMine {
+		if pr.Reviewers != nil {
+			for _, reviewer := range *pr.Reviewers {
+				if reviewer.Id != nil && strings.EqualFold(*reviewer.Id, userID) && (reviewer.Vote == nil || *reviewer.Vote == 0) {
+					return dashboardNeedsVote
+				}
+			}
+		}
+		return dashboardWaiting
+	}
 
-		repoID := m.selectedRepo.Id.String()
-		prID := *m.selectedPR.PullRequestId
+	if pr.IsDraft != nil && *pr.IsDraft {
+		return dashboardWaiting
+	}
 
-		err := prs.ApprovePR(ctx, connection, *m.selectedProject.Name, repoID, prID, vote, comment)
-		if err != nil {
-			log.Printf("Error approving PR: %v", err)
-			return prActionCompleteMsg{action: "approve", success: false, message: fmt.Sprintf("Failed to approve PR: %v", err)}
+	// Ready when nobody objects, every required reviewer approved and someone approved
+	approved := false
+	if pr.Reviewers != nil {
+		for _, reviewer := range *pr.Reviewers {
+			vote := 0
+			if reviewer.Vote != nil {
+				vote = *reviewer.Vote
+			}
+			if vote < 0 {
+				return dashboardWaiting
+			}
+			if reviewer.IsRequired != nil && *reviewer.IsRequired && vote < 5 {
+				return dashboardWaiting
+			}
+			if vote >= 5 {
+				approved = true
+			}
 		}
+	}
+	if !approved {
+		return dashboardWaiting
+	}
+	return dashboardReady
+}
 
-		var actionText string
-		if vote == 10 {
-			actionText = "Your approval vote submitted successfully"
-		} else if vote == -10 {
-			actionText = "Your rejection vote submitted successfully"
-		} else {
-			actionText = "Your vote submitted successfully"
+// dashboardProjects returns the selected project, or every project when none is selected
+func (m model) dashboardProjects() []string {
+	if m.selectedProject != nil && m.selectedProject.Name != nil {
+		return []string{*m.selectedProject.Name}
+	}
+	var projectNames []string
+	for _, project := range m.projects {
+		if project.Name != nil {
+			projectNames = append(projectNames, *project.Name)
 		}
-		return prActionCompleteMsg{action: "approve", success: true, message: actionText}
 	}
+	return projectNames
 }
 
-func (m model) completePR() tea.Cmd {
+func searchWorkItems(projectName string, query string, cfg *config.Config) tea.Cmd {
 	return func() tea.Msg {
-		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
-			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
-			return prActionCompleteMsg{action: "complete", success: false, message: "Failed to complete PR: missing project, repo, or PR details"}
-		}
-
-		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
 		ctx := context.Background()
 
-		repoID := m.selectedRepo.Id.String()
-		prID := *m.selectedPR.PullRequestId
-
-		// Set default completion options
-		mergeMessage := "Merge pull request #" + fmt.Sprintf("%d", prID)
-		deleteSourceBranch := true
-		completionOptions := &git.GitPullRequestCompletionOptions{
-			MergeCommitMessage: &mergeMessage,
-			DeleteSourceBranch: &deleteSourceBranch,
-			MergeStrategy:      &git.GitPullRequestMergeStrategyValues.Squash, // Default to squash merge
-		}
-
-		_, err := prs.CompletePR(ctx, connection, *m.selectedProject.Name, repoID, prID, completionOptions)
+		items, err := workitems.SearchWorkItems(ctx, connection, projectName, query)
 		if err != nil {
-			log.Printf("Error completing PR: %v", err)
-			return prActionCompleteMsg{action: "complete", success: false, message: fmt.Sprintf("Failed to complete PR: %v", err)}
+			log.Printf("Error searching work items: %v", err)
+			return workItemsSearchedMsg{query: query, items: []workitemtracking.WorkItem{}}
 		}
 
-		return prActionCompleteMsg{action: "complete", success: true, message: "PR completed successfully"}
+		return workItemsSearchedMsg{query: query, items: *items}
 	}
 }
 
-func (m model) overridePR(overrideMessage string) tea.Cmd {
+func loadPRWorkItems(projectName string, repoID string, prID int, cfg *config.Config) tea.Cmd {
 	return func() tea.Msg {
-		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
-			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
-			return prActionCompleteMsg{action: "override", success: false}
-		}
-
-		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
 		ctx := context.Background()
 
-		repoID := m.selectedRepo.Id.String()
-		prID := *m.selectedPR.PullRequestId
+		refs, err := prs.GetPRWorkItemRefs(ctx, connection, projectName, repoID, prID)
+		if err != nil {
+			log.Printf("Error getting PR work items: %v", err)
+			return prWorkItemsLoadedMsg{items: []workitemtracking.WorkItem{}}
+		}
 
-		// Create completion options with override
-		deleteBranch := false
-		completionOptions := &git.GitPullRequestCompletionOptions{
-			DeleteSourceBranch: &deleteBranch, // Don't delete source branch by default
+		var ids []int
+		for _, ref := range *refs {
+			if ref.Id == nil {
+				continue
+			}
+			if id, err := strconv.Atoi(*ref.Id); err == nil {
+				ids = append(ids, id)
+			}
 		}
 
-		// Complete the PR with override
-		_, err := prs.CompletePR(ctx, connection, *m.selectedProject.Name, repoID, prID, completionOptions)
+		items, err := workitems.GetWorkItems(ctx, connection, projectName, ids)
 		if err != nil {
-			log.Printf("Error overriding PR: %v", err)
-			return prActionCompleteMsg{action: "override", success: false, message: fmt.Sprintf("Failed to override PR: %v", err)}
+			log.Printf("Error getting PR work items: %v", err)
+			return prWorkItemsLoadedMsg{items: []workitemtracking.WorkItem{}}
 		}
 
-		return prActionCompleteMsg{action: "override", success: true, message: "PR completed with override"}
+		return prWorkItemsLoadedMsg{items: *items}
 	}
 }
 
-func (m model) createPR() tea.Cmd {
+func loadPRPolicies(projectName string, projectID string, prID int, cfg *config.Config) tea.Cmd {
 	return func() tea.Msg {
-		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedRepo.Id == nil ||
-			m.prSourceBranch == nil || m.prTargetBranch == nil {
-			return nil
-		}
-
-		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
 		ctx := context.Background()
 
-		// Create the PR request
-		title := m.prTitleInput.Value()
-		description := m.prDescInput.Value()
-		prRequest := &git.GitPullRequest{
-			Title:         &title,
-			Description:   &description,
-			SourceRefName: m.prSourceBranch.Name,
-			TargetRefName: m.prTargetBranch.Name,
-		}
-
-		// Add reviewers if any
-		if len(m.prReviewers) > 0 {
-			var reviewers []git.IdentityRefWithVote
-			for _, reviewer := range m.prReviewers {
-				if reviewer.Descriptor != nil {
-					reviewers = append(reviewers, git.IdentityRefWithVote{
-						Id: reviewer.Descriptor,
-					})
-				}
-			}
-			prRequest.Reviewers = &reviewers
+		evaluations, err := policies.GetPRPolicyEvaluations(ctx, connection, projectName, projectID, prID)
+		if err != nil {
+			log.Printf("Error getting PR policy evaluations: %v", err)
+			return prPoliciesLoadedMsg{evaluations: []policy.PolicyEvaluationRecord{}}
 		}
 
-		repoID := m.selectedRepo.Id.String()
-		pr, err := prs.CreatePR(ctx, connection, *m.selectedProject.Name, repoID, prRequest)
-		if err != nil {
-			log.Printf("Error creating PR: %v", err)
-			return nil
+		if evaluations != nil {
+			return prPoliciesLoadedMsg{evaluations: *evaluations}
 		}
 
-		return prCreatedMsg{pr: pr}
+		return prPoliciesLoadedMsg{evaluations: []policy.PolicyEvaluationRecord{}}
 	}
 }
 
-func tick() tea.Cmd {
-	return tea.Tick(time.Second*3, func(t time.Time) tea.Msg {
-		return autoRefreshMsg{}
-	})
-}
+func loadPRComments(projectName string, repoID string, prID int, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
+		ctx := context.Background()
 
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-	var cmds []tea.Cmd
-
-	// Handle config modal if shown
-	if m.showConfigModal {
-		// Update text inputs if in PR create mode
-		if m.prCreateMode {
-			var textCmd tea.Cmd
-			m.prTitleInput, textCmd = m.prTitleInput.Update(msg)
-			cmds = append(cmds, textCmd)
-
-			m.prDescInput, textCmd = m.prDescInput.Update(msg)
-			cmds = append(cmds, textCmd)
+		comments, err := prs.GetPRComments(ctx, connection, projectName, repoID, prID)
+		if err != nil {
+			log.Printf("Error getting PR comments: %v", err)
+			return prCommentsLoadedMsg{comments: []git.GitPullRequestCommentThread{}}
 		}
 
-		// Update text inputs if in override mode
-		if m.prOverrideMode {
-			var textCmd tea.Cmd
-			m.prOverrideInput, textCmd = m.prOverrideInput.Update(msg)
-			cmds = append(cmds, textCmd)
+		if comments != nil {
+			return prCommentsLoadedMsg{comments: *comments}
 		}
 
-		switch msg := msg.(type) {
-		case config.ConfigCompleteMsg:
-			// Configuration is complete, switch to main app
-			m.config = msg.Config
-			m.showConfigModal = false
-			m.configModal = nil
-			m.initialLoading = true
-			m.loadingProjects = true
-			m.autoDetectDone = false
-			return m, tea.Batch(m.projectsSpinner.Tick, loadProjects(m.config), autoDetectProjectAndRepo(m.config))
-		case tea.WindowSizeMsg:
-			m.width = msg.Width
-			m.height = msg.Height
-			m.configModal, cmd = m.configModal.Update(msg)
-			return m, cmd
-		default:
-			m.configModal, cmd = m.configModal.Update(msg)
-			if m.configModal.IsCompleted() {
-				if m.config.IsComplete() {
-					m.showConfigModal = false
-					m.configModal = nil
-					m.initialLoading = true
-					m.loadingProjects = true
-					m.autoDetectDone = false
-					return m, tea.Batch(m.projectsSpinner.Tick, loadProjects(m.config), autoDetectProjectAndRepo(m.config))
-				}
-			}
-			return m, cmd
-		}
+		return prCommentsLoadedMsg{comments: []git.GitPullRequestCommentThread{}}
 	}
+}
 
-	// Update spinners
-	if m.loadingProjects {
-		m.projectsSpinner, cmd = m.projectsSpinner.Update(msg)
-		cmds = append(cmds, cmd)
-	}
-	if m.loadingRepos {
-		m.reposSpinner, cmd = m.reposSpinner.Update(msg)
-		cmds = append(cmds, cmd)
-	}
-	if m.loadingPipelines {
-		m.pipelinesSpinner, cmd = m.pipelinesSpinner.Update(msg)
-		cmds = append(cmds, cmd)
+func loadPRFiles(projectName string, repoID string, prID int, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
+		ctx := context.Background()
+
+		files, err := prs.GetPRChanges(ctx, connection, projectName, repoID, prID)
+		if err != nil {
+			log.Printf("Error getting PR changes: %v", err)
+			return prFilesLoadedMsg{files: []prs.PRFileChange{}}
+		}
+
+		return prFilesLoadedMsg{files: *files}
 	}
-	if m.loadingRuns {
-		m.runsSpinner, cmd = m.runsSpinner.Update(msg)
-		cmds = append(cmds, cmd)
+}
+
+func loadPRIterations(projectName string, repoID string, prID int, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
+		ctx := context.Background()
+
+		iterations, err := prs.GetPRIterations(ctx, connection, projectName, repoID, prID)
+		if err != nil {
+			log.Printf("Error getting PR iterations: %v", err)
+			return prIterationsLoadedMsg{iterations: []git.GitPullRequestIteration{}}
+		}
+
+		return prIterationsLoadedMsg{iterations: *iterations}
 	}
-	if m.loadingTimeline {
-		m.timelineSpinner, cmd = m.timelineSpinner.Update(msg)
-		cmds = append(cmds, cmd)
+}
+
+func loadPRIterationFiles(projectName string, repoID string, prID int, iterationID int, compareTo int, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
+		ctx := context.Background()
+
+		files, err := prs.GetPRIterationChanges(ctx, connection, projectName, repoID, prID, iterationID, compareTo)
+		if err != nil {
+			log.Printf("Error getting PR iteration changes: %v", err)
+			return prFilesLoadedMsg{files: []prs.PRFileChange{}}
+		}
+
+		return prFilesLoadedMsg{files: *files}
 	}
-	if m.loadingPRs {
-		m.prsSpinner, cmd = m.prsSpinner.Update(msg)
-		cmds = append(cmds, cmd)
+}
+
+// pollCherryPick fetches the status of a cherry-pick or revert once the delay has passed
+func pollCherryPick(projectName string, repoID string, id int, revert bool, delay time.Duration, cfg *config.Config) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
+		ctx := context.Background()
+
+		if revert {
+			operation, err := prs.GetRevert(ctx, connection, projectName, repoID, id)
+			if err != nil {
+				log.Printf("Error getting revert status: %v", err)
+				return prCherryPickStatusMsg{id: id, err: err}
+			}
+			return cherryPickStatus(operation.RevertId, operation.Status, operation.DetailedStatus)
+		}
+
+		operation, err := prs.GetCherryPick(ctx, connection, projectName, repoID, id)
+		if err != nil {
+			log.Printf("Error getting cherry-pick status: %v", err)
+			return prCherryPickStatusMsg{id: id, err: err}
+		}
+		return cherryPickStatus(operation.CherryPickId, operation.Status, operation.DetailedStatus)
+	})
+}
+
+func cherryPickStatus(id *int, status *git.GitAsyncOperationStatus, detail *git.GitAsyncRefOperationDetail) prCherryPickStatusMsg {
+	msg := prCherryPickStatusMsg{detail: detail}
+	if id != nil {
+		msg.id = *id
 	}
-	if m.loadingPRDetails {
-		m.prDetailsSpinner, cmd = m.prDetailsSpinner.Update(msg)
-		cmds = append(cmds, cmd)
+	if status != nil {
+		msg.status = *status
 	}
+	return msg
+}
 
-	switch msg := msg.(type) {
-	case []core.TeamProjectReference:
-		m.projects = msg
-		m.loadingProjects = false
+func loadPRFileDiff(projectName string, repoID string, file prs.PRFileChange, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
+		ctx := context.Background()
 
-		// Check if we can complete initial loading
-		if m.autoDetectDone {
-			m.initialLoading = false
+		// A missing side would show the whole file as added or deleted, so fail instead
+		oldContent, err := prs.GetFileContent(ctx, connection, projectName, repoID, file.OriginalObjectId)
+		if err != nil {
+			log.Printf("Error getting original file content: %v", err)
+			return prDiffLoadedMsg{path: file.Path, err: err}
 		}
-		return m, tea.Batch(cmds...)
-	case autoDetectCompleteMsg:
-		m.autoDetectDone = true
-		m.autoDetectResult = msg.result
-
-		// Check if we can complete initial loading
-		if !m.loadingProjects {
-			m.initialLoading = false
+		newContent, err := prs.GetFileContent(ctx, connection, projectName, repoID, file.ObjectId)
+		if err != nil {
+			log.Printf("Error getting file content: %v", err)
+			return prDiffLoadedMsg{path: file.Path, err: err}
 		}
 
-		if msg.result.ShouldAutoLoad {
-			// Find and select the matching project
-			for i, project := range m.projects {
-				if project.Id != nil && msg.result.Project != nil && msg.result.Project.Id != nil &&
-					*project.Id == *msg.result.Project.Id {
-					m.selectedProject = &m.projects[i]
-					m.cursor = i
-					m.loadingRepos = true
-					m.repos = []git.GitRepository{}
+		if diff.IsBinary(oldContent) || diff.IsBinary(newContent) {
+			return prDiffLoadedMsg{path: file.Path, binary: true}
+		}
 
-					// Auto-select the repository when repos are loaded
-					m.autoSelectRepo = msg.result.Repository
+		return prDiffLoadedMsg{path: file.Path, lines: diff.Unified(oldContent, newContent, 3)}
+	}
+}
 
-					cmds = append(cmds, m.reposSpinner.Tick, loadProjectRepos(*m.selectedProject.Name, m.config))
-					break
-				}
-			}
+func (m model) approvePR(vote int, comment string) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "vote", success: false, message: "Failed to vote: missing project, repo, or PR details"}
 		}
-		return m, tea.Batch(cmds...)
-	case projectLoadedMsg:
-		m.repos = msg.repos
-		m.loadingRepos = false
 
-		// Auto-select repository if we have one from auto-detection
-		if m.autoSelectRepo != nil {
-			for i, repo := range m.repos {
-				if repo.Id != nil && m.autoSelectRepo.Id != nil && *repo.Id == *m.autoSelectRepo.Id {
-					m.selectedRepo = &m.repos[i]
-					// Instead of showing repo options, directly navigate to pipelines
-					m.showPipelines = true
-					m.focusedPanel = 2
-					m.loadingPipelines = true
-					m.pipelines = []pipeline.Pipeline{}
-					m.cursor = 0
-					m.autoSelectRepo = nil // Clear auto-selection
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
 
-					if m.selectedProject != nil && m.selectedRepo != nil {
-						cmds = append(cmds, m.pipelinesSpinner.Tick, loadRepoPipelines(*m.selectedProject.Name, *m.selectedRepo.Name, m.config))
-					}
-					break
-				}
+		// Vote only as the authenticated user
+		user := m.currentUser
+		if user == nil {
+			var err error
+			user, err = identity.GetCurrentUser(ctx, connection)
+			if err != nil {
+				log.Printf("Error getting current user: %v", err)
+				return prActionCompleteMsg{action: "vote", success: false, message: fmt.Sprintf("Failed to vote: could not resolve current user: %v", err)}
 			}
 		}
 
-		return m, tea.Batch(cmds...)
-	case pipelinesLoadedMsg:
-		m.pipelines = msg.pipelines
-		m.loadingPipelines = false
-		return m, tea.Batch(cmds...)
-	case runsLoadedMsg:
-		m.runs = msg.runs
-		m.loadingRuns = false
+		repoID := m.selectedRepo.Id.String()
+		prID := *m.selectedPR.PullRequestId
 
-		// Check for in-progress runs and auto-select the first one
-		for i, run := range m.runs {
-			if run.State != nil && (*run.State == "inProgress" || *run.State == "notStarted") {
-				m.selectedRun = &m.runs[i]
-				m.showRuns = false
-				m.showRunDetails = true
-				m.loadingTimeline = true
-				m.timeline = nil
-				m.cursor = 0
-				m.autoRefresh = true
-				m.autoSelected = true
+		_, err := prs.VotePR(ctx, connection, *m.selectedProject.Name, repoID, prID, *user.Id, vote, comment)
+		if err != nil {
+			log.Printf("Error voting on PR: %v", err)
+			return prActionCompleteMsg{action: "vote", success: false, message: fmt.Sprintf("Failed to vote: %v", err)}
+		}
 
-				if m.selectedProject != nil && m.selectedRun.Id != nil {
-					cmds = append(cmds, m.timelineSpinner.Tick, loadRunTimeline(*m.selectedProject.Name, *m.selectedRun.Id, m.config), tick())
-				}
-				break
-			}
+		return prActionCompleteMsg{action: "vote", success: true, message: fmt.Sprintf("Your vote \"%s\" was submitted successfully", voteLabel(vote))}
+	}
+}
+
+func (m model) createThread(content string) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "comment", success: false, message: "Failed to add comment: missing project, repo, or PR details"}
 		}
 
-		return m, tea.Batch(cmds...)
-	case timelineLoadedMsg:
-		m.timeline = msg.timeline
-		m.loadingTimeline = false
-		m.lastRefresh = time.Now()
-		return m, tea.Batch(cmds...)
-	case prsLoadedMsg:
-		m.prs = msg.prs
-		m.loadingPRs = false
-		return m, tea.Batch(cmds...)
-	case branchesLoadedMsg:
-		m.branches = msg.branches
-		m.loadingBranches = false
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
 
-		// Set default branches if in PR create mode
-		if m.prCreateMode {
-			// Set target branch to main/master by default
-			for _, branch := range m.branches {
-				if branch.Name != nil {
-					branchName := *branch.Name
-					if branchName == "refs/heads/main" || branchName == "refs/heads/master" {
-						m.prTargetBranch = &branch
-						break
-					}
-				}
-			}
+		repoID := m.selectedRepo.Id.String()
+		prID := *m.selectedPR.PullRequestId
 
-			// Auto-select the latest non-main branch as source
-			if m.prSourceBranch == nil {
-				for _, branch := range m.branches {
-					if branch.Name != nil {
-						branchName := *branch.Name
-						if branchName != "refs/heads/main" && branchName != "refs/heads/master" {
-							m.prSourceBranch = &branch
-							break
-						}
-					}
-				}
-			}
+		_, err := prs.CreatePRThread(ctx, connection, *m.selectedProject.Name, repoID, prID, content)
+		if err != nil {
+			log.Printf("Error creating thread: %v", err)
+			return prActionCompleteMsg{action: "comment", success: false, message: fmt.Sprintf("Failed to add comment: %v", err)}
 		}
-		return m, tea.Batch(cmds...)
-	case usersLoadedMsg:
-		m.users = msg.users
-		m.loadingUsers = false
 
-		// Initialize filtered reviewers if in PR create mode
-		if m.prCreateMode {
-			m.filteredReviewers = m.users
-		}
-		return m, tea.Batch(cmds...)
-	case prCreatedMsg:
-		// PR created successfully, refresh the PR list
-		if m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil {
-			m.showPRCreate = false
-			m.prCreateMode = false
-			m.loadingPRs = true
-			repoID := m.selectedRepo.Id.String()
-			return m, tea.Batch(append(cmds, m.prsSpinner.Tick, loadRepoPRs(*m.selectedProject.Name, repoID, m.config))...)
+		return prActionCompleteMsg{action: "comment", success: true, message: "Comment added"}
+	}
+}
+
+func (m model) createFileThread(file prs.PRFileChange, threadContext git.CommentThreadContext, content string) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "comment", success: false, message: "Failed to add comment: missing project, repo, or PR details"}
 		}
-		return m, tea.Batch(cmds...)
-	case prDetailsLoadedMsg:
-		m.prDetails = msg.pr
-		m.loadingPRDetails = false
-		return m, tea.Batch(cmds...)
-	case prCommentsLoadedMsg:
-		m.prComments = msg.comments
-		m.loadingPRComments = false
-		return m, tea.Batch(cmds...)
-	case prActionCompleteMsg:
-		// Store action message and timestamp
-		m.prActionMessage = msg.message
-		m.prActionTime = time.Now()
 
-		// PR action completed, refresh PR details and list
-		if msg.success && m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil {
-			m.showPRReview = false
-			m.prReviewMode = false
-			m.prOverrideMode = false
-			repoID := m.selectedRepo.Id.String()
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
 
-			// Refresh both PR details and PR list
-			if m.selectedPR != nil && m.selectedPR.PullRequestId != nil {
-				return m, tea.Batch(append(cmds,
-					loadPRDetails(*m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, m.config),
-					loadRepoPRs(*m.selectedProject.Name, repoID, m.config))...)
-			}
+		repoID := m.selectedRepo.Id.String()
+		prID := *m.selectedPR.PullRequestId
+
+		_, err := prs.CreatePRFileThread(ctx, connection, *m.selectedProject.Name, repoID, prID, file, threadContext, content)
+		if err != nil {
+			log.Printf("Error creating inline thread: %v", err)
+			return prActionCompleteMsg{action: "comment", success: false, message: fmt.Sprintf("Failed to add comment: %v", err)}
 		}
-		return m, tea.Batch(cmds...)
-	case autoRefreshMsg:
-		if m.autoRefresh && m.showRunDetails && m.selectedProject != nil && m.selectedRun != nil && m.selectedRun.Id != nil {
-			return m, tea.Batch(append(cmds, loadRunTimeline(*m.selectedProject.Name, *m.selectedRun.Id, m.config), tick())...)
+
+		return prActionCompleteMsg{action: "comment", success: true, message: "Comment added"}
+	}
+}
+
+func (m model) replyToThread(threadID int, parentCommentID int, content string) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "reply", success: false, message: "Failed to reply: missing project, repo, or PR details"}
 		}
-		return m, tea.Batch(cmds...)
-	case refreshMsg:
-		if m.showRunDetails && m.selectedProject != nil && m.selectedRun != nil && m.selectedRun.Id != nil {
-			m.loadingTimeline = true
-			return m, tea.Batch(append(cmds, m.timelineSpinner.Tick, loadRunTimeline(*m.selectedProject.Name, *m.selectedRun.Id, m.config))...)
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		repoID := m.selectedRepo.Id.String()
+		prID := *m.selectedPR.PullRequestId
+
+		_, err := prs.ReplyToPRThread(ctx, connection, *m.selectedProject.Name, repoID, prID, threadID, parentCommentID, content)
+		if err != nil {
+			log.Printf("Error replying to thread: %v", err)
+			return prActionCompleteMsg{action: "reply", success: false, message: fmt.Sprintf("Failed to reply: %v", err)}
 		}
+
+		return prActionCompleteMsg{action: "reply", success: true, message: "Reply posted"}
+	}
+}
+
+func (m model) updateThreadStatus(threadID int, status git.CommentThreadStatus) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "status", success: false, message: "Failed to update thread: missing project, repo, or PR details"}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		repoID := m.selectedRepo.Id.String()
+		prID := *m.selectedPR.PullRequestId
+
+		_, err := prs.UpdatePRThreadStatus(ctx, connection, *m.selectedProject.Name, repoID, prID, threadID, status)
+		if err != nil {
+			log.Printf("Error updating thread status: %v", err)
+			return prActionCompleteMsg{action: "status", success: false, message: fmt.Sprintf("Failed to update thread: %v", err)}
+		}
+
+		statusText, _ := threadStatusLabel(status)
+		return prActionCompleteMsg{action: "status", success: true, message: "Thread marked as " + statusText}
+	}
+}
+
+func (m model) completePR(completionOptions *git.GitPullRequestCompletionOptions) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "complete", success: false, message: "Failed to complete PR: missing project, repo, or PR details"}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		repoID := m.selectedRepo.Id.String()
+		prID := *m.selectedPR.PullRequestId
+
+		_, err := prs.CompletePR(ctx, connection, *m.selectedProject.Name, repoID, prID, completionOptions)
+		if err != nil {
+			log.Printf("Error completing PR: %v", err)
+			return prActionCompleteMsg{action: "complete", success: false, message: fmt.Sprintf("Failed to complete PR: %v", err)}
+		}
+
+		return prActionCompleteMsg{action: "complete", success: true, message: "PR completed successfully"}
+	}
+}
+
+// loadSelectedPRPolicies loads policy evaluations for the selected PR, which are keyed by project ID
+func (m model) loadSelectedPRPolicies() tea.Cmd {
+	if m.selectedProject == nil || m.selectedProject.Id == nil || m.selectedPR == nil || m.selectedPR.PullRequestId == nil {
+		return nil
+	}
+	return loadPRPolicies(*m.selectedProject.Name, m.selectedProject.Id.String(), *m.selectedPR.PullRequestId, m.config)
+}
+
+func (m model) loadSelectedPRWorkItems() tea.Cmd {
+	if m.selectedProject == nil || m.selectedRepo == nil || m.selectedRepo.Id == nil || m.selectedPR == nil || m.selectedPR.PullRequestId == nil {
+		return nil
+	}
+	return loadPRWorkItems(*m.selectedProject.Name, m.selectedRepo.Id.String(), *m.selectedPR.PullRequestId, m.config)
+}
+
+func (m *model) resetWorkItemSearch() {
+	m.workItemSearch = ""
+	m.workItemSearched = ""
+	m.workItemResults = []workitemtracking.WorkItem{}
+	m.searchingWorkItems = false
+}
+
+func (m model) startWorkItemSearch(cmds []tea.Cmd) (tea.Model, tea.Cmd) {
+	m.workItemSearched = m.workItemSearch
+	if m.selectedProject == nil || strings.TrimSpace(m.workItemSearch) == "" {
+		m.workItemResults = []workitemtracking.WorkItem{}
 		return m, tea.Batch(cmds...)
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		return m, tea.Batch(cmds...)
-	case tea.KeyMsg:
-		// Handle character input for reviewer search
-		if m.prCreateMode && m.prCreateStep == 4 && !m.searchMode {
-			key := msg.String()
-			if key == "backspace" {
-				if len(m.reviewerSearch) > 0 {
-					m.reviewerSearch = m.reviewerSearch[:len(m.reviewerSearch)-1]
-					m.filterReviewers()
-					m.cursor = 0
-				}
-				return m, tea.Batch(cmds...)
-			} else if len(msg.Runes) > 0 && key != "up" && key != "down" && key != "enter" &&
-				key != "tab" && key != "escape" && key != "esc" && key != "q" && key != "ctrl+c" {
-				m.reviewerSearch += string(msg.Runes)
-				m.filterReviewers()
-				m.cursor = 0
-				return m, tea.Batch(cmds...)
+	}
+	m.searchingWorkItems = true
+	return m, tea.Batch(append(cmds, searchWorkItems(*m.selectedProject.Name, m.workItemSearch, m.config))...)
+}
+
+func (m model) linkWorkItem(item workitemtracking.WorkItem) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedProject.Id == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil || item.Id == nil {
+			return prActionCompleteMsg{action: "link", success: false, message: "Failed to link work item: missing project, repo, or PR details"}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		for _, linked := range m.prLinkedWorkItems {
+			if linked.Id != nil && *linked.Id == *item.Id {
+				return prActionCompleteMsg{action: "link", success: false, message: fmt.Sprintf("Failed to link work item: #%d is already linked", *item.Id)}
 			}
 		}
 
-		if m.searchMode {
-			switch msg.String() {
-			case "escape", "esc":
-				m.searchMode = false
-				m.searchQuery = ""
-				m.filteredItems = nil
-				m.cursor = m.originalCursor
-				m.updateScroll()
-				return m, tea.Batch(cmds...)
-			case "enter":
-				if len(m.filteredItems) > 0 && m.cursor < len(m.filteredItems) {
-					selected := m.filteredItems[m.cursor]
-					m.searchMode = false
-					m.searchQuery = ""
-					m.filteredItems = nil
+		err := workitems.LinkPullRequest(ctx, connection, *m.selectedProject.Name, *item.Id,
+			m.selectedProject.Id.String(), m.selectedRepo.Id.String(), *m.selectedPR.PullRequestId)
+		if err != nil {
+			log.Printf("Error linking work item: %v", err)
+			return prActionCompleteMsg{action: "link", success: false, message: fmt.Sprintf("Failed to link work item: %v", err)}
+		}
 
-					if m.focusedPanel == 0 {
-						if project, ok := selected.(core.TeamProjectReference); ok {
-							for i, p := range m.projects {
-								if p.Id != nil && project.Id != nil && *p.Id == *project.Id {
-									m.cursor = i
-									m.selectedProject = &m.projects[i]
-									m.loadingRepos = true
-									m.repos = []git.GitRepository{}
-									m.updateScroll()
-									return m, tea.Batch(append(cmds, m.reposSpinner.Tick, loadProjectRepos(*m.selectedProject.Name, m.config))...)
-								}
-							}
-						}
-					} else if m.focusedPanel == 1 {
-						if repo, ok := selected.(git.GitRepository); ok {
-							for i, r := range m.repos {
-								if r.Id != nil && repo.Id != nil && *r.Id == *repo.Id {
-									m.cursor = i
-									m.selectedRepo = &m.repos[i]
-									m.showRepoOptions = true
-									m.cursor = 0
-									m.updateScroll()
-									return m, tea.Batch(cmds...)
-								}
-							}
-						}
-					}
-				}
-				return m, tea.Batch(cmds...)
-			case "backspace":
-				if len(m.searchQuery) > 0 {
-					m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
-					m.filterItems()
-					m.cursor = 0
-				}
-				return m, tea.Batch(cmds...)
-			case "up", "k":
-				if m.cursor > 0 {
-					m.cursor--
-				}
-				return m, tea.Batch(cmds...)
-			case "down", "j":
-				if m.cursor < len(m.filteredItems)-1 {
-					m.cursor++
-				}
-				return m, tea.Batch(cmds...)
-			default:
-				if len(msg.Runes) > 0 {
-					m.searchQuery += string(msg.Runes)
-					m.filterItems()
-					m.cursor = 0
-				}
-				return m, tea.Batch(cmds...)
+		return prActionCompleteMsg{action: "link", success: true, message: fmt.Sprintf("Linked work item #%d", *item.Id)}
+	}
+}
+
+// formatWorkItem renders a work item as "#id [type] title (state)"
+func formatWorkItem(item workitemtracking.WorkItem) string {
+	text := fmt.Sprintf("#%d", *item.Id)
+	if itemType := workitems.Field(item, "System.WorkItemType"); itemType != "" {
+		text += " [" + itemType + "]"
+	}
+	if title := workitems.Field(item, "System.Title"); title != "" {
+		text += " " + title
+	}
+	if state := workitems.Field(item, "System.State"); state != "" {
+		text += " (" + state + ")"
+	}
+	return text
+}
+
+// renderWorkItemSearch writes the work item search box and results, returning the lines used
+func (m model) renderWorkItemSearch(content *strings.Builder) int {
+	linesUsed := 0
+	content.WriteString("  Search: " + m.workItemSearch + "\n")
+	linesUsed++
+
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6
+
+	if m.searchingWorkItems {
+		content.WriteString("  Searching work items...\n")
+		linesUsed++
+	} else if m.workItemSearched != "" && len(m.workItemResults) == 0 {
+		content.WriteString("  No matching work items\n")
+		linesUsed++
+	} else if len(m.workItemResults) > 0 {
+		for i, item := range m.workItemResults {
+			if i >= 5 { // Limit to 5 results
+				break
+			}
+			prefix := "    "
+			if i == m.cursor {
+				prefix = "  → "
 			}
+			content.WriteString(prefix + truncateRunes(formatWorkItem(item), contentWidth-6) + "\n")
+			linesUsed++
 		}
+	}
+	return linesUsed
+}
 
-		switch msg.String() {
-		case "q", "ctrl+c":
-			return m, tea.Quit
-		case "r":
-			if m.showRunDetails {
-				return m, tea.Batch(append(cmds, func() tea.Msg { return refreshMsg{} })...)
+func (m model) requeueFailedBuilds() tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil {
+			return prActionCompleteMsg{action: "requeue", success: false, message: "Failed to requeue builds: missing project details"}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		requeued := 0
+		for _, evaluation := range m.prPolicies {
+			if !policies.IsBuildValidation(evaluation) || !policies.IsFailed(evaluation) || evaluation.EvaluationId == nil {
+				continue
 			}
-		case "/":
-			if !m.showRepoOptions {
-				m.searchMode = true
-				m.searchQuery = ""
-				m.originalCursor = m.cursor
-				m.cursor = 0
-				m.filterItems()
-				return m, tea.Batch(cmds...)
+			_, err := policies.RequeuePolicyEvaluation(ctx, connection, *m.selectedProject.Name, *evaluation.EvaluationId)
+			if err != nil {
+				log.Printf("Error requeuing policy evaluation: %v", err)
+				return prActionCompleteMsg{action: "requeue", success: false, message: fmt.Sprintf("Failed to requeue build: %v", err)}
 			}
-		case "escape", "esc", "backspace":
-			if m.prOverrideMode {
-				// Exit override mode
-				m.showPRReview = false
-				m.prOverrideMode = false
-				return m, tea.Batch(cmds...)
-			} else if m.prCreateMode {
-				// Exit PR creation mode
-				m.showPRCreate = false
-				m.prCreateMode = false
-				m.prCreateStep = 0
-				m.cursor = 0
-				return m, tea.Batch(cmds...)
-			} else if m.showRunDetails {
-				m.showRunDetails = false
-				m.showRuns = true
-				m.autoRefresh = false
-				m.autoSelected = false
-				// Find the cursor position for the selected run
-				foundRun := false
-				for i, run := range m.runs {
-					if m.selectedRun != nil && run.Id != nil && m.selectedRun.Id != nil && *run.Id == *m.selectedRun.Id {
-						m.cursor = i
-						foundRun = true
-						break
-					}
-				}
-				if !foundRun {
-					m.cursor = 0
+			requeued++
+		}
+
+		if requeued == 0 {
+			return prActionCompleteMsg{action: "requeue", success: false, message: "Failed to requeue: no failed build validations"}
+		}
+		return prActionCompleteMsg{action: "requeue", success: true, message: fmt.Sprintf("Requeued %d build validation(s)", requeued)}
+	}
+}
+
+// pendingPolicies returns blocking policy evaluations that would stop the PR from completing
+func (m model) pendingPolicies() []policy.PolicyEvaluationRecord {
+	var pending []policy.PolicyEvaluationRecord
+	for _, evaluation := range m.prPolicies {
+		if policies.IsPending(evaluation) {
+			pending = append(pending, evaluation)
+		}
+	}
+	return pending
+}
+
+func (m model) hasFailedBuild() bool {
+	for _, evaluation := range m.prPolicies {
+		if policies.IsBuildValidation(evaluation) && policies.IsFailed(evaluation) {
+			return true
+		}
+	}
+	return false
+}
+
+func policyStatusLabel(evaluation policy.PolicyEvaluationRecord) (string, lipgloss.Color) {
+	if evaluation.Status == nil {
+		return "⏳ Unknown", lipgloss.Color("240")
+	}
+	switch *evaluation.Status {
+	case policy.PolicyEvaluationStatusValues.Approved:
+		return "✅ Approved", lipgloss.Color("2")
+	case policy.PolicyEvaluationStatusValues.Rejected:
+		return "❌ Rejected", lipgloss.Color("1")
+	case policy.PolicyEvaluationStatusValues.Broken:
+		return "💥 Broken", lipgloss.Color("1")
+	case policy.PolicyEvaluationStatusValues.Running:
+		return "🔄 Running", lipgloss.Color("3")
+	case policy.PolicyEvaluationStatusValues.Queued:
+		return "⏳ Queued", lipgloss.Color("3")
+	}
+	return string(*evaluation.Status), lipgloss.Color("240")
+}
+
+// currentUserVote returns the authenticated user's vote on the PR, 0 when they are not a reviewer
+func (m model) currentUserVote() int {
+	if m.prDetails == nil || m.prDetails.Reviewers == nil || m.currentUser == nil || m.currentUser.Id == nil {
+		return 0
+	}
+	for _, reviewer := range *m.prDetails.Reviewers {
+		if reviewer.Id != nil && strings.EqualFold(*reviewer.Id, *m.currentUser.Id) && reviewer.Vote != nil {
+			return *reviewer.Vote
+		}
+	}
+	return 0
+}
+
+// dropPendingConfirms disarms the actions that need a second press of x once any other key
+// is pressed. Esc is let through so the back handling closes the confirmation itself.
+func (m *model) dropPendingConfirms(key string) {
+	if key == "x" || key == "esc" || key == "escape" {
+		return
+	}
+	m.prAbandonConfirm = false
+	m.runCancelConfirm = false
+}
+
+// changePRState abandons, reactivates, drafts or publishes the selected PR
+func (m model) changePRState(action string) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: action, success: false, message: "Failed to update PR: missing project, repo, or PR details"}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		repoID := m.selectedRepo.Id.String()
+		prID := *m.selectedPR.PullRequestId
+		projectName := *m.selectedProject.Name
+
+		var err error
+		var actionText string
+		switch action {
+		case "abandon":
+			_, err = prs.AbandonPR(ctx, connection, projectName, repoID, prID)
+			actionText = "PR abandoned"
+		case "reactivate":
+			_, err = prs.ReactivatePR(ctx, connection, projectName, repoID, prID)
+			actionText = "PR reactivated"
+		case "draft":
+			_, err = prs.SetPRDraft(ctx, connection, projectName, repoID, prID, true)
+			actionText = "PR converted to draft"
+		case "publish":
+			_, err = prs.SetPRDraft(ctx, connection, projectName, repoID, prID, false)
+			actionText = "PR published"
+		default:
+			return prActionCompleteMsg{action: action, success: false, message: fmt.Sprintf("Failed to update PR: unknown action %q", action)}
+		}
+		if err != nil {
+			log.Printf("Error updating PR (%s): %v", action, err)
+			return prActionCompleteMsg{action: action, success: false, message: fmt.Sprintf("Failed to %s PR: %v", action, err)}
+		}
+
+		return prActionCompleteMsg{action: action, success: true, message: actionText}
+	}
+}
+
+func (m model) setAutoComplete(completionOptions *git.GitPullRequestCompletionOptions) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "autocomplete", success: false, message: "Failed to set auto-complete: missing project, repo, or PR details"}
+		}
+		if m.currentUser == nil {
+			return prActionCompleteMsg{action: "autocomplete", success: false, message: "Failed to set auto-complete: current user is unknown"}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		repoID := m.selectedRepo.Id.String()
+		prID := *m.selectedPR.PullRequestId
+
+		_, err := prs.SetAutoComplete(ctx, connection, *m.selectedProject.Name, repoID, prID, m.currentUser, completionOptions)
+		if err != nil {
+			log.Printf("Error setting auto-complete: %v", err)
+			return prActionCompleteMsg{action: "autocomplete", success: false, message: fmt.Sprintf("Failed to set auto-complete: %v", err)}
+		}
+
+		return prActionCompleteMsg{action: "autocomplete", success: true, message: "Auto-complete set, PR will merge once policies pass"}
+	}
+}
+
+func (m model) cancelAutoComplete() tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "autocomplete", success: false, message: "Failed to cancel auto-complete: missing project, repo, or PR details"}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		repoID := m.selectedRepo.Id.String()
+		prID := *m.selectedPR.PullRequestId
+
+		_, err := prs.CancelAutoComplete(ctx, connection, *m.selectedProject.Name, repoID, prID)
+		if err != nil {
+			log.Printf("Error cancelling auto-complete: %v", err)
+			return prActionCompleteMsg{action: "autocomplete", success: false, message: fmt.Sprintf("Failed to cancel auto-complete: %v", err)}
+		}
+
+		return prActionCompleteMsg{action: "autocomplete", success: true, message: "Auto-complete cancelled"}
+	}
+}
+
+func (m model) overridePR(overrideMessage string, completionOptions *git.GitPullRequestCompletionOptions) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "override", success: false, message: "Failed to override PR: missing project, repo, or PR details"}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		repoID := m.selectedRepo.Id.String()
+		prID := *m.selectedPR.PullRequestId
+
+		_, err := prs.BypassAndCompletePR(ctx, connection, *m.selectedProject.Name, repoID, prID, overrideMessage, completionOptions)
+		if err != nil {
+			log.Printf("Error overriding PR: %v", err)
+			if prs.IsPermissionError(err) {
+				return prActionCompleteMsg{action: "override", success: false, message: "Failed to override PR: you do not have permission to bypass policies in this repository"}
+			}
+			return prActionCompleteMsg{action: "override", success: false, message: fmt.Sprintf("Failed to override PR: %v", err)}
+		}
+
+		// Record the reason on the PR so the bypass is visible to everyone
+		comment := fmt.Sprintf("Policies bypassed via AZTUI. Reason: %s", overrideMessage)
+		if _, err := prs.CreatePRThread(ctx, connection, *m.selectedProject.Name, repoID, prID, comment); err != nil {
+			log.Printf("Error posting override reason: %v", err)
+			return prActionCompleteMsg{action: "override", success: true, message: "PR completed with override, but the reason could not be posted as a comment"}
+		}
+
+		return prActionCompleteMsg{action: "override", success: true, message: "PR completed with override"}
+	}
+}
+
+func newPRDescInput() textarea.Model {
+	input := textarea.New()
+	input.Placeholder = "Enter PR description (markdown)..."
+	input.ShowLineNumbers = false
+	input.CharLimit = 0
+	input.SetWidth(60)
+	input.SetHeight(6)
+	return input
+}
+
+func loadPRTemplate(projectName string, repoID string, targetRefName string, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
+		ctx := context.Background()
+
+		template, err := prs.GetPRTemplate(ctx, connection, projectName, repoID, targetRefName)
+		if err != nil {
+			log.Printf("Error getting PR template: %v", err)
+			return nil
+		}
+
+		return prTemplateLoadedMsg{targetRefName: targetRefName, template: template}
+	}
+}
+
+func (m model) loadTargetPRTemplate() tea.Cmd {
+	if m.selectedProject == nil || m.selectedRepo == nil || m.selectedRepo.Id == nil || m.prTargetBranch == nil || m.prTargetBranch.Name == nil {
+		return nil
+	}
+	return loadPRTemplate(*m.selectedProject.Name, m.selectedRepo.Id.String(), *m.prTargetBranch.Name, m.config)
+}
+
+func loadBranchCommits(projectName string, repoID string, sourceRefName string, targetRefName string, cfg *config.Config) tea.Cmd {
+	return func() tea.Msg {
+		connection := azuredevops.NewPatConnection(cfg.AzureOrgURL, cfg.AzurePAT)
+		ctx := context.Background()
+
+		commits, err := prs.GetBranchCommits(ctx, connection, projectName, repoID, sourceRefName, targetRefName)
+		if err != nil {
+			log.Printf("Error getting branch commits: %v", err)
+			return prCommitsLoadedMsg{sourceRefName: sourceRefName, targetRefName: targetRefName, commits: []git.GitCommitRef{}}
+		}
+
+		return prCommitsLoadedMsg{sourceRefName: sourceRefName, targetRefName: targetRefName, commits: *commits}
+	}
+}
+
+func (m model) loadBranchCommits() tea.Cmd {
+	if m.selectedProject == nil || m.selectedRepo == nil || m.selectedRepo.Id == nil ||
+		m.prSourceBranch == nil || m.prSourceBranch.Name == nil || m.prTargetBranch == nil || m.prTargetBranch.Name == nil {
+		return nil
+	}
+	return loadBranchCommits(*m.selectedProject.Name, m.selectedRepo.Id.String(), *m.prSourceBranch.Name, *m.prTargetBranch.Name, m.config)
+}
+
+// commitSuggestion proposes a PR title and description from the branch's commits. A single
+// commit supplies both from its message, otherwise the title comes from the branch name and
+// the description lists the commit subjects oldest first.
+func commitSuggestion(commits []git.GitCommitRef, sourceRefName string) (string, string) {
+	var messages []string
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		// Merge commits only repeat what the other commits say
+		if commit.Comment == nil || (commit.Parents != nil && len(*commit.Parents) > 1) {
+			continue
+		}
+		messages = append(messages, strings.TrimSpace(*commit.Comment))
+	}
+
+	if len(messages) == 1 {
+		subject, body, _ := strings.Cut(messages[0], "\n")
+		return strings.TrimSpace(subject), strings.TrimSpace(body)
+	}
+
+	// "refs/heads/feature/add-login" becomes "Add login"
+	branchName := sourceRefName[strings.LastIndex(sourceRefName, "/")+1:]
+	title := strings.TrimSpace(strings.NewReplacer("-", " ", "_", " ").Replace(branchName))
+	if titleRunes := []rune(title); len(titleRunes) > 0 {
+		title = strings.ToUpper(string(titleRunes[0])) + string(titleRunes[1:])
+	}
+
+	var description strings.Builder
+	for _, message := range messages {
+		subject, _, _ := strings.Cut(message, "\n")
+		description.WriteString("- " + strings.TrimSpace(subject) + "\n")
+	}
+	return title, strings.TrimSuffix(description.String(), "\n")
+}
+
+// applyCommitSuggestion fills the title and description from the commits. Unless forced,
+// only fields that are empty or still hold an earlier suggestion are replaced, so a
+// description filled from the PR template is kept.
+func (m *model) applyCommitSuggestion(force bool) {
+	if len(m.prCommits) == 0 || m.prSourceBranch == nil || m.prSourceBranch.Name == nil {
+		return
+	}
+	title, description := commitSuggestion(m.prCommits, *m.prSourceBranch.Name)
+
+	if currentTitle := m.prTitleInput.Value(); force || currentTitle == "" || currentTitle == m.prGeneratedTitle {
+		m.prTitleInput.SetValue(title)
+		m.prTitleInput.CursorEnd()
+		m.prGeneratedTitle = title
+	}
+	// A template takes precedence over the commit list unless the suggestion is asked for
+	if currentDesc := m.prDescInput.Value(); force || currentDesc == "" || currentDesc == m.prGeneratedDesc {
+		m.prDescInput.SetValue(description)
+		m.prGeneratedDesc = description
+	}
+}
+
+// editPRDescription opens the description in $VISUAL or $EDITOR, suspending the TUI until it exits
+func (m model) editPRDescription() tea.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	file, err := os.CreateTemp("", "aztui-pr-*.md")
+	if err != nil {
+		log.Printf("Error creating PR description file: %v", err)
+		return nil
+	}
+	_, err = file.WriteString(m.prDescInput.Value())
+	file.Close()
+	if err != nil {
+		log.Printf("Error writing PR description file: %v", err)
+		os.Remove(file.Name())
+		return nil
+	}
+
+	// The editor may carry arguments, such as "code --wait"
+	editorArgs := strings.Fields(editor)
+	cmd := exec.Command(editorArgs[0], append(editorArgs[1:], file.Name())...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return prDescEditedMsg{path: file.Name(), err: err}
+	})
+}
+
+func (m model) createPR() tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedRepo.Id == nil ||
+			m.prSourceBranch == nil || m.prTargetBranch == nil {
+			return nil
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		// Create the PR request
+		title := m.prTitleInput.Value()
+		description := m.prDescInput.Value()
+		isDraft := m.prCreateDraft
+		prRequest := &git.GitPullRequest{
+			Title:         &title,
+			Description:   &description,
+			SourceRefName: m.prSourceBranch.Name,
+			TargetRefName: m.prTargetBranch.Name,
+			IsDraft:       &isDraft,
+		}
+
+		// Add reviewers if any
+		if len(m.prReviewers) > 0 {
+			reviewers := m.prReviewers
+			prRequest.Reviewers = &reviewers
+		}
+
+		// Link work items
+		if len(m.prWorkItems) > 0 {
+			var workItemRefs []webapi.ResourceRef
+			for _, item := range m.prWorkItems {
+				id := fmt.Sprintf("%d", *item.Id)
+				workItemRefs = append(workItemRefs, webapi.ResourceRef{Id: &id})
+			}
+			prRequest.WorkItemRefs = &workItemRefs
+		}
+
+		repoID := m.selectedRepo.Id.String()
+		pr, err := prs.CreatePR(ctx, connection, *m.selectedProject.Name, repoID, prRequest)
+		if err != nil {
+			log.Printf("Error creating PR: %v", err)
+			return nil
+		}
+
+		return prCreatedMsg{pr: pr}
+	}
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(time.Second*3, func(t time.Time) tea.Msg {
+		return autoRefreshMsg{}
+	})
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	var cmds []tea.Cmd
+
+	// Handle config modal if shown
+	if m.showConfigModal {
+		switch msg := msg.(type) {
+		case config.ConfigCompleteMsg:
+			// Configuration is complete, switch to main app
+			m.config = msg.Config
+			m.showConfigModal = false
+			m.configModal = nil
+			m.initialLoading = true
+			m.loadingProjects = true
+			m.autoDetectDone = false
+			return m, tea.Batch(m.projectsSpinner.Tick, loadProjects(m.config), autoDetectProjectAndRepo(m.config), loadCurrentUser(m.config))
+		case tea.WindowSizeMsg:
+			m.width = msg.Width
+			m.height = msg.Height
+			m.configModal, cmd = m.configModal.Update(msg)
+			return m, cmd
+		default:
+			m.configModal, cmd = m.configModal.Update(msg)
+			if m.configModal.IsCompleted() {
+				if m.config.IsComplete() {
+					m.showConfigModal = false
+					m.configModal = nil
+					m.initialLoading = true
+					m.loadingProjects = true
+					m.autoDetectDone = false
+					return m, tea.Batch(m.projectsSpinner.Tick, loadProjects(m.config), autoDetectProjectAndRepo(m.config), loadCurrentUser(m.config))
 				}
-				m.updateScroll()
-				return m, tea.Batch(cmds...)
-			} else if m.showRuns {
-				m.showRuns = false
-				m.showPipelines = true
-				m.focusedPanel = 2
-				// Find the cursor position for the selected pipeline
-				foundPipeline := false
-				for i, pipeline := range m.pipelines {
-					if m.selectedPipeline != nil && pipeline.Id != nil && m.selectedPipeline.Id != nil && *pipeline.Id == *m.selectedPipeline.Id {
-						m.cursor = i
-						foundPipeline = true
-						break
-					}
+			}
+			return m, cmd
+		}
+	}
+
+	// Update spinners
+	if m.loadingProjects {
+		m.projectsSpinner, cmd = m.projectsSpinner.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	if m.loadingRepos {
+		m.reposSpinner, cmd = m.reposSpinner.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	if m.loadingPipelines || m.loadingRunOptions || m.queueingRun {
+		m.pipelinesSpinner, cmd = m.pipelinesSpinner.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	if m.loadingRuns {
+		m.runsSpinner, cmd = m.runsSpinner.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	if m.loadingTimeline || m.loadingRunLog {
+		m.timelineSpinner, cmd = m.timelineSpinner.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	if m.loadingPRs {
+		m.prsSpinner, cmd = m.prsSpinner.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	if m.loadingPRDetails || m.loadingPRFiles || m.loadingPRDiff {
+		m.prDetailsSpinner, cmd = m.prDetailsSpinner.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
+	switch msg := msg.(type) {
+	case []core.TeamProjectReference:
+		m.projects = msg
+		m.loadingProjects = false
+
+		// Check if we can complete initial loading
+		if m.autoDetectDone {
+			m.initialLoading = false
+		}
+		return m, tea.Batch(cmds...)
+	case autoDetectCompleteMsg:
+		m.autoDetectDone = true
+		m.autoDetectResult = msg.result
+
+		// Check if we can complete initial loading
+		if !m.loadingProjects {
+			m.initialLoading = false
+		}
+
+		if msg.result.ShouldAutoLoad {
+			// Find and select the matching project
+			for i, project := range m.projects {
+				if project.Id != nil && msg.result.Project != nil && msg.result.Project.Id != nil &&
+					*project.Id == *msg.result.Project.Id {
+					m.selectedProject = &m.projects[i]
+					m.cursor = i
+					m.loadingRepos = true
+					m.repos = []git.GitRepository{}
+
+					// Auto-select the repository when repos are loaded
+					m.autoSelectRepo = msg.result.Repository
+
+					cmds = append(cmds, m.reposSpinner.Tick, loadProjectRepos(*m.selectedProject.Name, m.config))
+					break
 				}
-				if !foundPipeline {
+			}
+		}
+		return m, tea.Batch(cmds...)
+	case projectLoadedMsg:
+		m.repos = msg.repos
+		m.loadingRepos = false
+
+		// Auto-select repository if we have one from auto-detection
+		if m.autoSelectRepo != nil {
+			for i, repo := range m.repos {
+				if repo.Id != nil && m.autoSelectRepo.Id != nil && *repo.Id == *m.autoSelectRepo.Id {
+					m.selectedRepo = &m.repos[i]
+					// Instead of showing repo options, directly navigate to pipelines
+					m.showPipelines = true
+					m.focusedPanel = 2
+					m.loadingPipelines = true
+					m.pipelines = []pipeline.Pipeline{}
 					m.cursor = 0
-				}
-				m.updateScroll()
-				return m, tea.Batch(cmds...)
-			} else if m.showPRDetails {
-				m.showPRDetails = false
-				m.showPRs = true
-				// Find the cursor position for the selected PR
-				foundPR := false
-				for i, pr := range m.prs {
-					if m.selectedPR != nil && pr.PullRequestId != nil && m.selectedPR.PullRequestId != nil && *pr.PullRequestId == *m.selectedPR.PullRequestId {
-						m.cursor = i
-						foundPR = true
-						break
+					m.autoSelectRepo = nil // Clear auto-selection
+
+					if m.selectedProject != nil && m.selectedRepo != nil {
+						cmds = append(cmds, m.pipelinesSpinner.Tick, loadRepoPipelines(*m.selectedProject.Name, *m.selectedRepo.Name, m.config))
 					}
+					break
+				}
+			}
+		}
+
+		return m, tea.Batch(cmds...)
+	case pipelinesLoadedMsg:
+		m.pipelines = msg.pipelines
+		m.loadingPipelines = false
+		return m, tea.Batch(cmds...)
+	case runsLoadedMsg:
+		m.runs = msg.runs
+		m.loadingRuns = false
+
+		// Open a run just queued from the run dialog, else auto-select the first in-progress run
+		for i, run := range m.runs {
+			queued := m.queuedRunId != 0 && run.Id != nil && *run.Id == m.queuedRunId
+			if queued || (m.queuedRunId == 0 && run.State != nil && (*run.State == "inProgress" || *run.State == "notStarted")) {
+				m.selectedRun = &m.runs[i]
+				m.showRuns = false
+				m.showRunDetails = true
+				m.loadingTimeline = true
+				m.timeline = nil
+				m.timelineExpanded = map[uuid.UUID]bool{}
+				m.runApprovals = map[uuid.UUID]pipelinesapproval.Approval{}
+				m.cursor = 0
+				m.autoRefresh = true
+				m.autoSelected = !queued
+
+				if m.selectedProject != nil && m.selectedRun.Id != nil {
+					cmds = append(cmds, m.timelineSpinner.Tick, loadRunTimeline(*m.selectedProject.Name, *m.selectedRun.Id, m.config), tick())
+				}
+				break
+			}
+		}
+		m.queuedRunId = 0
+
+		return m, tea.Batch(cmds...)
+	case runOptionsLoadedMsg:
+		// Drop options for a branch the user has since moved away from
+		if !m.showRunPipeline || (m.runBranch != "" && msg.refName != m.runBranch) {
+			return m, tea.Batch(cmds...)
+		}
+		m.loadingRunOptions = false
+		if msg.err != nil {
+			m.runPipelineError = fmt.Sprintf("Failed to load the pipeline: %v", msg.err)
+			return m, tea.Batch(cmds...)
+		}
+		if msg.branches != nil {
+			m.runBranches = msg.branches
+		}
+		m.runBranch = msg.refName
+
+		// Keep the values already entered for parameters the branch still declares
+		values := make(map[string]string)
+		for _, parameter := range msg.parameters {
+			if value, ok := m.runParameterValues[parameter.Name]; ok {
+				values[parameter.Name] = value
+			} else {
+				values[parameter.Name] = parameter.Default
+			}
+		}
+		m.runParameters = msg.parameters
+		m.runParameterValues = values
+
+		m.runVariables = msg.variables
+		for _, variable := range msg.variables {
+			if _, ok := m.runVariableValues[variable.Name]; !ok {
+				m.runVariableValues[variable.Name] = variable.Value
+			}
+		}
+		m.runStages = msg.stages
+		return m, tea.Batch(cmds...)
+	case runQueuedMsg:
+		m.queueingRun = false
+		if msg.err != nil {
+			m.runPipelineError = fmt.Sprintf("Failed to queue the run: %v", msg.err)
+			return m, tea.Batch(cmds...)
+		}
+
+		m.showRunPipeline = false
+		m.showPipelines = false
+		runId := 0
+		if msg.run != nil && msg.run.Id != nil {
+			runId = *msg.run.Id
+		}
+		return m, tea.Batch(append(cmds, m.openQueuedRun(runId))...)
+	case runActionCompleteMsg:
+		m.runActionTime = time.Now()
+		if msg.err != nil {
+			m.runActionMessage = fmt.Sprintf("Failed to %s the run: %v", msg.action, msg.err)
+			return m, tea.Batch(cmds...)
+		}
+		if msg.action == "rerun" {
+			m.runActionMessage = ""
+			m.showRunDetails = false
+			m.showRunLog = false
+			m.autoRefresh = false
+			m.autoSelected = false
+			return m, tea.Batch(append(cmds, m.openQueuedRun(msg.runId))...)
+		}
+
+		// Follow the run as it cancels, the stage reruns or the approved stage starts
+		switch msg.action {
+		case "cancel":
+			m.runActionMessage = "Cancel requested"
+		case "retry":
+			m.runActionMessage = "Stage retry queued"
+		case "approve":
+			m.runActionMessage = "Approved"
+		case "reject":
+			m.runActionMessage = "Rejected"
+		}
+		m.runApprovalMode = false
+		if m.selectedProject != nil && m.selectedRun != nil && m.selectedRun.Id != nil {
+			cmds = append(cmds, loadRunTimeline(*m.selectedProject.Name, *m.selectedRun.Id, m.config))
+			if !m.autoRefresh {
+				m.autoRefresh = true
+				cmds = append(cmds, tick())
+			}
+		}
+		return m, tea.Batch(cmds...)
+	case runApprovalsLoadedMsg:
+		if msg.err == nil {
+			for _, approval := range msg.approvals {
+				if approval.Id != nil {
+					m.runApprovals[*approval.Id] = approval
+				}
+			}
+		}
+		return m, tea.Batch(cmds...)
+	case timelineLoadedMsg:
+		// Keep the cursor on the same record as the tree changes
+		var selectedId *uuid.UUID
+		if rows := m.timelineRows(); m.cursor < len(rows) {
+			selectedId = rows[m.cursor].Node.Record.Id
+		}
+		m.timeline = msg.timeline
+		m.loadingTimeline = false
+		m.lastRefresh = time.Now()
+		if !m.runInProgress() {
+			// Nothing left to cancel
+			m.runCancelConfirm = false
+		}
+
+		// Fetch the approvals the run is waiting on, they can change without the timeline changing
+		var approvalIds []uuid.UUID
+		for _, record := range m.runPendingChecks() {
+			if *record.Type == "Checkpoint.Approval" {
+				approvalIds = append(approvalIds, *record.Id)
+			}
+		}
+		if len(approvalIds) > 0 && m.selectedProject != nil {
+			cmds = append(cmds, loadRunApprovals(*m.selectedProject.Name, approvalIds, m.config))
+		}
+		if checks := m.runPendingChecks(); m.showRunApprovals && !m.runApprovalMode && m.cursor >= len(checks) {
+			m.cursor = max(len(checks)-1, 0)
+		}
+		if selectedId != nil {
+			for i, row := range m.timelineRows() {
+				if row.Node.Record.Id != nil && *row.Node.Record.Id == *selectedId {
+					m.cursor = i
+					break
+				}
+			}
+		}
+
+		// Pick up a log the step has started writing, and the rest of it once the step finishes
+		if record := m.runLogRecord(); m.showRunLog && record != nil && m.selectedProject != nil && m.selectedRun != nil && m.selectedRun.Id != nil {
+			wasTailing := m.runLogTailing
+			m.runLogTailing = record.State != nil && *record.State == build.TimelineRecordStateValues.InProgress
+			if m.runLogId == 0 && record.Log != nil && record.Log.Id != nil {
+				m.runLogId = *record.Log.Id
+				cmds = append(cmds, loadRunLog(*m.selectedProject.Name, *m.selectedRun.Id, m.runLogId, 0, m.config))
+			} else if wasTailing && !m.runLogTailing && m.runLogId != 0 {
+				cmds = append(cmds, loadRunLog(*m.selectedProject.Name, *m.selectedRun.Id, m.runLogId, len(m.runLogLines), m.config))
+			}
+		}
+		return m, tea.Batch(cmds...)
+	case runLogLoadedMsg:
+		// Drop results for another log, or a range that an earlier fetch already appended
+		if !m.showRunLog || msg.logID != m.runLogId || msg.startLine != len(m.runLogLines) {
+			return m, tea.Batch(cmds...)
+		}
+		m.loadingRunLog = false
+		if msg.err == nil {
+			m.runLogLines = append(m.runLogLines, msg.lines...)
+		}
+		return m, tea.Batch(cmds...)
+	case prsLoadedMsg:
+		m.prs = msg.prs
+		m.loadingPRs = false
+		m.prsError = ""
+		if msg.err != nil {
+			m.prsError = fmt.Sprintf("Failed to load pull requests: %v", msg.err)
+			return m, tea.Batch(cmds...)
+		}
+
+		// Offer the labels of an unfiltered list, keeping the others while a label is picked
+		if m.prLabelFilter == "" {
+			m.prLabelOptions = []string{}
+		}
+		for _, pr := range m.prs {
+			for _, name := range prs.LabelNames(pr) {
+				if !containsFold(m.prLabelOptions, name) {
+					m.prLabelOptions = append(m.prLabelOptions, name)
+				}
+			}
+		}
+		sort.Slice(m.prLabelOptions, func(i, j int) bool {
+			return strings.ToLower(m.prLabelOptions[i]) < strings.ToLower(m.prLabelOptions[j])
+		})
+		return m, tea.Batch(cmds...)
+	case branchesLoadedMsg:
+		m.branches = msg.branches
+		m.loadingBranches = false
+
+		// Set default branches if in PR create mode
+		if m.prCreateMode {
+			// Target the requested branch or the repository's default, falling back to main/master
+			if m.prTargetBranch == nil {
+				if m.prDefaultTarget != "" {
+					m.prTargetBranch = m.findBranch(m.prDefaultTarget)
+				}
+				if m.prTargetBranch == nil && m.selectedRepo != nil && m.selectedRepo.DefaultBranch != nil {
+					m.prTargetBranch = m.findBranch(*m.selectedRepo.DefaultBranch)
+				}
+				if m.prTargetBranch == nil {
+					m.prTargetBranch = m.findBranch("refs/heads/main")
+				}
+				if m.prTargetBranch == nil {
+					m.prTargetBranch = m.findBranch("refs/heads/master")
+				}
+				cmds = append(cmds, m.loadTargetPRTemplate())
+			}
+
+			cmds = append(cmds, m.selectDefaultSource())
+		}
+		return m, tea.Batch(cmds...)
+	case usersLoadedMsg:
+		m.users = msg.users
+		m.loadingUsers = false
+
+		// Initialize filtered reviewers if a reviewer picker is open
+		if m.prCreateMode || m.prReviewerMode {
+			m.filterReviewers()
+		}
+		return m, tea.Batch(cmds...)
+	case currentUserLoadedMsg:
+		m.currentUser = msg.user
+		return m, tea.Batch(cmds...)
+	case prTemplateLoadedMsg:
+		// Only fill the description while it is empty or still holds an earlier template or commit list
+		if m.prCreateMode && m.prTargetBranch != nil && m.prTargetBranch.Name != nil && *m.prTargetBranch.Name == msg.targetRefName && msg.template != "" {
+			if desc := m.prDescInput.Value(); desc == "" || desc == m.prDescTemplate || desc == m.prGeneratedDesc {
+				m.prDescInput.SetValue(msg.template)
+				m.prDescTemplate = msg.template
+			}
+		}
+		return m, tea.Batch(cmds...)
+	case localBranchLoadedMsg:
+		if !m.prCreateMode || m.selectedProject == nil || m.selectedRepo == nil || m.selectedRepo.Id == nil {
+			return m, tea.Batch(cmds...)
+		}
+		refName := "refs/heads/" + msg.branch
+		if msg.branch == "" || (m.selectedRepo.DefaultBranch != nil && refName == *m.selectedRepo.DefaultBranch) {
+			// Detached or on the default branch, so there is nothing to propose from the checkout
+			return m, tea.Batch(append(cmds, loadLatestBranch(*m.selectedProject.Name, m.selectedRepo.Id.String(), m.config))...)
+		}
+		m.prLocalBranch = msg.branch
+		m.prDefaultSource = refName
+		return m, tea.Batch(append(cmds, m.selectDefaultSource())...)
+	case latestBranchLoadedMsg:
+		if m.prCreateMode && msg.branch != nil && msg.branch.Name != nil {
+			m.prDefaultSource = *msg.branch.Name
+			cmds = append(cmds, m.selectDefaultSource())
+		}
+		return m, tea.Batch(cmds...)
+	case branchPushedMsg:
+		m.pushingBranch = false
+		if msg.err != nil {
+			log.Printf("Error pushing branch: %v", msg.err)
+			m.prActionMessage = fmt.Sprintf("Failed to push %s: %v", msg.branch, msg.err)
+			m.prActionTime = time.Now()
+			return m, tea.Batch(cmds...)
+		}
+		m.prActionMessage = fmt.Sprintf("Pushed %s", msg.branch)
+		m.prActionTime = time.Now()
+
+		// Reload branches so the pushed branch can be selected as the source
+		if m.prCreateMode && m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil {
+			m.loadingBranches = true
+			cmds = append(cmds, loadRepoBranches(*m.selectedProject.Name, m.selectedRepo.Id.String(), m.config))
+		}
+		return m, tea.Batch(cmds...)
+	case prCommitsLoadedMsg:
+		// Drop results for a branch pair the user has since moved away from
+		if m.prCreateMode && m.prSourceBranch != nil && m.prSourceBranch.Name != nil && m.prTargetBranch != nil && m.prTargetBranch.Name != nil &&
+			*m.prSourceBranch.Name == msg.sourceRefName && *m.prTargetBranch.Name == msg.targetRefName {
+			m.prCommits = msg.commits
+			m.loadingPRCommits = false
+			m.applyCommitSuggestion(false)
+		}
+		return m, tea.Batch(cmds...)
+	case prDescEditedMsg:
+		defer os.Remove(msg.path)
+		if msg.err != nil {
+			log.Printf("Error editing PR description: %v", msg.err)
+			return m, tea.Batch(cmds...)
+		}
+		edited, err := os.ReadFile(msg.path)
+		if err != nil {
+			log.Printf("Error reading edited PR description: %v", err)
+			return m, tea.Batch(cmds...)
+		}
+		m.prDescInput.SetValue(strings.TrimRight(string(edited), "\n"))
+		return m, tea.Batch(cmds...)
+	case prCreatedMsg:
+		// PR created successfully, refresh the PR list
+		if m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil {
+			m.showPRCreate = false
+			m.prCreateMode = false
+			m.loadingPRs = true
+			repoID := m.selectedRepo.Id.String()
+			return m, tea.Batch(append(cmds, m.prsSpinner.Tick, loadRepoPRs(*m.selectedProject.Name, repoID, m.prFilter(), m.config))...)
+		}
+		return m, tea.Batch(cmds...)
+	case prDetailsLoadedMsg:
+		m.prDetails = msg.pr
+		m.loadingPRDetails = false
+		return m, tea.Batch(cmds...)
+	case prCommentsLoadedMsg:
+		m.prComments = msg.comments
+		m.loadingPRComments = false
+		return m, tea.Batch(cmds...)
+	case dashboardPRsLoadedMsg:
+		m.dashboardPRs = msg.prs
+		m.loadingDashboard = false
+		return m, tea.Batch(cmds...)
+	case workItemsSearchedMsg:
+		// Ignore results for a query the user has since changed
+		if msg.query == m.workItemSearched {
+			m.workItemResults = msg.items
+			m.searchingWorkItems = false
+			m.cursor = 0
+		}
+		return m, tea.Batch(cmds...)
+	case prWorkItemsLoadedMsg:
+		m.prLinkedWorkItems = msg.items
+		return m, tea.Batch(cmds...)
+	case prPoliciesLoadedMsg:
+		m.prPolicies = msg.evaluations
+		return m, tea.Batch(cmds...)
+	case prIterationsLoadedMsg:
+		// Newest first, as the latest pushes are the ones left to review
+		m.prIterations = make([]git.GitPullRequestIteration, 0, len(msg.iterations))
+		for i := len(msg.iterations) - 1; i >= 0; i-- {
+			m.prIterations = append(m.prIterations, msg.iterations[i])
+		}
+		m.loadingPRIterations = false
+		return m, tea.Batch(cmds...)
+	case prFilesLoadedMsg:
+		m.prFiles = msg.files
+		m.loadingPRFiles = false
+		return m, tea.Batch(cmds...)
+	case prDiffLoadedMsg:
+		// Ignore stale diffs if the user already moved on to another file
+		if m.selectedPRFile != nil && m.selectedPRFile.Path == msg.path {
+			m.prDiffLines = msg.lines
+			m.prDiffBinary = msg.binary
+			m.prDiffError = ""
+			if msg.err != nil {
+				m.prDiffError = fmt.Sprintf("Failed to load diff: %v", msg.err)
+			}
+			m.loadingPRDiff = false
+		}
+		return m, tea.Batch(cmds...)
+	case prActionCompleteMsg:
+		// Store action message and timestamp
+		m.prActionMessage = msg.message
+		m.prActionTime = time.Now()
+
+		// PR action completed, refresh PR details and list
+		if msg.success && m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil {
+			m.showPRReview = false
+			m.prReviewMode = false
+			m.prOverrideMode = false
+			m.prReplyMode = false
+			m.prReplyInline = false
+			m.prThreadStatusMode = false
+			m.prVoteMode = false
+			m.prAbandonConfirm = false
+			m.prLinkWorkItemMode = false
+			m.prCompleteMode = false
+			m.prDiffAnchor = -1
+			if m.prReviewerMode || m.prLabelMode {
+				// The reviewer or label list is reloaded and may have shrunk
+				m.cursor = 0
+			}
+			repoID := m.selectedRepo.Id.String()
+
+			// Refresh PR details, comments and PR list
+			if m.selectedPR != nil && m.selectedPR.PullRequestId != nil {
+				return m, tea.Batch(append(cmds,
+					loadPRDetails(*m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, m.config),
+					loadPRComments(*m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, m.config),
+					m.loadSelectedPRPolicies(),
+					m.loadSelectedPRWorkItems(),
+					loadRepoPRs(*m.selectedProject.Name, repoID, m.prFilter(), m.config))...)
+			}
+		}
+		return m, tea.Batch(cmds...)
+	case prCherryPickStatusMsg:
+		// Drop polls of an operation that has since been replaced
+		if !m.prCherryPickRunning || (m.prCherryPickID != 0 && msg.id != m.prCherryPickID) {
+			return m, tea.Batch(cmds...)
+		}
+		kind := strings.ToLower(m.cherryPickKind())
+		if msg.err != nil {
+			m.prCherryPickRunning = false
+			m.prCherryPickError = msg.err.Error()
+			m.prActionMessage = fmt.Sprintf("Failed to %s: %v", kind, msg.err)
+			m.prActionTime = time.Now()
+			return m, tea.Batch(cmds...)
+		}
+
+		m.prCherryPickID = msg.id
+		m.prCherryPickStatus = msg.status
+		switch msg.status {
+		case git.GitAsyncOperationStatusValues.Completed:
+			m.prCherryPickRunning = false
+			// Remember the new branch so another cherry-pick onto the same branch picks a new name
+			m.prCherryPickCreated[m.prCherryPickRef] = true
+			m.prActionMessage = fmt.Sprintf("Created %s", strings.TrimPrefix(m.prCherryPickRef, "refs/heads/"))
+			m.prActionTime = time.Now()
+		case git.GitAsyncOperationStatusValues.Failed, git.GitAsyncOperationStatusValues.Abandoned:
+			m.prCherryPickRunning = false
+			m.prCherryPickError = cherryPickFailure(msg.status, msg.detail)
+			m.prActionMessage = fmt.Sprintf("Failed to %s: %s", kind, m.prCherryPickError)
+			m.prActionTime = time.Now()
+		default:
+			if m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil {
+				cmds = append(cmds, pollCherryPick(*m.selectedProject.Name, m.selectedRepo.Id.String(), msg.id, m.prCherryPickRevert, 2*time.Second, m.config))
+			}
+		}
+		return m, tea.Batch(cmds...)
+	case autoRefreshMsg:
+		if m.autoRefresh && (m.showRunDetails || m.showRunLog || m.showRunApprovals) && m.selectedProject != nil && m.selectedRun != nil && m.selectedRun.Id != nil {
+			// A running step's log only gains lines, so fetch just the ones after those shown
+			if m.showRunLog && m.runLogTailing && m.runLogId != 0 && !m.loadingRunLog {
+				cmds = append(cmds, loadRunLog(*m.selectedProject.Name, *m.selectedRun.Id, m.runLogId, len(m.runLogLines), m.config))
+			}
+			return m, tea.Batch(append(cmds, loadRunTimeline(*m.selectedProject.Name, *m.selectedRun.Id, m.config), tick())...)
+		}
+		return m, tea.Batch(cmds...)
+	case refreshMsg:
+		if m.showRunLog && m.runLogId != 0 && m.selectedProject != nil && m.selectedRun != nil && m.selectedRun.Id != nil {
+			m.runLogLines = []string{}
+			m.loadingRunLog = true
+			return m, tea.Batch(append(cmds, m.timelineSpinner.Tick, loadRunLog(*m.selectedProject.Name, *m.selectedRun.Id, m.runLogId, 0, m.config))...)
+		} else if (m.showRunDetails || m.showRunApprovals) && m.selectedProject != nil && m.selectedRun != nil && m.selectedRun.Id != nil {
+			m.loadingTimeline = true
+			return m, tea.Batch(append(cmds, m.timelineSpinner.Tick, loadRunTimeline(*m.selectedProject.Name, *m.selectedRun.Id, m.config))...)
+		}
+		return m, tea.Batch(cmds...)
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, tea.Batch(cmds...)
+	case tea.KeyMsg:
+		m.dropPendingConfirms(msg.String())
+
+		if m.prCreateMode && msg.String() == "ctrl+p" && m.localBranchUnpushed() && !m.pushingBranch {
+			// Push the local branch so it can be used as the source
+			m.pushingBranch = true
+			return m, tea.Batch(append(cmds, pushBranch(m.prLocalBranch))...)
+		}
+		if m.prCreateMode && msg.String() == "ctrl+g" {
+			// Replace the title and description with the ones suggested by the commits
+			m.applyCommitSuggestion(true)
+			return m, tea.Batch(cmds...)
+		}
+
+		// Send typing to the focused text input before any shortcut handling
+		if m.prReplyMode || m.prOverrideMode || m.runApprovalMode || (m.prCreateMode && (m.prCreateStep == 0 || m.prCreateStep == 1)) {
+			key := msg.String()
+			editingDesc := m.prCreateMode && m.prCreateStep == 1
+			if editingDesc && key == "ctrl+e" {
+				// Hand the description over to the user's editor
+				return m, tea.Batch(append(cmds, m.editPRDescription())...)
+			}
+			// Enter starts a new line in the description rather than submitting
+			if (key != "enter" || editingDesc) && key != "esc" && key != "escape" && key != "ctrl+c" && !(m.prCreateMode && key == "tab") {
+				var inputCmd tea.Cmd
+				if m.prReplyMode {
+					m.prCommentInput, inputCmd = m.prCommentInput.Update(msg)
+				} else if m.prOverrideMode {
+					m.prOverrideInput, inputCmd = m.prOverrideInput.Update(msg)
+				} else if m.runApprovalMode {
+					m.runApprovalInput, inputCmd = m.runApprovalInput.Update(msg)
+				} else if m.prCreateStep == 0 {
+					m.prTitleInput, inputCmd = m.prTitleInput.Update(msg)
+				} else {
+					m.prDescInput, inputCmd = m.prDescInput.Update(msg)
+				}
+				return m, tea.Batch(append(cmds, inputCmd)...)
+			}
+		}
+
+		// The completion dialog owns the keyboard while it is open
+		if m.prCompleteMode {
+			return m.updatePRCompleteDialog(msg, cmds)
+		}
+
+		// Handle the run pipeline dialog
+		if m.showRunPipeline && !m.searchMode {
+			key := msg.String()
+			switch key {
+			case "tab":
+				m.runPipelineStep = (m.runPipelineStep + 1) % 4
+				m.cursor = 0
+				return m, tea.Batch(cmds...)
+			case "shift+tab":
+				m.runPipelineStep = (m.runPipelineStep + 3) % 4
+				m.cursor = 0
+				return m, tea.Batch(cmds...)
+			case "up":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+				return m, tea.Batch(cmds...)
+			case "down":
+				if m.cursor < m.runPipelineItems()-1 {
+					m.cursor++
+				}
+				return m, tea.Batch(cmds...)
+			case "backspace":
+				m.backspaceRunPipeline()
+				return m, tea.Batch(cmds...)
+			case " ":
+				if m.runPipelineStep == 3 && m.cursor < len(m.runStages) {
+					stage := m.runStages[m.cursor]
+					m.runSkipStages[stage] = !m.runSkipStages[stage]
+					return m, tea.Batch(cmds...)
+				}
+				if m.runPipelineStep == 1 && m.cursor < len(m.runParameters) && len(m.runParameters[m.cursor].Values) > 0 {
+					// Cycle through the allowed values
+					parameter := m.runParameters[m.cursor]
+					next := 0
+					for i, value := range parameter.Values {
+						if value == m.runParameterValues[parameter.Name] {
+							next = (i + 1) % len(parameter.Values)
+							break
+						}
+					}
+					m.runParameterValues[parameter.Name] = parameter.Values[next]
+					return m, tea.Batch(cmds...)
+				}
+				m.typeRunPipeline(" ")
+				return m, tea.Batch(cmds...)
+			case "enter":
+				if m.loadingRunOptions || m.queueingRun {
+					return m, tea.Batch(cmds...)
+				}
+				if m.runPipelineStep == 0 {
+					// Read the parameters and stages of the picked branch
+					branches := m.runFilteredBranches()
+					if m.cursor < len(branches) && m.selectedProject != nil && m.selectedPipeline != nil && m.selectedPipeline.Id != nil {
+						m.runBranch = *branches[m.cursor].Name
+						m.runBranchFilter = ""
+						m.runPipelineStep = 1
+						m.cursor = 0
+						m.loadingRunOptions = true
+						m.runPipelineError = ""
+						return m, tea.Batch(append(cmds, m.pipelinesSpinner.Tick, loadRunOptions(*m.selectedProject.Name, *m.selectedPipeline.Id, m.runBranch, m.config))...)
+					}
+					return m, tea.Batch(cmds...)
+				}
+				if m.runBranch != "" {
+					m.queueingRun = true
+					m.runPipelineError = ""
+					return m, tea.Batch(append(cmds, m.pipelinesSpinner.Tick, m.queueRun())...)
+				}
+				return m, tea.Batch(cmds...)
+			case "escape", "esc", "left", "ctrl+c":
+			default:
+				if len(msg.Runes) > 0 {
+					m.typeRunPipeline(string(msg.Runes))
+				}
+				return m, tea.Batch(cmds...)
+			}
+		}
+
+		// Handle the cherry-pick and revert branch picker
+		if m.prCherryPickMode && !m.searchMode {
+			key := msg.String()
+			switch key {
+			case "backspace":
+				if len(m.prCherryPickFilter) > 0 {
+					filterRunes := []rune(m.prCherryPickFilter)
+					m.prCherryPickFilter = string(filterRunes[:len(filterRunes)-1])
+					m.cursor = 0
+				}
+				return m, tea.Batch(cmds...)
+			case "tab":
+				if !m.prCherryPickRunning {
+					m.prCherryPickRevert = !m.prCherryPickRevert
+					m.cursor = 0
+				}
+				return m, tea.Batch(cmds...)
+			case "enter":
+				if m.prCherryPickRunning {
+					return m, tea.Batch(cmds...)
+				}
+				if m.prCherryPickID != 0 && m.prCherryPickStatus == git.GitAsyncOperationStatusValues.Completed {
+					return m.openCherryPickPR(cmds)
+				}
+				branches := m.cherryPickBranches()
+				if m.cursor < len(branches) && m.selectedPR != nil && m.selectedPR.PullRequestId != nil {
+					m.prCherryPickOnto = *branches[m.cursor].Name
+					m.prCherryPickRef = cherryPickRefName(m.prCherryPickRevert, *m.selectedPR.PullRequestId, m.prCherryPickOnto, m.branches, m.prCherryPickCreated)
+					m.prCherryPickRunning = true
+					m.prCherryPickID = 0
+					m.prCherryPickStatus = git.GitAsyncOperationStatusValues.Queued
+					m.prCherryPickError = ""
+					return m, tea.Batch(append(cmds, m.startCherryPick())...)
+				}
+				return m, tea.Batch(cmds...)
+			case "up":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+				return m, tea.Batch(cmds...)
+			case "down":
+				if m.cursor < len(m.cherryPickBranches())-1 {
+					m.cursor++
+				}
+				return m, tea.Batch(cmds...)
+			case "escape", "esc", "left", "ctrl+c":
+			default:
+				if len(msg.Runes) > 0 && !m.prCherryPickRunning {
+					m.prCherryPickFilter += string(msg.Runes)
+					m.cursor = 0
+				}
+				return m, tea.Batch(cmds...)
+			}
+		}
+
+		// Handle the label editor of an existing PR
+		if m.prLabelMode && !m.searchMode {
+			key := msg.String()
+			switch key {
+			case "backspace":
+				if len(m.prLabelInput) > 0 {
+					inputRunes := []rune(m.prLabelInput)
+					m.prLabelInput = string(inputRunes[:len(inputRunes)-1])
+				}
+				return m, tea.Batch(cmds...)
+			case "enter":
+				// Add the typed label
+				if name := strings.TrimSpace(m.prLabelInput); name != "" {
+					m.prLabelInput = ""
+					return m, tea.Batch(append(cmds, m.addLabel(name))...)
+				}
+				return m, tea.Batch(cmds...)
+			case "ctrl+d":
+				// Remove the highlighted label
+				if labels := m.prDetailLabels(); m.prLabelInput == "" && m.cursor < len(labels) {
+					return m, tea.Batch(append(cmds, m.removeLabel(labels[m.cursor]))...)
+				}
+				return m, tea.Batch(cmds...)
+			case "up":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+				return m, tea.Batch(cmds...)
+			case "down":
+				if m.cursor < len(m.prDetailLabels())-1 {
+					m.cursor++
+				}
+				return m, tea.Batch(cmds...)
+			case "tab", "escape", "esc", "left", "ctrl+c":
+			default:
+				if len(msg.Runes) > 0 {
+					m.prLabelInput += string(msg.Runes)
+					return m, tea.Batch(cmds...)
+				}
+			}
+		}
+
+		// Handle the reviewer editor of an existing PR
+		if m.prReviewerMode && !m.searchMode {
+			key := msg.String()
+			if key != "ctrl+d" {
+				m.prReviewerRemoveConfirm = ""
+			}
+			switch key {
+			case "backspace":
+				if len(m.reviewerSearch) > 0 {
+					searchRunes := []rune(m.reviewerSearch)
+					m.reviewerSearch = string(searchRunes[:len(searchRunes)-1])
+					m.filterReviewers()
+					m.cursor = 0
+				}
+				return m, tea.Batch(cmds...)
+			case "ctrl+r":
+				if m.reviewerSearch != "" {
+					// Add the highlighted result as required
+					if m.cursor < len(m.filteredReviewers) {
+						reviewer := m.filteredReviewers[m.cursor]
+						m.reviewerSearch = ""
+						m.filterReviewers()
+						m.cursor = 0
+						return m, tea.Batch(append(cmds, m.setReviewer(reviewer.Id, reviewer.DisplayName, true))...)
+					}
+				} else if reviewers := m.prDetailReviewers(); m.cursor < len(reviewers) && reviewers[m.cursor].Id != nil {
+					// Toggle whether the highlighted reviewer is required
+					reviewer := reviewers[m.cursor]
+					required := reviewer.IsRequired == nil || !*reviewer.IsRequired
+					name := ""
+					if reviewer.DisplayName != nil {
+						name = *reviewer.DisplayName
+					}
+					return m, tea.Batch(append(cmds, m.setReviewer(*reviewer.Id, name, required))...)
+				}
+				return m, tea.Batch(cmds...)
+			case "ctrl+d":
+				// Remove the highlighted reviewer
+				if reviewers := m.prDetailReviewers(); m.reviewerSearch == "" && m.cursor < len(reviewers) && reviewers[m.cursor].Id != nil {
+					reviewer := reviewers[m.cursor]
+					// Removing a required reviewer needs a second press to confirm
+					if reviewer.IsRequired != nil && *reviewer.IsRequired && m.prReviewerRemoveConfirm != *reviewer.Id {
+						m.prReviewerRemoveConfirm = *reviewer.Id
+						return m, tea.Batch(cmds...)
+					}
+					m.prReviewerRemoveConfirm = ""
+					name := ""
+					if reviewer.DisplayName != nil {
+						name = *reviewer.DisplayName
+					}
+					return m, tea.Batch(append(cmds, m.removeReviewer(*reviewer.Id, name))...)
+				}
+				return m, tea.Batch(cmds...)
+			case "enter":
+				// Add the highlighted result as optional
+				if m.reviewerSearch != "" && m.cursor < len(m.filteredReviewers) {
+					reviewer := m.filteredReviewers[m.cursor]
+					m.reviewerSearch = ""
+					m.filterReviewers()
+					m.cursor = 0
+					return m, tea.Batch(append(cmds, m.setReviewer(reviewer.Id, reviewer.DisplayName, false))...)
+				}
+				return m, tea.Batch(cmds...)
+			case "up", "down", "tab", "escape", "esc", "left", "ctrl+c":
+			default:
+				if len(msg.Runes) > 0 {
+					m.reviewerSearch += string(msg.Runes)
+					m.filterReviewers()
+					m.cursor = 0
+					return m, tea.Batch(cmds...)
+				}
+			}
+		}
+
+		// Handle character input for work item search
+		if ((m.prCreateMode && m.prCreateStep == 5) || m.prLinkWorkItemMode) && !m.searchMode {
+			key := msg.String()
+			if key == "backspace" {
+				if len(m.workItemSearch) > 0 {
+					searchRunes := []rune(m.workItemSearch)
+					m.workItemSearch = string(searchRunes[:len(searchRunes)-1])
+				} else if m.prCreateMode && len(m.prWorkItems) > 0 {
+					// Remove the last linked work item
+					m.prWorkItems = m.prWorkItems[:len(m.prWorkItems)-1]
+				}
+				return m, tea.Batch(cmds...)
+			} else if len(msg.Runes) > 0 && key != "up" && key != "down" && key != "enter" &&
+				key != "tab" && key != "escape" && key != "esc" && key != "ctrl+c" {
+				m.workItemSearch += string(msg.Runes)
+				return m, tea.Batch(cmds...)
+			}
+		}
+
+		// Handle character input for reviewer search
+		if m.prCreateMode && m.prCreateStep == 4 && !m.searchMode {
+			key := msg.String()
+			if key == "ctrl+r" {
+				// Add the highlighted reviewer as required, or toggle an added one
+				if m.cursor < len(m.filteredReviewers) {
+					m.addPRReviewer(m.filteredReviewers[m.cursor], true)
+				}
+				return m, tea.Batch(cmds...)
+			} else if key == "backspace" {
+				if len(m.reviewerSearch) > 0 {
+					m.reviewerSearch = m.reviewerSearch[:len(m.reviewerSearch)-1]
+					m.filterReviewers()
+					m.cursor = 0
+				} else if len(m.prReviewers) > 0 {
+					// Remove the last added reviewer
+					m.prReviewers = m.prReviewers[:len(m.prReviewers)-1]
+				}
+				return m, tea.Batch(cmds...)
+			} else if len(msg.Runes) > 0 && key != "up" && key != "down" && key != "enter" &&
+				key != "tab" && key != "escape" && key != "esc" && key != "q" && key != "ctrl+c" {
+				m.reviewerSearch += string(msg.Runes)
+				m.filterReviewers()
+				m.cursor = 0
+				return m, tea.Batch(cmds...)
+			}
+		}
+
+		if m.searchMode {
+			switch msg.String() {
+			case "escape", "esc":
+				m.searchMode = false
+				m.searchQuery = ""
+				m.filteredItems = nil
+				m.cursor = m.originalCursor
+				m.updateScroll()
+				return m, tea.Batch(cmds...)
+			case "enter":
+				if len(m.filteredItems) > 0 && m.cursor < len(m.filteredItems) {
+					selected := m.filteredItems[m.cursor]
+					m.searchMode = false
+					m.searchQuery = ""
+					m.filteredItems = nil
+
+					if m.focusedPanel == 0 {
+						if project, ok := selected.(core.TeamProjectReference); ok {
+							for i, p := range m.projects {
+								if p.Id != nil && project.Id != nil && *p.Id == *project.Id {
+									m.cursor = i
+									m.selectedProject = &m.projects[i]
+									m.loadingRepos = true
+									m.repos = []git.GitRepository{}
+									m.updateScroll()
+									return m, tea.Batch(append(cmds, m.reposSpinner.Tick, loadProjectRepos(*m.selectedProject.Name, m.config))...)
+								}
+							}
+						}
+					} else if m.focusedPanel == 1 {
+						if repo, ok := selected.(git.GitRepository); ok {
+							for i, r := range m.repos {
+								if r.Id != nil && repo.Id != nil && *r.Id == *repo.Id {
+									m.cursor = i
+									m.selectedRepo = &m.repos[i]
+									m.showRepoOptions = true
+									m.cursor = 0
+									m.updateScroll()
+									return m, tea.Batch(cmds...)
+								}
+							}
+						}
+					}
+				}
+				return m, tea.Batch(cmds...)
+			case "backspace":
+				if len(m.searchQuery) > 0 {
+					m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
+					m.filterItems()
+					m.cursor = 0
+				}
+				return m, tea.Batch(cmds...)
+			case "up", "k":
+				if m.cursor > 0 {
+					m.cursor--
+				}
+				return m, tea.Batch(cmds...)
+			case "down", "j":
+				if m.cursor < len(m.filteredItems)-1 {
+					m.cursor++
+				}
+				return m, tea.Batch(cmds...)
+			default:
+				if len(msg.Runes) > 0 {
+					m.searchQuery += string(msg.Runes)
+					m.filterItems()
+					m.cursor = 0
+				}
+				return m, tea.Batch(cmds...)
+			}
+		}
+
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return m, tea.Quit
+		case "r":
+			if m.showRunDetails || m.showRunLog || m.showRunApprovals {
+				return m, tea.Batch(append(cmds, func() tea.Msg { return refreshMsg{} })...)
+			} else if m.showPRDetails && m.prDetails != nil && m.prDetails.Status != nil &&
+				*m.prDetails.Status == git.PullRequestStatusValues.Abandoned {
+				return m, tea.Batch(append(cmds, m.changePRState("reactivate"))...)
+			} else if m.showPRDetails && !m.prVoteMode && m.prDetails != nil && m.prDetails.Status != nil &&
+				*m.prDetails.Status == git.PullRequestStatusValues.Completed {
+				return m, tea.Batch(append(cmds, m.openCherryPick(true))...)
+			} else if m.showPRThreads && !m.prThreadStatusMode {
+				// Reply to the selected thread
+				threads := m.visibleThreads()
+				if m.cursor < len(threads) && threads[m.cursor].Id != nil {
+					m.prReplyMode = true
+					m.prReplyInline = false
+					m.prReplyThreadId = *threads[m.cursor].Id
+					m.prCommentInput = textinput.New()
+					m.prCommentInput.Placeholder = "Write a reply..."
+					m.prCommentInput.Focus()
+					m.prCommentInput.Width = 50
+					return m, tea.Batch(cmds...)
+				}
+			}
+		case "/":
+			if !m.showRepoOptions {
+				m.searchMode = true
+				m.searchQuery = ""
+				m.originalCursor = m.cursor
+				m.cursor = 0
+				m.filterItems()
+				return m, tea.Batch(cmds...)
+			}
+		case "escape", "esc", "backspace":
+			if m.prOverrideMode {
+				// Exit override mode
+				m.showPRReview = false
+				m.prOverrideMode = false
+				return m, tea.Batch(cmds...)
+			} else if m.runApprovalMode {
+				m.runApprovalMode = false
+				m.runApprovalInput.Blur()
+				return m, tea.Batch(cmds...)
+			} else if m.prReplyMode {
+				m.prReplyMode = false
+				m.prReplyInline = false
+				m.prCommentInput.Blur()
+				return m, tea.Batch(cmds...)
+			} else if m.showPRDiff && m.prDiffAnchor >= 0 {
+				// Clear the line range selection
+				m.prDiffAnchor = -1
+				return m, tea.Batch(cmds...)
+			} else if m.prThreadStatusMode {
+				m.prThreadStatusMode = false
+				return m, tea.Batch(cmds...)
+			} else if m.prVoteMode {
+				m.prVoteMode = false
+				return m, tea.Batch(cmds...)
+			} else if m.prAbandonConfirm {
+				m.prAbandonConfirm = false
+				return m, tea.Batch(cmds...)
+			} else if m.prLinkWorkItemMode {
+				m.prLinkWorkItemMode = false
+				m.cursor = 0
+				return m, tea.Batch(cmds...)
+			} else if m.prReviewerMode {
+				m.prReviewerMode = false
+				m.reviewerSearch = ""
+				m.cursor = 0
+				return m, tea.Batch(cmds...)
+			} else if m.prLabelMode {
+				m.prLabelMode = false
+				m.prLabelInput = ""
+				m.cursor = 0
+				return m, tea.Batch(cmds...)
+			} else if m.showRunPipeline {
+				m.closeRunPipeline()
+				return m, tea.Batch(cmds...)
+			} else if m.runCancelConfirm {
+				m.runCancelConfirm = false
+				return m, tea.Batch(cmds...)
+			} else if m.prCherryPickMode {
+				// A running operation keeps being polled and reports in the details
+				m.prCherryPickMode = false
+				m.prCherryPickFilter = ""
+				m.cursor = 0
+				return m, tea.Batch(cmds...)
+			} else if m.showPRThreads {
+				m.showPRThreads = false
+				m.showPRDetails = true
+				m.cursor = 0
+				return m, tea.Batch(cmds...)
+			} else if m.prCreateMode {
+				// Exit PR creation mode
+				m.showPRCreate = false
+				m.prCreateMode = false
+				m.prCreateStep = 0
+				m.cursor = 0
+				return m, tea.Batch(cmds...)
+			} else if m.showPRDiff || m.showPRFiles || m.showPRIterations {
+				m.leavePRFileView()
+				return m, tea.Batch(cmds...)
+			} else if m.showRunApprovals {
+				m.closeRunApprovals()
+				return m, tea.Batch(cmds...)
+			} else if m.showRunLog {
+				m.showRunLog = false
+				m.showRunDetails = true
+				m.loadingRunLog = false
+				return m, tea.Batch(cmds...)
+			} else if m.showRunDetails {
+				m.showRunDetails = false
+				m.showRuns = true
+				m.autoRefresh = false
+				m.autoSelected = false
+				m.runActionMessage = ""
+				// Find the cursor position for the selected run
+				foundRun := false
+				for i, run := range m.runs {
+					if m.selectedRun != nil && run.Id != nil && m.selectedRun.Id != nil && *run.Id == *m.selectedRun.Id {
+						m.cursor = i
+						foundRun = true
+						break
+					}
+				}
+				if !foundRun {
+					m.cursor = 0
+				}
+				m.updateScroll()
+				return m, tea.Batch(cmds...)
+			} else if m.showRuns {
+				m.showRuns = false
+				m.showPipelines = true
+				m.focusedPanel = 2
+				// Find the cursor position for the selected pipeline
+				foundPipeline := false
+				for i, pipeline := range m.pipelines {
+					if m.selectedPipeline != nil && pipeline.Id != nil && m.selectedPipeline.Id != nil && *pipeline.Id == *m.selectedPipeline.Id {
+						m.cursor = i
+						foundPipeline = true
+						break
+					}
+				}
+				if !foundPipeline {
+					m.cursor = 0
+				}
+				m.updateScroll()
+				return m, tea.Batch(cmds...)
+			} else if m.showPRDetails && m.prFromDashboard {
+				// Return to the dashboard and refresh it, the PR may have changed
+				m.showPRDetails = false
+				m.prFromDashboard = false
+				m.showDashboard = true
+				m.loadingDashboard = true
+				m.cursor = 0
+				if m.currentUser != nil {
+					return m, tea.Batch(append(cmds, m.prsSpinner.Tick, loadDashboardPRs(m.dashboardScope, *m.currentUser.Id, m.config))...)
+				}
+				m.loadingDashboard = false
+				return m, tea.Batch(cmds...)
+			} else if m.showDashboard {
+				m.showDashboard = false
+				m.focusedPanel = 0
+				m.cursor = 0
+				return m, tea.Batch(cmds...)
+			} else if m.showPRDetails {
+				m.showPRDetails = false
+				m.showPRs = true
+				// Find the cursor position for the selected PR
+				foundPR := false
+				for i, pr := range m.prs {
+					if m.selectedPR != nil && pr.PullRequestId != nil && m.selectedPR.PullRequestId != nil && *pr.PullRequestId == *m.selectedPR.PullRequestId {
+						m.cursor = i
+						foundPR = true
+						break
+					}
+				}
+				if !foundPR {
+					m.cursor = 0
+				}
+				m.updateScroll()
+				return m, tea.Batch(cmds...)
+			} else if m.showPRs {
+				m.showPRs = false
+				m.showRepoOptions = true
+				m.cursor = 1 // Reset to "Pull Requests" option
+				return m, tea.Batch(cmds...)
+			} else if m.showPipelines {
+				m.showPipelines = false
+				m.showRepoOptions = true
+				m.cursor = 0 // Reset to "Pipelines" option
+				return m, tea.Batch(cmds...)
+			} else if m.showRepoOptions {
+				m.showRepoOptions = false
+				m.focusedPanel = 1
+				// Find the cursor position for the selected repo
+				foundRepo := false
+				for i, repo := range m.repos {
+					if m.selectedRepo != nil && repo.Id != nil && m.selectedRepo.Id != nil && *repo.Id == *m.selectedRepo.Id {
+						m.cursor = i
+						foundRepo = true
+						break
+					}
+				}
+				if !foundRepo {
+					m.cursor = 0
+				}
+				m.updateScroll()
+				return m, tea.Batch(cmds...)
+			}
+		case "up", "k":
+			if m.showRunLog {
+				m.scrollRunLog(-1)
+				return m, tea.Batch(cmds...)
+			} else if m.showRunApprovals {
+				if m.cursor > 0 {
+					m.cursor--
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prThreadStatusMode {
+				if m.prThreadStatusCursor > 0 {
+					m.prThreadStatusCursor--
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prVoteMode {
+				if m.prVoteCursor > 0 {
+					m.prVoteCursor--
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prCreateMode && m.prCreateStep == 4 {
+				// Navigate through filtered reviewers
+				if m.cursor > 0 {
+					m.cursor--
+				}
+				return m, tea.Batch(cmds...)
+			} else if (m.prCreateMode && m.prCreateStep == 5) || m.prLinkWorkItemMode {
+				// Navigate through work item results
+				if m.cursor > 0 {
+					m.cursor--
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prReviewerMode {
+				if m.cursor > 0 {
+					m.cursor--
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prCreateMode && (m.prCreateStep == 2 || m.prCreateStep == 3) {
+				// Navigate through branches
+				if m.prCreateStep == 2 { // Source branch selection
+					currentIndex := -1
+					for i, branch := range m.branches {
+						if m.prSourceBranch != nil && branch.Name != nil && m.prSourceBranch.Name != nil &&
+							*branch.Name == *m.prSourceBranch.Name {
+							currentIndex = i
+							break
+						}
+					}
+					if currentIndex > 0 {
+						m.prSourceBranch = &m.branches[currentIndex-1]
+						m.loadingPRCommits = true
+						cmds = append(cmds, m.loadBranchCommits())
+					}
+				} else if m.prCreateStep == 3 { // Target branch selection
+					currentIndex := -1
+					for i, branch := range m.branches {
+						if m.prTargetBranch != nil && branch.Name != nil && m.prTargetBranch.Name != nil &&
+							*branch.Name == *m.prTargetBranch.Name {
+							currentIndex = i
+							break
+						}
+					}
+					if currentIndex > 0 {
+						m.prTargetBranch = &m.branches[currentIndex-1]
+						m.loadingPRCommits = true
+						cmds = append(cmds, m.loadTargetPRTemplate(), m.loadBranchCommits())
+					}
+				}
+				return m, tea.Batch(cmds...)
+			} else if !m.searchMode && !m.prCreateMode {
+				if m.cursor > 0 {
+					m.cursor--
+					m.updateScroll()
+				}
+			}
+		case "down", "j":
+			if m.showRunLog {
+				m.scrollRunLog(1)
+				return m, tea.Batch(cmds...)
+			} else if m.showRunApprovals {
+				if m.cursor < len(m.runPendingChecks())-1 {
+					m.cursor++
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prThreadStatusMode {
+				if m.prThreadStatusCursor < len(threadStatusOptions)-1 {
+					m.prThreadStatusCursor++
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prVoteMode {
+				if m.prVoteCursor < len(voteOptions)-1 {
+					m.prVoteCursor++
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prCreateMode && m.prCreateStep == 4 {
+				// Navigate through filtered reviewers
+				if m.cursor < len(m.filteredReviewers)-1 {
+					m.cursor++
+				}
+				return m, tea.Batch(cmds...)
+			} else if (m.prCreateMode && m.prCreateStep == 5) || m.prLinkWorkItemMode {
+				// Navigate through work item results
+				if m.cursor < len(m.workItemResults)-1 {
+					m.cursor++
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prReviewerMode {
+				// Navigate search results while searching, the current reviewers otherwise
+				count := len(m.prDetailReviewers())
+				if m.reviewerSearch != "" {
+					count = len(m.filteredReviewers)
+				}
+				if m.cursor < count-1 {
+					m.cursor++
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prCreateMode && (m.prCreateStep == 2 || m.prCreateStep == 3) {
+				// Navigate through branches
+				if m.prCreateStep == 2 { // Source branch selection
+					currentIndex := -1
+					for i, branch := range m.branches {
+						if m.prSourceBranch != nil && branch.Name != nil && m.prSourceBranch.Name != nil &&
+							*branch.Name == *m.prSourceBranch.Name {
+							currentIndex = i
+							break
+						}
+					}
+					if currentIndex < len(m.branches)-1 {
+						m.prSourceBranch = &m.branches[currentIndex+1]
+						m.loadingPRCommits = true
+						cmds = append(cmds, m.loadBranchCommits())
+					}
+				} else if m.prCreateStep == 3 { // Target branch selection
+					currentIndex := -1
+					for i, branch := range m.branches {
+						if m.prTargetBranch != nil && branch.Name != nil && m.prTargetBranch.Name != nil &&
+							*branch.Name == *m.prTargetBranch.Name {
+							currentIndex = i
+							break
+						}
+					}
+					if currentIndex < len(m.branches)-1 {
+						m.prTargetBranch = &m.branches[currentIndex+1]
+						m.loadingPRCommits = true
+						cmds = append(cmds, m.loadTargetPRTemplate(), m.loadBranchCommits())
+					}
+				}
+				return m, tea.Batch(cmds...)
+			} else if !m.searchMode && !m.prCreateMode {
+				if !m.showRepoOptions && !m.showPipelines && !m.showRuns && !m.showRunDetails && !m.showPRs &&
+					!m.showPRDetails && !m.showPRFiles && !m.showPRIterations && !m.showPRDiff && !m.showPRThreads && !m.showDashboard {
+					if m.focusedPanel == 0 && m.cursor < len(m.projects)-1 {
+						m.cursor++
+						m.updateScroll()
+					} else if m.focusedPanel == 1 && m.cursor < len(m.repos)-1 {
+						m.cursor++
+						m.updateScroll()
+					}
+				} else if m.showRepoOptions && m.cursor < len(m.repoOptions)-1 {
+					m.cursor++
+				} else if m.showPipelines && m.cursor < len(m.pipelines)-1 {
+					m.cursor++
+					m.updateScroll()
+				} else if m.showRuns && m.cursor < len(m.runs)-1 {
+					m.cursor++
+					m.updateScroll()
+				} else if m.showPRs && m.cursor < len(m.prs)-1 {
+					m.cursor++
+					m.updateScroll()
+				} else if m.showDashboard && m.cursor < len(m.dashboardPRs)-1 {
+					m.cursor++
+				} else if m.showRunDetails && m.cursor < len(m.timelineRows())-1 {
+					m.cursor++
+					m.updateScroll()
+				} else if m.showPRIterations && m.cursor < len(m.prIterations)-1 {
+					m.cursor++
+				} else if m.showPRFiles && m.cursor < len(m.prFiles)-1 {
+					m.cursor++
+					m.updateScroll()
+				} else if m.showPRDiff && m.cursor < len(m.prDiffLines)-1 {
+					m.cursor++
+					m.updateScroll()
+				} else if m.showPRThreads && m.cursor < len(m.visibleThreads())-1 {
+					m.cursor++
+					m.updateScroll()
+				}
+			}
+		case "left", "h":
+			if !m.searchMode && !m.prCreateMode {
+				if m.showPRDiff || m.showPRFiles || m.showPRIterations {
+					m.leavePRFileView()
+					return m, tea.Batch(cmds...)
+				} else if m.prVoteMode || m.prLinkWorkItemMode || m.prReviewerMode || m.prLabelMode || m.prCherryPickMode || m.showRunPipeline {
+					return m, tea.Batch(cmds...)
+				} else if m.showPRThreads {
+					if !m.prThreadStatusMode {
+						m.showPRThreads = false
+						m.showPRDetails = true
+						m.cursor = 0
+					}
+					return m, tea.Batch(cmds...)
+				} else if m.showRunApprovals {
+					m.closeRunApprovals()
+					return m, tea.Batch(cmds...)
+				} else if m.showRunLog {
+					m.showRunLog = false
+					m.showRunDetails = true
+					m.loadingRunLog = false
+					return m, tea.Batch(cmds...)
+				} else if m.showRunDetails {
+					m.showRunDetails = false
+					m.showRuns = true
+					m.autoRefresh = false
+					// Find the cursor position for the selected run
+					foundRun := false
+					for i, run := range m.runs {
+						if m.selectedRun != nil && run.Id != nil && m.selectedRun.Id != nil && *run.Id == *m.selectedRun.Id {
+							m.cursor = i
+							foundRun = true
+							break
+						}
+					}
+					if !foundRun {
+						m.cursor = 0
+					}
+					m.updateScroll()
+					return m, tea.Batch(cmds...)
+				} else if m.showRuns {
+					m.showRuns = false
+					m.showPipelines = true
+					m.focusedPanel = 2
+					// Find the cursor position for the selected pipeline
+					foundPipeline := false
+					for i, pipeline := range m.pipelines {
+						if m.selectedPipeline != nil && pipeline.Id != nil && m.selectedPipeline.Id != nil && *pipeline.Id == *m.selectedPipeline.Id {
+							m.cursor = i
+							foundPipeline = true
+							break
+						}
+					}
+					if !foundPipeline {
+						m.cursor = 0
+					}
+					m.updateScroll()
+					return m, tea.Batch(cmds...)
+				} else if m.showPRs {
+					m.showPRs = false
+					m.showRepoOptions = true
+					m.cursor = 1 // Reset to "Pull Requests" option
+					return m, tea.Batch(cmds...)
+				} else if m.showPipelines {
+					m.showPipelines = false
+					m.showRepoOptions = true
+					m.cursor = 0 // Reset to "Pipelines" option
+					return m, tea.Batch(cmds...)
+				} else if m.showDashboard {
+					m.showDashboard = false
+					m.focusedPanel = 0
+					m.cursor = 0
+					return m, tea.Batch(cmds...)
+				} else if !m.showRepoOptions {
+					m.focusedPanel = 0
+					m.cursor = 0
+				} else {
+					// Also allow left arrow to go back from repo options
+					m.showRepoOptions = false
+					m.focusedPanel = 1
+					// Find the cursor position for the selected repo
+					foundRepo := false
+					for i, repo := range m.repos {
+						if m.selectedRepo != nil && repo.Id != nil && m.selectedRepo.Id != nil && *repo.Id == *m.selectedRepo.Id {
+							m.cursor = i
+							foundRepo = true
+							break
+						}
+					}
+					if !foundRepo {
+						m.cursor = 0
+					}
+					m.updateScroll()
+					return m, tea.Batch(cmds...)
+				}
+			}
+		case "right", "l":
+			if !m.searchMode && !m.showRepoOptions && !m.showPipelines && !m.showRuns && !m.showPRs && !m.prCreateMode &&
+				!m.showPRDetails && !m.showPRFiles && !m.showPRIterations && !m.showPRDiff && !m.showPRThreads && !m.showDashboard {
+				m.focusedPanel = 1
+				m.cursor = 0
+			}
+		case "enter":
+			if m.runApprovalMode {
+				// Approve or reject with the typed comment
+				checks := m.runPendingChecks()
+				if m.cursor < len(checks) {
+					return m, tea.Batch(append(cmds, m.updateApproval(*checks[m.cursor].Id, m.runApprovalReject, strings.TrimSpace(m.runApprovalInput.Value())))...)
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prOverrideMode {
+				// Pick the merge options to bypass with in the completion dialog
+				if reason := strings.TrimSpace(m.prOverrideInput.Value()); reason != "" {
+					m.showPRReview = false
+					m.prOverrideMode = false
+					m.prOverrideInput.Blur()
+					m.openPRCompleteDialog(false, reason)
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prReplyMode {
+				// Submit reply or new thread
+				content := strings.TrimSpace(m.prCommentInput.Value())
+				if content == "" {
+					return m, tea.Batch(cmds...)
+				}
+				if m.prReplyInline {
+					threadContext, ok := m.prDiffThreadContext()
+					if !ok || m.selectedPRFile == nil {
+						return m, tea.Batch(cmds...)
+					}
+					return m, tea.Batch(append(cmds, m.createFileThread(*m.selectedPRFile, *threadContext, content))...)
+				}
+				if m.prReplyThreadId == 0 {
+					return m, tea.Batch(append(cmds, m.createThread(content))...)
+				}
+				parentCommentID := 0
+				for _, thread := range m.visibleThreads() {
+					if thread.Id != nil && *thread.Id == m.prReplyThreadId && thread.Comments != nil && len(*thread.Comments) > 0 {
+						if (*thread.Comments)[0].Id != nil {
+							parentCommentID = *(*thread.Comments)[0].Id
+						}
+						break
+					}
+				}
+				return m, tea.Batch(append(cmds, m.replyToThread(m.prReplyThreadId, parentCommentID, content))...)
+			} else if m.prLinkWorkItemMode {
+				// Search, or link the highlighted result once the results match the query
+				if m.workItemSearch != m.workItemSearched {
+					return m.startWorkItemSearch(cmds)
+				}
+				if m.cursor < len(m.workItemResults) {
+					return m, tea.Batch(append(cmds, m.linkWorkItem(m.workItemResults[m.cursor]))...)
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prVoteMode {
+				// Cast the chosen vote
+				return m, tea.Batch(append(cmds, m.approvePR(voteOptions[m.prVoteCursor].vote, ""))...)
+			} else if m.prThreadStatusMode {
+				// Apply the chosen thread status
+				threads := m.visibleThreads()
+				if m.cursor < len(threads) && threads[m.cursor].Id != nil {
+					return m, tea.Batch(append(cmds, m.updateThreadStatus(*threads[m.cursor].Id, threadStatusOptions[m.prThreadStatusCursor]))...)
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prCreateMode {
+				if m.prCreateStep == 4 && m.cursor < len(m.filteredReviewers) {
+					// Add reviewer as optional
+					m.addPRReviewer(m.filteredReviewers[m.cursor], false)
+					return m, tea.Batch(cmds...)
+				} else if m.prCreateStep == 5 && m.workItemSearch != m.workItemSearched {
+					return m.startWorkItemSearch(cmds)
+				} else if m.prCreateStep == 5 && m.cursor < len(m.workItemResults) {
+					// Add work item
+					selectedItem := m.workItemResults[m.cursor]
+					for _, item := range m.prWorkItems {
+						if *item.Id == *selectedItem.Id {
+							return m, tea.Batch(cmds...)
+						}
+					}
+					m.prWorkItems = append(m.prWorkItems, selectedItem)
+					return m, tea.Batch(cmds...)
+				} else if m.prTitleInput.Value() != "" && m.prSourceBranch != nil && m.prTargetBranch != nil {
+					// Submit PR creation
+					return m, tea.Batch(append(cmds, m.createPR())...)
+				}
+				return m, tea.Batch(cmds...)
+			} else if !m.searchMode {
+				if m.showPRDiff || m.showPRThreads {
+					return m, tea.Batch(cmds...)
+				} else if m.showPRIterations && m.cursor < len(m.prIterations) && m.prIterations[m.cursor].Id != nil {
+					// Show what changed in the iteration since the marked one, or since the one before it
+					iterationID := *m.prIterations[m.cursor].Id
+					compareTo := iterationID - 1
+					if m.prIterationBase != 0 && m.prIterationBase != iterationID {
+						compareTo = m.prIterationBase
+					}
+					if compareTo > iterationID {
+						iterationID, compareTo = compareTo, iterationID
+					}
+					return m.openPRFiles(cmds, iterationID, compareTo, true)
+				} else if m.showPRFiles && m.cursor < len(m.prFiles) {
+					// View the diff of the selected file
+					m.selectedPRFile = &m.prFiles[m.cursor]
+					m.showPRFiles = false
+					m.showPRDiff = true
+					m.loadingPRDiff = true
+					m.prDiffLines = nil
+					m.prDiffBinary = false
+					m.prDiffError = ""
+					m.cursor = 0
+					m.prDiffScroll = 0
+					m.prDiffAnchor = -1
+
+					if m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil {
+						repoID := m.selectedRepo.Id.String()
+						return m, tea.Batch(append(cmds, m.prDetailsSpinner.Tick, loadPRFileDiff(*m.selectedProject.Name, repoID, *m.selectedPRFile, m.config))...)
+					}
+					return m, tea.Batch(cmds...)
+				} else if m.showRunDetails {
+					// Open the log of the selected record, or expand it when it has none
+					if rows := m.timelineRows(); m.cursor < len(rows) {
+						node := rows[m.cursor].Node
+						if node.Record.Type != nil && *node.Record.Type == "Checkpoint.Approval" && node.Record.Id != nil {
+							return m.openRunApprovals(*node.Record.Id, cmds)
+						}
+						if node.Record.Log == nil && len(node.Children) > 0 {
+							m.toggleTimelineNode(node)
+							return m, tea.Batch(cmds...)
+						}
+						return m.openRunLog(node.Record, cmds)
+					}
+					return m, tea.Batch(cmds...)
+				} else if m.showRuns && m.cursor < len(m.runs) {
+					m.selectedRun = &m.runs[m.cursor]
+					m.showRuns = false
+					m.showRunDetails = true
+					m.loadingTimeline = true
+					m.timeline = nil
+					m.timelineExpanded = map[uuid.UUID]bool{}
+					m.runApprovals = map[uuid.UUID]pipelinesapproval.Approval{}
+					m.cursor = 0
+					m.autoSelected = false // This is a manual selection
+
+					// Start auto-refresh for running builds
+					if m.selectedRun.State != nil && (*m.selectedRun.State == "inProgress" || *m.selectedRun.State == "notStarted") {
+						m.autoRefresh = true
+					}
+
+					if m.selectedProject != nil && m.selectedRun.Id != nil {
+						cmds = append(cmds, m.timelineSpinner.Tick, loadRunTimeline(*m.selectedProject.Name, *m.selectedRun.Id, m.config))
+						if m.autoRefresh {
+							cmds = append(cmds, tick())
+						}
+						return m, tea.Batch(cmds...)
+					}
+					return m, tea.Batch(cmds...)
+				} else if m.showDashboard && m.cursor < len(m.dashboardPRs) {
+					// View PR details, switching to the PR's project and repository
+					pr := &m.dashboardPRs[m.cursor]
+					if pr.Repository == nil || pr.Repository.Id == nil || pr.Repository.Project == nil ||
+						pr.Repository.Project.Name == nil || pr.PullRequestId == nil {
+						return m, tea.Batch(cmds...)
+					}
+					if m.selectedProject == nil || m.selectedProject.Id == nil || pr.Repository.Project.Id == nil ||
+						*m.selectedProject.Id != *pr.Repository.Project.Id {
+						m.selectedProject = pr.Repository.Project
+						for i, project := range m.projects {
+							if project.Id != nil && pr.Repository.Project.Id != nil && *project.Id == *pr.Repository.Project.Id {
+								m.selectedProject = &m.projects[i]
+								break
+							}
+						}
+						m.loadingRepos = true
+						m.repos = []git.GitRepository{}
+						cmds = append(cmds, m.reposSpinner.Tick, loadProjectRepos(*m.selectedProject.Name, m.config))
+					}
+					m.selectedRepo = pr.Repository
+					m.selectedPR = pr
+					m.showDashboard = false
+					m.prFromDashboard = true
+					m.showPRDetails = true
+					m.loadingPRDetails = true
+					m.prDetails = nil
+					m.prPolicies = []policy.PolicyEvaluationRecord{}
+					m.prLinkedWorkItems = []workitemtracking.WorkItem{}
+					m.cursor = 0
+
+					repoID := m.selectedRepo.Id.String()
+					return m, tea.Batch(append(cmds,
+						m.prDetailsSpinner.Tick,
+						loadPRDetails(*m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, m.config),
+						loadPRComments(*m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, m.config),
+						m.loadSelectedPRPolicies(),
+						m.loadSelectedPRWorkItems())...)
+				} else if m.showPRs && m.cursor < len(m.prs) {
+					// View PR details
+					m.selectedPR = &m.prs[m.cursor]
+					m.showPRs = false
+					m.showPRDetails = true
+					m.loadingPRDetails = true
+					m.prDetails = nil
+					m.prPolicies = []policy.PolicyEvaluationRecord{}
+					m.prLinkedWorkItems = []workitemtracking.WorkItem{}
+					m.cursor = 0
+
+					if m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil && m.selectedPR.PullRequestId != nil {
+						repoID := m.selectedRepo.Id.String()
+						return m, tea.Batch(append(cmds,
+							m.prDetailsSpinner.Tick,
+							loadPRDetails(*m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, m.config),
+							loadPRComments(*m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, m.config),
+							m.loadSelectedPRPolicies(),
+							m.loadSelectedPRWorkItems())...)
+					}
+					return m, tea.Batch(cmds...)
+				} else if m.showPipelines && m.cursor < len(m.pipelines) {
+					m.selectedPipeline = &m.pipelines[m.cursor]
+					m.showPipelines = false
+					m.showRuns = true
+					m.loadingRuns = true
+					m.runs = []pipeline.Run{}
+					m.cursor = 0
+					if m.selectedProject != nil && m.selectedPipeline.Id != nil {
+						return m, tea.Batch(append(cmds, m.runsSpinner.Tick, loadPipelineRuns(*m.selectedProject.Name, *m.selectedPipeline.Id, m.config))...)
+					}
+					return m, tea.Batch(cmds...)
+				} else if m.showRepoOptions {
+					if m.cursor == 0 { // "Pipelines" option
+						m.showRepoOptions = false
+						m.showPipelines = true
+						m.focusedPanel = 2
+						m.loadingPipelines = true
+						m.pipelines = []pipeline.Pipeline{}
+						m.cursor = 0
+						if m.selectedProject != nil && m.selectedRepo != nil {
+							return m, tea.Batch(append(cmds, m.pipelinesSpinner.Tick, loadRepoPipelines(*m.selectedProject.Name, *m.selectedRepo.Name, m.config))...)
+						}
+					} else if m.cursor == 1 { // "Pull Requests" option
+						m.showRepoOptions = false
+						m.showPRs = true
+						m.focusedPanel = 2
+						m.loadingPRs = true
+						m.prs = []git.GitPullRequest{}
+						m.cursor = 0
+						m.prsScroll = 0
+						if m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil {
+							repoID := m.selectedRepo.Id.String()
+							// Branches back the target branch filter
+							return m, tea.Batch(append(cmds,
+								m.prsSpinner.Tick,
+								loadRepoPRs(*m.selectedProject.Name, repoID, m.prFilter(), m.config),
+								loadRepoBranches(*m.selectedProject.Name, repoID, m.config))...)
+						}
+					}
+					return m, tea.Batch(cmds...)
+				} else if m.focusedPanel == 0 && m.cursor < len(m.projects) {
+					m.selectedProject = &m.projects[m.cursor]
+					m.loadingRepos = true
+					m.repos = []git.GitRepository{}
+					return m, tea.Batch(append(cmds, m.reposSpinner.Tick, loadProjectRepos(*m.selectedProject.Name, m.config))...)
+				} else if m.focusedPanel == 1 && m.cursor < len(m.repos) {
+					m.selectedRepo = &m.repos[m.cursor]
+					m.showRepoOptions = true
+					m.cursor = 0
+					return m, tea.Batch(cmds...)
+				}
+			}
+		case "tab":
+			if m.prCreateMode {
+				// Navigate between PR form fields
+				m.prCreateStep = (m.prCreateStep + 1) % prCreateSteps
+				m.cursor = 0
+
+				// Focus appropriate input
+				if m.prCreateStep == 0 {
+					m.prTitleInput.Focus()
+					m.prDescInput.Blur()
+				} else if m.prCreateStep == 1 {
+					m.prTitleInput.Blur()
+					m.prDescInput.Focus()
+				} else {
+					m.prTitleInput.Blur()
+					m.prDescInput.Blur()
+				}
+				return m, tea.Batch(cmds...)
+			} else if !m.searchMode && !m.showRepoOptions {
+				if m.focusedPanel == 0 {
+					m.focusedPanel = 1
+				} else {
+					m.focusedPanel = 0
+				}
+				m.cursor = 0
+			}
+		case "n":
+			if m.showPRThreads && !m.prThreadStatusMode {
+				// Start a new general comment thread
+				m.prReplyMode = true
+				m.prReplyInline = false
+				m.prReplyThreadId = 0
+				m.prCommentInput = textinput.New()
+				m.prCommentInput.Placeholder = "Write a comment..."
+				m.prCommentInput.Focus()
+				m.prCommentInput.Width = 50
+				return m, tea.Batch(cmds...)
+			} else if m.showPRs && !m.prCreateMode {
+				return m, tea.Batch(append(cmds, m.startPRCreate("", ""))...)
+			} else if m.showPipelines && !m.searchMode && m.cursor < len(m.pipelines) {
+				// Queue a new run of the selected pipeline
+				return m, tea.Batch(append(cmds, m.openRunPipeline(&m.pipelines[m.cursor]))...)
+			}
+		case "x":
+			if m.showRunApprovals {
+				m.startApprovalComment(true)
+				return m, tea.Batch(cmds...)
+			} else if m.showRunDetails && m.runInProgress() {
+				// Canceling needs a second press to confirm
+				if m.runCancelConfirm {
+					m.runCancelConfirm = false
+					return m, tea.Batch(append(cmds, m.cancelRun())...)
+				}
+				m.runCancelConfirm = true
+				return m, tea.Batch(cmds...)
+			} else if m.showPRDetails && !m.prOverrideMode && !m.prCompleteMode && !m.prVoteMode && m.prDetails != nil &&
+				m.prDetails.Status != nil && *m.prDetails.Status == git.PullRequestStatusValues.Active {
+				// Abandoning needs a second press to confirm
+				if m.prAbandonConfirm {
+					return m, tea.Batch(append(cmds, m.changePRState("abandon"))...)
+				}
+				m.prAbandonConfirm = true
+				return m, tea.Batch(cmds...)
+			}
+		case "R":
+			if m.showRunDetails && !m.runInProgress() {
+				return m, tea.Batch(append(cmds, m.rerunRun())...)
+			}
+		case "L":
+			if m.showPRDetails && !m.prOverrideMode && !m.prCompleteMode && !m.prVoteMode && m.prDetails != nil {
+				// Edit the labels
+				m.prLabelMode = true
+				m.prLabelInput = ""
+				m.cursor = 0
+				return m, tea.Batch(cmds...)
+			} else if m.showPRs && !m.prCreateMode && !m.searchMode {
+				// Cycle the label filter through the labels seen, then back to any
+				next := 0
+				for i, name := range m.prLabelOptions {
+					if strings.EqualFold(name, m.prLabelFilter) {
+						next = i + 1
+						break
+					}
+				}
+				m.prLabelFilter = ""
+				if next < len(m.prLabelOptions) {
+					m.prLabelFilter = m.prLabelOptions[next]
+				}
+				return m.reloadPRs(cmds)
+			}
+		case "e":
+			if m.showPRDetails && !m.prOverrideMode && !m.prCompleteMode && !m.prVoteMode && m.prDetails != nil &&
+				m.prDetails.Status != nil && *m.prDetails.Status == git.PullRequestStatusValues.Active {
+				// Edit the reviewers
+				m.prReviewerMode = true
+				m.reviewerSearch = ""
+				m.cursor = 0
+				if len(m.users) == 0 && !m.loadingUsers {
+					m.loadingUsers = true
+					return m, tea.Batch(append(cmds, loadOrgUsers(m.config))...)
+				}
+				m.filterReviewers()
+				return m, tea.Batch(cmds...)
+			}
+		case "w":
+			if m.showPRDetails && !m.prOverrideMode && !m.prCompleteMode && !m.prVoteMode && m.prDetails != nil &&
+				m.prDetails.Status != nil && *m.prDetails.Status == git.PullRequestStatusValues.Active {
+				// Search for a work item to link
+				m.prLinkWorkItemMode = true
+				m.resetWorkItemSearch()
+				m.cursor = 0
+				return m, tea.Batch(cmds...)
+			}
+		case "p":
+			if m.showPRDetails && !m.prOverrideMode && !m.prCompleteMode && !m.prVoteMode && m.prDetails != nil &&
+				m.prDetails.Status != nil && *m.prDetails.Status == git.PullRequestStatusValues.Active {
+				if m.prDetails.IsDraft != nil && *m.prDetails.IsDraft {
+					return m, tea.Batch(append(cmds, m.changePRState("publish"))...)
+				}
+				return m, tea.Batch(append(cmds, m.changePRState("draft"))...)
+			} else if m.showPRDetails && !m.prVoteMode && m.prDetails != nil &&
+				m.prDetails.Status != nil && *m.prDetails.Status == git.PullRequestStatusValues.Completed {
+				return m, tea.Batch(append(cmds, m.openCherryPick(false))...)
+			}
+		case " ":
+			if m.showRunDetails {
+				// Expand or collapse the selected stage or job
+				if rows := m.timelineRows(); m.cursor < len(rows) && len(rows[m.cursor].Node.Children) > 0 {
+					m.toggleTimelineNode(rows[m.cursor].Node)
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.prCreateMode && m.prCreateStep == 6 {
+				m.prCreateDraft = !m.prCreateDraft
+				return m, tea.Batch(cmds...)
+			} else if m.showPRIterations && m.cursor < len(m.prIterations) && m.prIterations[m.cursor].Id != nil {
+				// Mark the iteration to compare others against
+				if m.prIterationBase == *m.prIterations[m.cursor].Id {
+					m.prIterationBase = 0
+				} else {
+					m.prIterationBase = *m.prIterations[m.cursor].Id
+				}
+				return m, tea.Batch(cmds...)
+			}
+		case "a":
+			if m.showRunDetails && len(m.runPendingChecks()) > 0 {
+				// Review the approvals and checks the run is waiting on
+				return m.openRunApprovals(uuid.Nil, cmds)
+			} else if m.showRunApprovals {
+				m.startApprovalComment(false)
+				return m, tea.Batch(cmds...)
+			} else if m.showPRDetails && !m.prOverrideMode && !m.prCompleteMode && !m.prVoteMode && m.prDetails != nil && m.prDetails.Status != nil &&
+				*m.prDetails.Status == git.PullRequestStatusValues.Active {
+				// Approve PR
+				return m, tea.Batch(append(cmds, m.approvePR(10, "Approved via AZTUI"))...)
+			} else if m.showPRs && !m.prCreateMode && !m.searchMode && m.currentUser != nil {
+				// Toggle PRs where I am a reviewer
+				m.prFilterReviewer = !m.prFilterReviewer
+				return m.reloadPRs(cmds)
+			}
+		case "m":
+			if m.showPRs && !m.prCreateMode && !m.searchMode && m.currentUser != nil {
+				// Toggle PRs I created
+				m.prFilterMine = !m.prFilterMine
+				return m.reloadPRs(cmds)
+			} else if !m.searchMode && !m.prCreateMode && !m.showPipelines && !m.showRuns && !m.showRunDetails && !m.showPRs &&
+				!m.showPRDetails && !m.showPRFiles && !m.showPRIterations && !m.showPRDiff && !m.showPRThreads && !m.showDashboard && m.currentUser != nil {
+				// Open the My PRs dashboard
+				m.showRepoOptions = false
+				m.showDashboard = true
+				m.focusedPanel = 2
+				m.cursor = 0
+				m.loadingDashboard = true
+				m.dashboardPRs = []git.GitPullRequest{}
+				m.dashboardScope = m.dashboardProjects()
+				return m, tea.Batch(append(cmds, m.prsSpinner.Tick, loadDashboardPRs(m.dashboardScope, *m.currentUser.Id, m.config))...)
+			}
+		case "d":
+			if m.showPRDetails && !m.prOverrideMode && !m.prCompleteMode && !m.prVoteMode && m.prDetails != nil && m.prDetails.Status != nil &&
+				*m.prDetails.Status == git.PullRequestStatusValues.Active {
+				// Decline PR
+				return m, tea.Batch(append(cmds, m.approvePR(-10, "Declined via AZTUI"))...)
+			}
+		case "c":
+			if m.showPRDetails && !m.prOverrideMode && !m.prVoteMode && m.prDetails != nil && m.prDetails.Status != nil &&
+				*m.prDetails.Status == git.PullRequestStatusValues.Active && m.prDetails.PullRequestId != nil &&
+				m.selectedRepo != nil && m.selectedRepo.Id != nil {
+				m.openPRCompleteDialog(false, "")
+				return m, tea.Batch(cmds...)
+			} else if m.showPRDiff && !m.prDiffBinary {
+				// Comment on the highlighted line or selected range
+				if _, ok := m.prDiffThreadContext(); ok {
+					m.prReplyMode = true
+					m.prReplyInline = true
+					m.prReplyThreadId = 0
+					m.prCommentInput = textinput.New()
+					m.prCommentInput.Placeholder = "Write a comment..."
+					m.prCommentInput.Focus()
+					m.prCommentInput.Width = 50
+				}
+				return m, tea.Batch(cmds...)
+			}
+		case "b":
+			if m.showPRDetails && !m.prOverrideMode && !m.prCompleteMode && !m.prVoteMode && m.hasFailedBuild() {
+				return m, tea.Batch(append(cmds, m.requeueFailedBuilds())...)
+			} else if m.showPRs && !m.prCreateMode && !m.searchMode && len(m.branches) > 0 {
+				// Cycle the target branch filter through the branches, then back to any
+				next := 0
+				for i, branch := range m.branches {
+					if branch.Name != nil && *branch.Name == m.prFilterTarget {
+						next = i + 1
+						break
+					}
+				}
+				m.prFilterTarget = ""
+				if next < len(m.branches) && m.branches[next].Name != nil {
+					m.prFilterTarget = *m.branches[next].Name
+				}
+				return m.reloadPRs(cmds)
+			}
+		case "u":
+			if m.showPRDetails && !m.prOverrideMode && !m.prVoteMode && m.prDetails != nil && m.prDetails.Status != nil &&
+				*m.prDetails.Status == git.PullRequestStatusValues.Active && m.prDetails.PullRequestId != nil &&
+				m.selectedRepo != nil && m.selectedRepo.Id != nil {
+				if m.prDetails.AutoCompleteSetBy != nil {
+					return m, tea.Batch(append(cmds, m.cancelAutoComplete())...)
+				}
+				// Choose the options auto-complete will use
+				m.openPRCompleteDialog(true, "")
+				return m, tea.Batch(cmds...)
+			}
+		case "v":
+			if m.showPRDetails && !m.prOverrideMode && !m.prCompleteMode && m.prDetails != nil && m.prDetails.Status != nil &&
+				*m.prDetails.Status == git.PullRequestStatusValues.Active {
+				// Open the vote picker on the current vote
+				m.prVoteMode = true
+				m.prVoteCursor = 0
+				currentVote := m.currentUserVote()
+				for i, option := range voteOptions {
+					if option.vote == currentVote {
+						m.prVoteCursor = i
+						break
+					}
+				}
+				return m, tea.Batch(cmds...)
+			}
+			if m.showPRDiff && len(m.prDiffLines) > 0 {
+				// Toggle a line range selection starting at the cursor
+				if m.prDiffAnchor >= 0 {
+					m.prDiffAnchor = -1
+				} else {
+					m.prDiffAnchor = m.cursor
+				}
+				return m, tea.Batch(cmds...)
+			}
+		case "o":
+			if m.showPRDetails && !m.prVoteMode && m.prDetails != nil && m.prDetails.Status != nil &&
+				*m.prDetails.Status == git.PullRequestStatusValues.Active {
+				// Start override process
+				m.showPRReview = true
+				m.prOverrideMode = true
+				m.prOverrideInput = textinput.New()
+				m.prOverrideInput.Placeholder = "Enter override reason..."
+				m.prOverrideInput.Focus()
+				m.prOverrideInput.Width = 50
+				return m, tea.Batch(cmds...)
+			}
+		case "f":
+			if m.showPRDetails && !m.prOverrideMode && !m.prVoteMode && m.selectedPR != nil && m.selectedPR.PullRequestId != nil {
+				// View files changed in the latest iteration
+				return m.openPRFiles(cmds, 0, 0, false)
+			}
+		case "i":
+			if m.showPRDetails && !m.prOverrideMode && !m.prCompleteMode && !m.prVoteMode && m.selectedPR != nil && m.selectedPR.PullRequestId != nil &&
+				m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil {
+				// View the iteration history
+				m.showPRDetails = false
+				m.showPRIterations = true
+				m.loadingPRIterations = true
+				m.prIterations = []git.GitPullRequestIteration{}
+				m.prIterationBase = 0
+				m.cursor = 0
+				repoID := m.selectedRepo.Id.String()
+				return m, tea.Batch(append(cmds, m.prDetailsSpinner.Tick, loadPRIterations(*m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, m.config))...)
+			}
+		case "t":
+			if m.showRunDetails {
+				// Retry the failed jobs of the selected stage
+				if stage := m.runStageAt(m.cursor); stage != nil && stageRetryable(stage) {
+					return m, tea.Batch(append(cmds, m.retryStage(*stage.Record.Identifier))...)
+				}
+				return m, tea.Batch(cmds...)
+			} else if m.showPRDetails && !m.prOverrideMode && !m.prVoteMode {
+				// View comment threads
+				m.showPRDetails = false
+				m.showPRThreads = true
+				m.cursor = 0
+				m.prThreadsScroll = 0
+				return m, tea.Batch(cmds...)
+			}
+		case "s":
+			if m.showPRs && !m.prCreateMode && !m.searchMode {
+				// Cycle the status filter
+				m.prStatusFilter = (m.prStatusFilter + 1) % len(prStatusFilters)
+				return m.reloadPRs(cmds)
+			}
+			if m.showPRThreads && !m.prThreadStatusMode {
+				threads := m.visibleThreads()
+				if m.cursor < len(threads) {
+					// Open the status picker on the thread's current status
+					m.prThreadStatusMode = true
+					m.prThreadStatusCursor = 0
+					if threads[m.cursor].Status != nil {
+						for i, status := range threadStatusOptions {
+							if status == *threads[m.cursor].Status {
+								m.prThreadStatusCursor = i
+								break
+							}
+						}
+					}
+					return m, tea.Batch(cmds...)
+				}
+			}
+		case "pgup", "pgdown":
+			if m.showRunLog {
+				page := m.rightContentLines() - runLogHeaderLines
+				if msg.String() == "pgup" {
+					page = -page
+				}
+				m.scrollRunLog(page)
+				return m, tea.Batch(cmds...)
+			} else if m.showPRDiff && len(m.prDiffLines) > 0 {
+				page := m.rightContentLines() - 2 - prDiffFooterLines
+				if page < 1 {
+					page = 1
+				}
+				if msg.String() == "pgup" {
+					m.cursor -= page
+				} else {
+					m.cursor += page
+				}
+				if m.cursor < 0 {
+					m.cursor = 0
+				}
+				if m.cursor > len(m.prDiffLines)-1 {
+					m.cursor = len(m.prDiffLines) - 1
+				}
+				m.updateScroll()
+				return m, tea.Batch(cmds...)
+			}
+		case "G", "end":
+			if m.showRunLog {
+				// Jump to the end and follow new lines again
+				m.runLogFollow = true
+				return m, tea.Batch(cmds...)
+			}
+		case "[", "]":
+			if m.showPRDiff && m.selectedPRFile != nil && len(m.prFiles) > 0 {
+				// Jump to the previous or next changed file
+				currentIndex := 0
+				for i, file := range m.prFiles {
+					if file.Path == m.selectedPRFile.Path {
+						currentIndex = i
+						break
+					}
+				}
+				if msg.String() == "[" && currentIndex > 0 {
+					currentIndex--
+				} else if msg.String() == "]" && currentIndex < len(m.prFiles)-1 {
+					currentIndex++
+				} else {
+					return m, tea.Batch(cmds...)
+				}
+
+				m.selectedPRFile = &m.prFiles[currentIndex]
+				m.loadingPRDiff = true
+				m.prDiffLines = nil
+				m.prDiffBinary = false
+				m.prDiffError = ""
+				m.cursor = 0
+				m.prDiffScroll = 0
+				m.prDiffAnchor = -1
+
+				if m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil {
+					repoID := m.selectedRepo.Id.String()
+					return m, tea.Batch(append(cmds, m.prDetailsSpinner.Tick, loadPRFileDiff(*m.selectedProject.Name, repoID, *m.selectedPRFile, m.config))...)
+				}
+				return m, tea.Batch(cmds...)
+			}
+		}
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// leavePRFileView steps back from a file diff to the file list, and from the file or
+// iteration list to the view it was opened from
+func (m *model) leavePRFileView() {
+	switch {
+	case m.showPRDiff:
+		m.showPRDiff = false
+		m.showPRFiles = true
+		// Find the cursor position for the selected file
+		m.cursor = 0
+		for i, file := range m.prFiles {
+			if m.selectedPRFile != nil && file.Path == m.selectedPRFile.Path {
+				m.cursor = i
+				break
+			}
+		}
+		m.updateScroll()
+	case m.showPRFiles:
+		m.showPRFiles = false
+		m.cursor = 0
+		if m.prFilesFromIterations {
+			m.showPRIterations = true
+		} else {
+			m.showPRDetails = true
+		}
+	case m.showPRIterations:
+		m.showPRIterations = false
+		m.showPRDetails = true
+		m.cursor = 0
+	}
+}
+
+// openPRFiles shows the files changed in an iteration compared to another. An iteration
+// of 0 shows the latest one, and a compareTo of 0 compares against the merge base.
+func (m model) openPRFiles(cmds []tea.Cmd, iterationID int, compareTo int, fromIterations bool) (tea.Model, tea.Cmd) {
+	m.showPRDetails = false
+	m.showPRIterations = false
+	m.showPRFiles = true
+	m.loadingPRFiles = true
+	m.prFiles = []prs.PRFileChange{}
+	m.prFilesIteration = iterationID
+	m.prFilesCompareTo = compareTo
+	m.prFilesFromIterations = fromIterations
+	m.cursor = 0
+	m.prFilesScroll = 0
+
+	if m.selectedProject == nil || m.selectedRepo == nil || m.selectedRepo.Id == nil || m.selectedPR == nil || m.selectedPR.PullRequestId == nil {
+		return m, tea.Batch(cmds...)
+	}
+	repoID := m.selectedRepo.Id.String()
+	if iterationID == 0 {
+		return m, tea.Batch(append(cmds, m.prDetailsSpinner.Tick, loadPRFiles(*m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, m.config))...)
+	}
+	return m, tea.Batch(append(cmds, m.prDetailsSpinner.Tick,
+		loadPRIterationFiles(*m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, iterationID, compareTo, m.config))...)
+}
+
+// prFilesRangeLabel describes which iterations the file list compares
+func (m model) prFilesRangeLabel() string {
+	if m.prFilesIteration == 0 {
+		return "latest iteration"
+	}
+	if m.prFilesCompareTo == 0 {
+		return fmt.Sprintf("iteration %d", m.prFilesIteration)
+	}
+	return fmt.Sprintf("iteration %d since iteration %d", m.prFilesIteration, m.prFilesCompareTo)
+}
+
+// prFilter builds the server-side PR query from the list filter toggles
+func (m model) prFilter() prs.PRFilter {
+	filter := prs.PRFilter{
+		Status:        prStatusFilters[m.prStatusFilter],
+		TargetRefName: m.prFilterTarget,
+		Label:         m.prLabelFilter,
+	}
+	if m.currentUser != nil && m.currentUser.Id != nil {
+		if m.prFilterMine {
+			filter.CreatorId = *m.currentUser.Id
+		}
+		if m.prFilterReviewer {
+			filter.ReviewerId = *m.currentUser.Id
+		}
+	}
+	return filter
+}
+
+// prFilterLabel summarises the active PR list filters for the panel title
+func (m model) prFilterLabel() string {
+	parts := []string{string(prStatusFilters[m.prStatusFilter])}
+	if m.prFilterMine {
+		parts = append(parts, "created by me")
+	}
+	if m.prFilterReviewer {
+		parts = append(parts, "reviewer: me")
+	}
+	if m.prFilterTarget != "" {
+		parts = append(parts, "→ "+strings.TrimPrefix(m.prFilterTarget, "refs/heads/"))
+	}
+	if m.prLabelFilter != "" {
+		parts = append(parts, "label: "+m.prLabelFilter)
+	}
+	return strings.Join(parts, " · ")
+}
+
+func containsFold(values []string, value string) bool {
+	for _, existing := range values {
+		if strings.EqualFold(existing, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// startPRCreate opens the create form. An empty source defaults to the checked out or most
+// recently updated branch, an empty target to the repository's default branch.
+func (m *model) startPRCreate(sourceRefName string, targetRefName string) tea.Cmd {
+	m.showPRCreate = true
+	m.prCreateMode = true
+	m.prCreateStep = 0
+	m.cursor = 0
+	m.reviewerSearch = ""
+
+	// Initialize text inputs
+	m.prTitleInput = textinput.New()
+	m.prTitleInput.Placeholder = "Enter PR title..."
+	m.prTitleInput.Focus()
+	m.prTitleInput.Width = 50
+
+	m.prDescInput = newPRDescInput()
+	m.prDescTemplate = ""
+	m.prCommits = []git.GitCommitRef{}
+	m.prGeneratedTitle = ""
+	m.prGeneratedDesc = ""
+
+	// Clear previous selections
+	m.prReviewers = []git.IdentityRefWithVote{}
+	m.prCreateDraft = false
+	m.prWorkItems = []workitemtracking.WorkItem{}
+	m.resetWorkItemSearch()
+	m.prSourceBranch = nil
+	m.prTargetBranch = nil
+	m.prDefaultSource = sourceRefName
+	m.prDefaultTarget = targetRefName
+	m.prLocalBranch = ""
+	m.prActionMessage = ""
+
+	// Load branches and users
+	if m.selectedProject == nil || m.selectedRepo == nil || m.selectedRepo.Id == nil {
+		return nil
+	}
+	repoID := m.selectedRepo.Id.String()
+	m.loadingBranches = true
+	m.loadingUsers = true
+
+	// Default the source to the local checkout when it is this repo, else to the latest branch
+	var sourceCmd tea.Cmd
+	if sourceRefName == "" && m.isLocalRepo() {
+		sourceCmd = loadLocalBranch()
+	} else if sourceRefName == "" {
+		sourceCmd = loadLatestBranch(*m.selectedProject.Name, repoID, m.config)
+	}
+	return tea.Batch(
+		loadRepoBranches(*m.selectedProject.Name, repoID, m.config),
+		loadOrgUsers(m.config),
+		sourceCmd)
+}
+
+// openCherryPickPR leaves the details for the create form, proposing the branch a finished
+// cherry-pick or revert wrote to
+func (m model) openCherryPickPR(cmds []tea.Cmd) (tea.Model, tea.Cmd) {
+	title := fmt.Sprintf("%s: ", m.cherryPickKind())
+	description := ""
+	if m.prDetails != nil && m.prDetails.PullRequestId != nil {
+		if m.prDetails.Title != nil {
+			title += *m.prDetails.Title
+		}
+		if m.prCherryPickRevert {
+			description = fmt.Sprintf("Reverts !%d.", *m.prDetails.PullRequestId)
+		} else {
+			description = fmt.Sprintf("Cherry-picks !%d into %s.", *m.prDetails.PullRequestId, strings.TrimPrefix(m.prCherryPickOnto, "refs/heads/"))
+		}
+	}
+
+	m.prCherryPickMode = false
+	m.showPRDetails = false
+	m.prFromDashboard = false
+	m.showPRs = true
+	cmd := m.startPRCreate(m.prCherryPickRef, m.prCherryPickOnto)
+	m.prTitleInput.SetValue(title)
+	m.prTitleInput.CursorEnd()
+	m.prDescInput.SetValue(description)
+	return m.reloadPRs(append(cmds, cmd))
+}
+
+// reloadPRs reloads the PR list after a filter change
+func (m model) reloadPRs(cmds []tea.Cmd) (tea.Model, tea.Cmd) {
+	if m.selectedProject == nil || m.selectedRepo == nil || m.selectedRepo.Id == nil {
+		return m, tea.Batch(cmds...)
+	}
+	m.loadingPRs = true
+	m.prs = []git.GitPullRequest{}
+	m.cursor = 0
+	m.prsScroll = 0
+	repoID := m.selectedRepo.Id.String()
+	return m, tea.Batch(append(cmds, m.prsSpinner.Tick, loadRepoPRs(*m.selectedProject.Name, repoID, m.prFilter(), m.config))...)
+}
+
+// openPRCompleteDialog prepares the completion dialog from the repository defaults. With a
+// bypass reason the PR is completed with an override of its branch policies.
+func (m *model) openPRCompleteDialog(autoComplete bool, bypassReason string) {
+	defaults := m.config.GetCompletionDefaults(m.selectedRepo.Id.String())
+	m.prCompleteMode = true
+	m.prCompleteAuto = autoComplete
+	m.prCompleteBypass = bypassReason
+	m.prCompleteStep = 0
+	m.prMergeStrategy = 0 // Merge commit
+	for i, option := range mergeStrategyOptions {
+		if option.configKey == defaults.MergeStrategy {
+			m.prMergeStrategy = i
+			break
+		}
+	}
+	m.prDeleteSourceBranch = defaults.DeleteSourceBranch
+	m.prTransitionWorkItems = defaults.TransitionWorkItems
+	m.prSaveCompleteDefaults = false
+
+	mergeMessage := fmt.Sprintf("Merge pull request #%d", *m.prDetails.PullRequestId)
+	if m.prDetails.Title != nil {
+		mergeMessage = fmt.Sprintf("%s: %s", mergeMessage, *m.prDetails.Title)
+	}
+	m.prMergeMessageInput = textinput.New()
+	m.prMergeMessageInput.Placeholder = "Merge commit message..."
+	m.prMergeMessageInput.SetValue(mergeMessage)
+	m.prMergeMessageInput.Width = 50
+}
+
+func (m model) updatePRCompleteDialog(msg tea.KeyMsg, cmds []tea.Cmd) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc", "escape":
+		m.prCompleteMode = false
+		m.prMergeMessageInput.Blur()
+		return m, tea.Batch(cmds...)
+	case "tab", "down", "shift+tab", "up":
+		if msg.String() == "tab" || msg.String() == "down" {
+			m.prCompleteStep = (m.prCompleteStep + 1) % prCompleteSteps
+		} else {
+			m.prCompleteStep = (m.prCompleteStep + prCompleteSteps - 1) % prCompleteSteps
+		}
+		if m.prCompleteStep == 1 {
+			m.prMergeMessageInput.Focus()
+		} else {
+			m.prMergeMessageInput.Blur()
+		}
+		return m, tea.Batch(cmds...)
+	case "enter":
+		if m.selectedRepo == nil || m.selectedRepo.Id == nil {
+			return m, tea.Batch(cmds...)
+		}
+		option := mergeStrategyOptions[m.prMergeStrategy]
+
+		if m.prSaveCompleteDefaults {
+			defaults := config.CompletionDefaults{
+				MergeStrategy:       option.configKey,
+				DeleteSourceBranch:  m.prDeleteSourceBranch,
+				TransitionWorkItems: m.prTransitionWorkItems,
+			}
+			if err := m.config.SetCompletionDefaults(m.selectedRepo.Id.String(), defaults); err != nil {
+				// Leave the dialog open so the defaults can be unticked and the PR completed anyway
+				log.Printf("Error saving completion defaults: %v", err)
+				m.prActionMessage = fmt.Sprintf("Failed to save completion defaults: %v", err)
+				m.prActionTime = time.Now()
+				return m, tea.Batch(cmds...)
+			}
+		}
+
+		mergeMessage := strings.TrimSpace(m.prMergeMessageInput.Value())
+		deleteSourceBranch := m.prDeleteSourceBranch
+		transitionWorkItems := m.prTransitionWorkItems
+		mergeStrategy := option.strategy
+		completionOptions := &git.GitPullRequestCompletionOptions{
+			DeleteSourceBranch:  &deleteSourceBranch,
+			TransitionWorkItems: &transitionWorkItems,
+			MergeStrategy:       &mergeStrategy,
+		}
+		// Rebase fast-forward does not create a merge commit to carry a message
+		if mergeMessage != "" && mergeStrategy != git.GitPullRequestMergeStrategyValues.Rebase {
+			completionOptions.MergeCommitMessage = &mergeMessage
+		}
+		if m.prCompleteAuto {
+			return m, tea.Batch(append(cmds, m.setAutoComplete(completionOptions))...)
+		}
+		if m.prCompleteBypass != "" {
+			return m, tea.Batch(append(cmds, m.overridePR(m.prCompleteBypass, completionOptions))...)
+		}
+		return m, tea.Batch(append(cmds, m.completePR(completionOptions))...)
+	}
+
+	if m.prCompleteStep == 1 {
+		var inputCmd tea.Cmd
+		m.prMergeMessageInput, inputCmd = m.prMergeMessageInput.Update(msg)
+		return m, tea.Batch(append(cmds, inputCmd)...)
+	}
+
+	switch msg.String() {
+	case "left", "h":
+		if m.prCompleteStep == 0 {
+			m.prMergeStrategy = (m.prMergeStrategy + len(mergeStrategyOptions) - 1) % len(mergeStrategyOptions)
+		}
+	case "right", "l":
+		if m.prCompleteStep == 0 {
+			m.prMergeStrategy = (m.prMergeStrategy + 1) % len(mergeStrategyOptions)
+		}
+	case " ":
+		switch m.prCompleteStep {
+		case 2:
+			m.prDeleteSourceBranch = !m.prDeleteSourceBranch
+		case 3:
+			m.prTransitionWorkItems = !m.prTransitionWorkItems
+		case 4:
+			m.prSaveCompleteDefaults = !m.prSaveCompleteDefaults
+		}
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m *model) updateScroll() {
+	boxHeight := (m.height - 8) / 2
+	visibleLines := boxHeight - 4
+
+	if m.showPRThreads {
+		visibleLines = m.prThreadListLines()
+		if m.cursor < m.prThreadsScroll {
+			m.prThreadsScroll = m.cursor
+		} else if m.cursor >= m.prThreadsScroll+visibleLines {
+			m.prThreadsScroll = m.cursor - visibleLines + 1
+		}
+		return
+	}
+
+	if m.showPRFiles || m.showPRDiff {
+		// These views live in the right panel below a two line header
+		visibleLines = m.rightContentLines() - 2
+		scroll := &m.prFilesScroll
+		if m.showPRDiff {
+			visibleLines -= prDiffFooterLines
+			scroll = &m.prDiffScroll
+		}
+		if visibleLines < 1 {
+			visibleLines = 1
+		}
+		if m.cursor < *scroll {
+			*scroll = m.cursor
+		} else if m.cursor >= *scroll+visibleLines {
+			*scroll = m.cursor - visibleLines + 1
+		}
+		return
+	}
+
+	if m.focusedPanel == 0 {
+		if m.cursor < m.projectsScroll {
+			m.projectsScroll = m.cursor
+		} else if m.cursor >= m.projectsScroll+visibleLines {
+			m.projectsScroll = m.cursor - visibleLines + 1
+		}
+	} else if m.focusedPanel == 1 {
+		if m.cursor < m.reposScroll {
+			m.reposScroll = m.cursor
+		} else if m.cursor >= m.reposScroll+visibleLines {
+			m.reposScroll = m.cursor - visibleLines + 1
+		}
+	} else if m.focusedPanel == 2 || m.showPipelines {
+		if m.cursor < m.pipelinesScroll {
+			m.pipelinesScroll = m.cursor
+		} else if m.cursor >= m.pipelinesScroll+visibleLines {
+			m.pipelinesScroll = m.cursor - visibleLines + 1
+		}
+	} else if m.showRuns {
+		if m.cursor < m.runsScroll {
+			m.runsScroll = m.cursor
+		} else if m.cursor >= m.runsScroll+visibleLines {
+			m.runsScroll = m.cursor - visibleLines + 1
+		}
+	} else if m.showPRs {
+		if m.cursor < m.prsScroll {
+			m.prsScroll = m.cursor
+		} else if m.cursor >= m.prsScroll+visibleLines {
+			m.prsScroll = m.cursor - visibleLines + 1
+		}
+	} else if m.showPRDetails {
+		// No scrolling needed for PR details view
+	} else if m.showRunDetails {
+		if m.cursor < m.timelineScroll {
+			m.timelineScroll = m.cursor
+		} else if m.cursor >= m.timelineScroll+visibleLines {
+			m.timelineScroll = m.cursor - visibleLines + 1
+		}
+	}
+}
+
+// timelineRows lays out the run's timeline as a tree of stages, jobs and tasks
+func (m model) timelineRows() []timeline.Row {
+	if m.timeline == nil || m.timeline.Records == nil {
+		return nil
+	}
+	return timeline.Flatten(timeline.Build(*m.timeline.Records), m.timelineIsExpanded)
+}
+
+func (m model) timelineIsExpanded(node *timeline.Node) bool {
+	if node.Record.Id != nil {
+		if expanded, ok := m.timelineExpanded[*node.Record.Id]; ok {
+			return expanded
+		}
+	}
+	return timelineDefaultExpanded(node)
+}
+
+// timelineDefaultExpanded collapses records that finished cleanly, so that the running and
+// failing parts of a long pipeline stay in view
+func timelineDefaultExpanded(node *timeline.Node) bool {
+	state, result := timeline.Status(node)
+	return state != build.TimelineRecordStateValues.Completed || result == nil ||
+		(*result != build.TaskResultValues.Succeeded && *result != build.TaskResultValues.Skipped)
+}
+
+func (m *model) toggleTimelineNode(node *timeline.Node) {
+	if node.Record.Id == nil {
+		return
+	}
+	m.timelineExpanded[*node.Record.Id] = !m.timelineIsExpanded(node)
+	if rows := m.timelineRows(); m.cursor >= len(rows) {
+		m.cursor = len(rows) - 1
+	}
+	m.updateScroll()
+}
+
+func (m *model) openRunPipeline(selected *pipeline.Pipeline) tea.Cmd {
+	m.selectedPipeline = selected
+	m.showRunPipeline = true
+	m.runPipelineStep = 0
+	m.cursor = 0
+	m.runBranches = []git.GitRef{}
+	m.runBranchFilter = ""
+	m.runBranch = ""
+	m.runParameters = []pipelineyaml.Parameter{}
+	m.runParameterValues = map[string]string{}
+	m.runVariables = []pipelines.Variable{}
+	m.runVariableValues = map[string]string{}
+	m.runStages = []string{}
+	m.runSkipStages = map[string]bool{}
+	m.runPipelineError = ""
+
+	if m.selectedProject == nil || selected.Id == nil {
+		return nil
+	}
+	m.loadingRunOptions = true
+	return tea.Batch(m.pipelinesSpinner.Tick, loadRunOptions(*m.selectedProject.Name, *selected.Id, "", m.config))
+}
+
+// closeRunPipeline returns to the pipelines list with the pipeline still selected
+func (m *model) closeRunPipeline() {
+	m.showRunPipeline = false
+	m.loadingRunOptions = false
+	m.cursor = 0
+	for i, pipeline := range m.pipelines {
+		if m.selectedPipeline != nil && pipeline.Id != nil && m.selectedPipeline.Id != nil && *pipeline.Id == *m.selectedPipeline.Id {
+			m.cursor = i
+			break
+		}
+	}
+	m.updateScroll()
+}
+
+// runFilteredBranches returns the branches matching the filter typed in the run dialog
+func (m model) runFilteredBranches() []git.GitRef {
+	filter := strings.ToLower(m.runBranchFilter)
+	var branches []git.GitRef
+	for _, branch := range m.runBranches {
+		if branch.Name != nil && strings.Contains(strings.ToLower(*branch.Name), filter) {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+// runPipelineItems is the number of entries in the dialog's current section
+func (m model) runPipelineItems() int {
+	switch m.runPipelineStep {
+	case 0:
+		return len(m.runFilteredBranches())
+	case 1:
+		return len(m.runParameters)
+	case 2:
+		return len(m.runVariables)
+	case 3:
+		return len(m.runStages)
+	}
+	return 0
+}
+
+// openQueuedRun shows the selected pipeline's runs and opens the given run once they have loaded
+func (m *model) openQueuedRun(runId int) tea.Cmd {
+	m.showRuns = true
+	m.loadingRuns = true
+	m.runs = []pipeline.Run{}
+	m.cursor = 0
+	m.queuedRunId = runId
+	if m.selectedProject == nil || m.selectedPipeline == nil || m.selectedPipeline.Id == nil {
+		return nil
+	}
+	return tea.Batch(m.runsSpinner.Tick, loadPipelineRuns(*m.selectedProject.Name, *m.selectedPipeline.Id, m.config))
+}
+
+// runInProgress reports whether the selected run is still queued or running, going by its
+// timeline once loaded since the run from the list is not refreshed
+func (m model) runInProgress() bool {
+	if m.timeline != nil && m.timeline.Records != nil && len(*m.timeline.Records) > 0 {
+		for _, node := range timeline.Build(*m.timeline.Records) {
+			if state, _ := timeline.Status(node); state != build.TimelineRecordStateValues.Completed {
+				return true
+			}
+		}
+		return false
+	}
+	return m.selectedRun != nil && m.selectedRun.State != nil &&
+		(*m.selectedRun.State == "inProgress" || *m.selectedRun.State == "notStarted")
+}
+
+// runPendingChecks returns the approvals and other checks the run is waiting on, in the order
+// of the stages they guard
+func (m model) runPendingChecks() []build.TimelineRecord {
+	var checks []build.TimelineRecord
+	if m.timeline == nil || m.timeline.Records == nil {
+		return checks
+	}
+	var walk func(nodes []*timeline.Node)
+	walk = func(nodes []*timeline.Node) {
+		for _, node := range nodes {
+			record := node.Record
+			if record.Id != nil && record.Type != nil && strings.HasPrefix(*record.Type, "Checkpoint.") &&
+				(record.State == nil || *record.State != build.TimelineRecordStateValues.Completed) {
+				checks = append(checks, record)
+			}
+			walk(node.Children)
+		}
+	}
+	walk(timeline.Build(*m.timeline.Records))
+	return checks
+}
+
+// runRecordStage returns the name of the stage a timeline record belongs to
+func (m model) runRecordStage(record build.TimelineRecord) string {
+	records := make(map[uuid.UUID]build.TimelineRecord)
+	for _, other := range *m.timeline.Records {
+		if other.Id != nil {
+			records[*other.Id] = other
+		}
+	}
+	for record.ParentId != nil {
+		parent, ok := records[*record.ParentId]
+		if !ok {
+			break
+		}
+		if parent.Type != nil && *parent.Type == "Stage" && parent.Name != nil {
+			return *parent.Name
+		}
+		record = parent
+	}
+	return ""
+}
+
+// openRunApprovals shows the run's waiting approvals and checks, starting on the given one
+func (m model) openRunApprovals(approvalId uuid.UUID, cmds []tea.Cmd) (tea.Model, tea.Cmd) {
+	m.showRunDetails = false
+	m.showRunApprovals = true
+	m.runCancelConfirm = false
+	m.cursor = 0
+	for i, record := range m.runPendingChecks() {
+		if *record.Id == approvalId {
+			m.cursor = i
+			break
+		}
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// closeRunApprovals returns to the run details
+func (m *model) closeRunApprovals() {
+	m.showRunApprovals = false
+	m.showRunDetails = true
+	m.runApprovalMode = false
+	m.cursor = 0
+	m.updateScroll()
+}
+
+// startApprovalComment asks for the comment to approve or reject the selected approval with
+func (m *model) startApprovalComment(reject bool) {
+	checks := m.runPendingChecks()
+	if m.cursor >= len(checks) || *checks[m.cursor].Type != "Checkpoint.Approval" {
+		return
+	}
+	approval, ok := m.runApprovals[*checks[m.cursor].Id]
+	if !ok || approval.Status == nil || *approval.Status != pipelinesapproval.ApprovalStatusValues.Pending {
+		return
+	}
+	m.runApprovalMode = true
+	m.runApprovalReject = reject
+	m.runApprovalInput = textinput.New()
+	m.runApprovalInput.Placeholder = "Comment (optional)..."
+	m.runApprovalInput.Focus()
+	m.runApprovalInput.Width = 50
+}
+
+func (m model) updateApproval(approvalId uuid.UUID, reject bool, comment string) tea.Cmd {
+	action := "approve"
+	status := pipelinesapproval.ApprovalStatusValues.Approved
+	if reject {
+		action = "reject"
+		status = pipelinesapproval.ApprovalStatusValues.Rejected
+	}
+	return m.runAction(action, func(ctx context.Context, connection *azuredevops.Connection, projectName string, runId int) (int, error) {
+		return 0, pipelines.UpdateApproval(ctx, connection, projectName, approvalId, status, comment)
+	})
+}
+
+// runStageAt returns the stage containing the timeline row, nil when the row is not under a stage
+func (m model) runStageAt(index int) *timeline.Node {
+	rows := m.timelineRows()
+	if index >= len(rows) {
+		return nil
+	}
+	for i := index; i >= 0; i-- {
+		if rows[i].Depth == 0 {
+			record := rows[i].Node.Record
+			if record.Type != nil && *record.Type == "Stage" {
+				return rows[i].Node
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// stageRetryable reports whether the stage finished with jobs that failed or were canceled
+func stageRetryable(stage *timeline.Node) bool {
+	if stage.Record.Identifier == nil {
+		return false
+	}
+	state, result := timeline.Status(stage)
+	return state == build.TimelineRecordStateValues.Completed && result != nil &&
+		(*result == build.TaskResultValues.Failed || *result == build.TaskResultValues.Canceled)
+}
+
+func (m model) cancelRun() tea.Cmd {
+	return m.runAction("cancel", func(ctx context.Context, connection *azuredevops.Connection, projectName string, runId int) (int, error) {
+		return 0, pipelines.CancelRun(ctx, connection, projectName, runId)
+	})
+}
+
+func (m model) retryStage(stageRefName string) tea.Cmd {
+	return m.runAction("retry", func(ctx context.Context, connection *azuredevops.Connection, projectName string, runId int) (int, error) {
+		return 0, pipelines.RetryStage(ctx, connection, projectName, runId, stageRefName)
+	})
+}
+
+func (m model) rerunRun() tea.Cmd {
+	return m.runAction("rerun", func(ctx context.Context, connection *azuredevops.Connection, projectName string, runId int) (int, error) {
+		queued, err := pipelines.RerunRun(ctx, connection, projectName, runId)
+		if err != nil || queued.Id == nil {
+			return 0, err
+		}
+		return *queued.Id, nil
+	})
+}
+
+// runAction applies an update to the selected run, returning the id of any run it queued
+func (m model) runAction(action string, update func(ctx context.Context, connection *azuredevops.Connection, projectName string, runId int) (int, error)) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRun == nil || m.selectedRun.Id == nil {
+			return runActionCompleteMsg{action: action, err: fmt.Errorf("missing project or run")}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		runId, err := update(ctx, connection, *m.selectedProject.Name, *m.selectedRun.Id)
+		if err != nil {
+			log.Printf("Error running %s on run: %v", action, err)
+		}
+		return runActionCompleteMsg{action: action, runId: runId, err: err}
+	}
+}
+
+// typeRunPipeline adds typed text to the branch filter or the highlighted value
+func (m *model) typeRunPipeline(typed string) {
+	switch m.runPipelineStep {
+	case 0:
+		m.runBranchFilter += typed
+		m.cursor = 0
+	case 1:
+		if m.cursor < len(m.runParameters) && len(m.runParameters[m.cursor].Values) == 0 {
+			m.runParameterValues[m.runParameters[m.cursor].Name] += typed
+		}
+	case 2:
+		if m.cursor < len(m.runVariables) {
+			m.runVariableValues[m.runVariables[m.cursor].Name] += typed
+		}
+	}
+}
+
+// backspaceRunPipeline removes the last character of the branch filter or the highlighted value
+func (m *model) backspaceRunPipeline() {
+	trim := func(text string) string {
+		if len(text) == 0 {
+			return text
+		}
+		textRunes := []rune(text)
+		return string(textRunes[:len(textRunes)-1])
+	}
+	switch m.runPipelineStep {
+	case 0:
+		m.runBranchFilter = trim(m.runBranchFilter)
+		m.cursor = 0
+	case 1:
+		if m.cursor < len(m.runParameters) && len(m.runParameters[m.cursor].Values) == 0 {
+			name := m.runParameters[m.cursor].Name
+			m.runParameterValues[name] = trim(m.runParameterValues[name])
+		}
+	case 2:
+		if m.cursor < len(m.runVariables) {
+			name := m.runVariables[m.cursor].Name
+			m.runVariableValues[name] = trim(m.runVariableValues[name])
+		}
+	}
+}
+
+// queueRun queues the pipeline with the dialog's choices. Only variables changed from
+// the definition's values are sent.
+func (m model) queueRun() tea.Cmd {
+	refName := m.runBranch
+	parameters := make(map[string]string)
+	for _, parameter := range m.runParameters {
+		if value := m.runParameterValues[parameter.Name]; value != "" {
+			parameters[parameter.Name] = value
+		}
+	}
+	variables := make(map[string]string)
+	for _, variable := range m.runVariables {
+		if value := m.runVariableValues[variable.Name]; value != variable.Value {
+			variables[variable.Name] = value
+		}
+	}
+	var stagesToSkip []string
+	for _, stage := range m.runStages {
+		if m.runSkipStages[stage] {
+			stagesToSkip = append(stagesToSkip, stage)
+		}
+	}
+
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedPipeline == nil || m.selectedPipeline.Id == nil {
+			return runQueuedMsg{err: fmt.Errorf("missing project or pipeline")}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		run, err := pipelines.RunPipeline(ctx, connection, *m.selectedProject.Name, *m.selectedPipeline.Id, refName, parameters, variables, stagesToSkip)
+		if err != nil {
+			log.Printf("Error queueing pipeline run: %v", err)
+			return runQueuedMsg{err: err}
+		}
+		return runQueuedMsg{run: run}
+	}
+}
+
+func (m model) openRunLog(record build.TimelineRecord, cmds []tea.Cmd) (tea.Model, tea.Cmd) {
+	if record.Id == nil {
+		return m, tea.Batch(cmds...)
+	}
+	m.showRunDetails = false
+	m.showRunLog = true
+	m.runLogRecordId = *record.Id
+	m.runLogName = "Unknown Step"
+	if record.Name != nil {
+		m.runLogName = *record.Name
+	}
+	m.runLogId = 0
+	m.runLogLines = []string{}
+	m.runLogScroll = 0
+	m.runLogFollow = true
+	m.runLogTailing = record.State != nil && *record.State == build.TimelineRecordStateValues.InProgress
+
+	// Steps that have not started have no log yet, the timeline refresh picks it up later
+	if record.Log != nil && record.Log.Id != nil && m.selectedProject != nil && m.selectedRun != nil && m.selectedRun.Id != nil {
+		m.runLogId = *record.Log.Id
+		m.loadingRunLog = true
+		cmds = append(cmds, m.timelineSpinner.Tick, loadRunLog(*m.selectedProject.Name, *m.selectedRun.Id, m.runLogId, 0, m.config))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// runLogRecord returns the timeline record whose log is shown, from the latest timeline
+func (m model) runLogRecord() *build.TimelineRecord {
+	if m.timeline == nil || m.timeline.Records == nil {
+		return nil
+	}
+	for i, record := range *m.timeline.Records {
+		if record.Id != nil && *record.Id == m.runLogRecordId {
+			return &(*m.timeline.Records)[i]
+		}
+	}
+	return nil
+}
+
+// runLogStart returns the first log line shown in a page of the given height
+func (m model) runLogStart(page int) int {
+	maxScroll := len(m.runLogLines) - page
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if m.runLogFollow || m.runLogScroll > maxScroll {
+		return maxScroll
+	}
+	return m.runLogScroll
+}
+
+// scrollRunLog moves the log by delta lines, following new lines again once the end is reached
+func (m *model) scrollRunLog(delta int) {
+	page := m.rightContentLines() - runLogHeaderLines
+	m.runLogScroll = m.runLogStart(page) + delta
+	if m.runLogScroll < 0 {
+		m.runLogScroll = 0
+	}
+	maxScroll := len(m.runLogLines) - page
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	m.runLogFollow = m.runLogScroll >= maxScroll
+}
+
+// prThreadListLines is the height of the thread list, the rest of the panel shows the selected conversation
+func (m model) prThreadListLines() int {
+	lines := (m.rightContentLines() - 2) / 2
+	if lines < 3 {
+		lines = 3
+	}
+	return lines
+}
+
+// rightContentLines mirrors the layout in View and returns the lines available to right panel content
+func (m model) rightContentLines() int {
+	totalAvailableHeight := m.height - 2 - 3 // instructions and search bar
+	if totalAvailableHeight < 10 {
+		totalAvailableHeight = 10
+	}
+	rightBoxHeight := totalAvailableHeight
+	if rightBoxHeight < 6 {
+		rightBoxHeight = 6
+	}
+	lines := rightBoxHeight - 4 - 1 // borders, padding and title
+	if lines < 1 {
+		lines = 1
+	}
+	return lines
+}
+
+func (m model) renderLoadingAnimation(visibleLines int, message string, spinner spinner.Model) string {
+	var content strings.Builder
+
+	content.WriteString(fmt.Sprintf("  %s %s\n", spinner.View(), message))
+
+	// Fill remaining space
+	for i := 1; i < visibleLines; i++ {
+		content.WriteString("\n")
+	}
+
+	return content.String()
+}
+
+func (m model) renderInitialLoadingScreen() string {
+	logoText := `   ▄████████  ▄███████▄      ███     ███    █▄   ▄█ 
+   ███    ███ ██▀     ▄██ ▀█████████▄ ███    ███ ███ 
+   ███    ███       ▄███▀    ▀███▀▀██ ███    ███ ███▌
+   ███    ███  ▀█▀▄███▀▄▄     ███   ▀ ███    ███ ███▌
+ ▀███████████   ▄███▀   ▀     ███     ███    ███ ███▌
+   ███    ███ ▄███▀           ███     ███    ███ ███ 
+   ███    ███ ███▄     ▄█     ███     ███    ███ ███ 
+   ███    █▀   ▀████████▀    ▄████▀   ████████▀  █▀`
+
+	logoStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("12")).
+		Bold(true).
+		Align(lipgloss.Center)
+
+	loadingText := ""
+	if m.loadingProjects && !m.autoDetectDone {
+		loadingText = fmt.Sprintf("\n\n%s Loading projects and detecting current repository...", m.projectsSpinner.View())
+	} else if m.loadingProjects {
+		loadingText = fmt.Sprintf("\n\n%s Loading projects...", m.projectsSpinner.View())
+	} else if !m.autoDetectDone {
+		loadingText = fmt.Sprintf("\n\n%s Detecting current repository...", m.projectsSpinner.View())
+	}
+
+	loadingStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Align(lipgloss.Center)
+
+	// Center everything vertically
+	contentHeight := 8 + 3 // Logo height + loading text
+	paddingTop := (m.height - contentHeight) / 2
+	if paddingTop < 0 {
+		paddingTop = 0
+	}
+
+	var content strings.Builder
+
+	// Add top padding
+	for i := 0; i < paddingTop; i++ {
+		content.WriteString("\n")
+	}
+
+	// Add logo
+	content.WriteString(logoStyle.Width(m.width).Render(logoText))
+
+	// Add loading text
+	content.WriteString(loadingStyle.Width(m.width).Render(loadingText))
+
+	return content.String()
+}
+
+func (m *model) filterItems() {
+	m.filteredItems = nil
+
+	if m.searchQuery == "" {
+		if m.focusedPanel == 0 {
+			for _, project := range m.projects {
+				m.filteredItems = append(m.filteredItems, project)
+			}
+		} else if m.focusedPanel == 1 {
+			for _, repo := range m.repos {
+				m.filteredItems = append(m.filteredItems, repo)
+			}
+		}
+		return
+	}
+
+	query := strings.ToLower(m.searchQuery)
+
+	if m.focusedPanel == 0 {
+		for _, project := range m.projects {
+			if project.Name != nil && strings.Contains(strings.ToLower(*project.Name), query) {
+				m.filteredItems = append(m.filteredItems, project)
+			}
+		}
+	} else if m.focusedPanel == 1 {
+		for _, repo := range m.repos {
+			if repo.Name != nil && strings.Contains(strings.ToLower(*repo.Name), query) {
+				m.filteredItems = append(m.filteredItems, repo)
+			}
+		}
+	}
+}
+
+// visibleThreads returns the comment threads people wrote, skipping system notices and deleted threads
+func (m model) visibleThreads() []git.GitPullRequestCommentThread {
+	var threads []git.GitPullRequestCommentThread
+	for _, thread := range m.prComments {
+		if thread.IsDeleted != nil && *thread.IsDeleted {
+			continue
+		}
+		if thread.Comments == nil {
+			continue
+		}
+		hasUserComment := false
+		for _, comment := range *thread.Comments {
+			if comment.CommentType != nil && *comment.CommentType == git.CommentTypeValues.System {
+				continue
+			}
+			if comment.IsDeleted != nil && *comment.IsDeleted {
+				continue
+			}
+			hasUserComment = true
+			break
+		}
+		if hasUserComment {
+			threads = append(threads, thread)
+		}
+	}
+	return threads
+}
+
+func (m *model) filterReviewers() {
+	m.filteredReviewers = nil
+
+	if m.reviewerSearch == "" {
+		m.filteredReviewers = m.users
+		return
+	}
+
+	query := strings.ToLower(m.reviewerSearch)
+
+	for _, user := range m.users {
+		if strings.Contains(strings.ToLower(user.DisplayName), query) || strings.Contains(strings.ToLower(user.UniqueName), query) {
+			m.filteredReviewers = append(m.filteredReviewers, user)
+		}
+	}
+}
+
+// prDetailReviewers returns the reviewers of the PR shown in the details view
+func (m model) prDetailReviewers() []git.IdentityRefWithVote {
+	if m.prDetails == nil || m.prDetails.Reviewers == nil {
+		return nil
+	}
+	return *m.prDetails.Reviewers
+}
+
+func (m model) setReviewer(reviewerID string, name string, required bool) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "reviewer", success: false, message: "Failed to update reviewer: missing project, repo, or PR details"}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		repoID := m.selectedRepo.Id.String()
+		_, err := prs.SetPRReviewer(ctx, connection, *m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, reviewerID, required)
+		if err != nil {
+			log.Printf("Error updating reviewer: %v", err)
+			return prActionCompleteMsg{action: "reviewer", success: false, message: fmt.Sprintf("Failed to update reviewer: %v", err)}
+		}
+
+		if required {
+			return prActionCompleteMsg{action: "reviewer", success: true, message: fmt.Sprintf("%s is a required reviewer", name)}
+		}
+		return prActionCompleteMsg{action: "reviewer", success: true, message: fmt.Sprintf("%s is an optional reviewer", name)}
+	}
+}
+
+func (m model) removeReviewer(reviewerID string, name string) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "reviewer", success: false, message: "Failed to remove reviewer: missing project, repo, or PR details"}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		repoID := m.selectedRepo.Id.String()
+		err := prs.RemovePRReviewer(ctx, connection, *m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, reviewerID)
+		if err != nil {
+			log.Printf("Error removing reviewer: %v", err)
+			return prActionCompleteMsg{action: "reviewer", success: false, message: fmt.Sprintf("Failed to remove reviewer: %v", err)}
+		}
+
+		return prActionCompleteMsg{action: "reviewer", success: true, message: fmt.Sprintf("Removed %s", name)}
+	}
+}
+
+// startCherryPick cherry-picks or reverts the selected PR onto the chosen branch
+func (m model) startCherryPick() tea.Cmd {
+	revert := m.prCherryPickRevert
+	ontoRefName := m.prCherryPickOnto
+	generatedRefName := m.prCherryPickRef
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prCherryPickStatusMsg{err: fmt.Errorf("missing project, repo, or PR details")}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		repoID := m.selectedRepo.Id.String()
+		if revert {
+			operation, err := prs.CreateRevert(ctx, connection, *m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, ontoRefName, generatedRefName)
+			if err != nil {
+				log.Printf("Error creating revert: %v", err)
+				return prCherryPickStatusMsg{err: err}
+			}
+			return cherryPickStatus(operation.RevertId, operation.Status, operation.DetailedStatus)
+		}
+
+		operation, err := prs.CreateCherryPick(ctx, connection, *m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, ontoRefName, generatedRefName)
+		if err != nil {
+			log.Printf("Error creating cherry-pick: %v", err)
+			return prCherryPickStatusMsg{err: err}
+		}
+		return cherryPickStatus(operation.CherryPickId, operation.Status, operation.DetailedStatus)
+	}
+}
+
+// openCherryPick shows the branch picker, keeping the state of an operation still running
+func (m *model) openCherryPick(revert bool) tea.Cmd {
+	m.prCherryPickMode = true
+	m.prCherryPickFilter = ""
+	m.cursor = 0
+	if !m.prCherryPickRunning {
+		m.prCherryPickRevert = revert
+		m.prCherryPickID = 0
+		m.prCherryPickStatus = ""
+		m.prCherryPickError = ""
+	}
+
+	// Reload branches so newly cut release branches can be picked
+	if m.selectedProject == nil || m.selectedRepo == nil || m.selectedRepo.Id == nil {
+		return nil
+	}
+	m.loadingBranches = true
+	return loadRepoBranches(*m.selectedProject.Name, m.selectedRepo.Id.String(), m.config)
+}
+
+// cherryPickBranches returns the branches matching the filter. A revert usually goes back
+// into the branch the PR was merged into, so that branch is offered first.
+func (m model) cherryPickBranches() []git.GitRef {
+	filter := strings.ToLower(m.prCherryPickFilter)
+	var branches []git.GitRef
+	for _, branch := range m.branches {
+		if branch.Name == nil || !strings.Contains(strings.ToLower(*branch.Name), filter) {
+			continue
+		}
+		if m.prCherryPickRevert && m.prDetails != nil && m.prDetails.TargetRefName != nil && *branch.Name == *m.prDetails.TargetRefName {
+			branches = append([]git.GitRef{branch}, branches...)
+		} else {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+func (m model) cherryPickKind() string {
+	if m.prCherryPickRevert {
+		return "Revert"
+	}
+	return "Cherry-pick"
+}
+
+// cherryPickRefName names the branch a cherry-pick or revert of the PR onto a branch is written to.
+// A number is added when the name is taken by a branch or by an earlier cherry-pick or revert.
+func cherryPickRefName(revert bool, pullRequestId int, ontoRefName string, branches []git.GitRef, created map[string]bool) string {
+	kind := "cherry-pick"
+	if revert {
+		kind = "revert"
+	}
+	onto := strings.ReplaceAll(strings.TrimPrefix(ontoRefName, "refs/heads/"), "/", "-")
+	base := fmt.Sprintf("refs/heads/%s-pr-%d-%s", kind, pullRequestId, onto)
+
+	existing := make(map[string]bool)
+	for refName := range created {
+		existing[refName] = true
+	}
+	for _, branch := range branches {
+		if branch.Name != nil {
+			existing[*branch.Name] = true
+		}
+	}
+	refName := base
+	for i := 2; existing[refName]; i++ {
+		refName = fmt.Sprintf("%s-%d", base, i)
+	}
+	return refName
+}
+
+// cherryPickFailure explains why a cherry-pick or revert did not complete
+func cherryPickFailure(status git.GitAsyncOperationStatus, detail *git.GitAsyncRefOperationDetail) string {
+	if detail != nil {
+		if detail.FailureMessage != nil && *detail.FailureMessage != "" {
+			return *detail.FailureMessage
+		}
+		if detail.Conflict != nil && *detail.Conflict {
+			return "the changes conflict with the target branch"
+		}
+		if detail.Timedout != nil && *detail.Timedout {
+			return "the operation timed out"
+		}
+	}
+	return fmt.Sprintf("the operation %s", status)
+}
+
+// prDetailLabels returns the labels of the PR shown in the details view
+func (m model) prDetailLabels() []string {
+	if m.prDetails == nil {
+		return nil
+	}
+	return prs.LabelNames(*m.prDetails)
+}
+
+func (m model) addLabel(name string) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "label", success: false, message: "Failed to add label: missing project, repo, or PR details"}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		repoID := m.selectedRepo.Id.String()
+		_, err := prs.AddPRLabel(ctx, connection, *m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, name)
+		if err != nil {
+			log.Printf("Error adding label: %v", err)
+			return prActionCompleteMsg{action: "label", success: false, message: fmt.Sprintf("Failed to add label: %v", err)}
+		}
+
+		return prActionCompleteMsg{action: "label", success: true, message: fmt.Sprintf("Added label %s", name)}
+	}
+}
+
+func (m model) removeLabel(name string) tea.Cmd {
+	return func() tea.Msg {
+		if m.selectedProject == nil || m.selectedRepo == nil || m.selectedPR == nil ||
+			m.selectedRepo.Id == nil || m.selectedPR.PullRequestId == nil {
+			return prActionCompleteMsg{action: "label", success: false, message: "Failed to remove label: missing project, repo, or PR details"}
+		}
+
+		connection := azuredevops.NewPatConnection(m.config.AzureOrgURL, m.config.AzurePAT)
+		ctx := context.Background()
+
+		repoID := m.selectedRepo.Id.String()
+		err := prs.RemovePRLabel(ctx, connection, *m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, name)
+		if err != nil {
+			log.Printf("Error removing label: %v", err)
+			return prActionCompleteMsg{action: "label", success: false, message: fmt.Sprintf("Failed to remove label: %v", err)}
+		}
+
+		return prActionCompleteMsg{action: "label", success: true, message: fmt.Sprintf("Removed label %s", name)}
+	}
+}
+
+// addPRReviewer adds a reviewer to the PR being created. Adding one that is already
+// there as required toggles whether it is required.
+func (m *model) addPRReviewer(reviewer identity.Reviewer, required bool) {
+	for i, added := range m.prReviewers {
+		if added.Id != nil && *added.Id == reviewer.Id {
+			if required {
+				isRequired := added.IsRequired == nil || !*added.IsRequired
+				m.prReviewers[i].IsRequired = &isRequired
+			}
+			return
+		}
+	}
+	m.prReviewers = append(m.prReviewers, reviewerRef(reviewer, required))
+}
+
+func reviewerRef(reviewer identity.Reviewer, required bool) git.IdentityRefWithVote {
+	id := reviewer.Id
+	displayName := reviewer.DisplayName
+	uniqueName := reviewer.UniqueName
+	isGroup := reviewer.IsGroup
+	return git.IdentityRefWithVote{
+		Id:          &id,
+		DisplayName: &displayName,
+		UniqueName:  &uniqueName,
+		IsContainer: &isGroup,
+		IsRequired:  &required,
+	}
+}
+
+// reviewerName shows a reviewer's name, marking groups so they are not mistaken for people
+func reviewerName(reviewer identity.Reviewer) string {
+	name := reviewer.DisplayName
+	if name == "" {
+		name = reviewer.UniqueName
+	}
+	if reviewer.IsGroup {
+		return name + " [group: " + reviewer.UniqueName + "]"
+	}
+	if reviewer.UniqueName != "" {
+		return name + " <" + reviewer.UniqueName + ">"
+	}
+	return name
+}
+
+func (m model) View() string {
+	// Show config modal if needed
+	if m.showConfigModal {
+		return m.configModal.View()
+	}
+
+	// Show initial loading screen with logo
+	if m.initialLoading {
+		return m.renderInitialLoadingScreen()
+	}
+
+	// Calculate responsive layout with proper margins
+	instructionHeight := 2 // Reduced space for instructions
+	searchHeight := 3      // Always reserve space for search bar
+	totalAvailableHeight := m.height - instructionHeight - searchHeight
+
+	// Ensure we have enough space to work with
+	if totalAvailableHeight < 10 {
+		totalAvailableHeight = 10
+	}
+	if m.width < 40 {
+		// For very small terminals, just return a simple message
+		return "Terminal too small. Please resize."
+	}
+
+	// Calculate exact dimensions - left takes half, right takes half
+	leftWidth := m.width / 2
+	rightWidth := m.width - leftWidth
+	leftBoxHeight := totalAvailableHeight / 2 // Each left box gets exactly half
+	rightBoxHeight := totalAvailableHeight    // Right panel height matches total available height
+
+	// Ensure minimum dimensions
+	if leftWidth < 20 {
+		leftWidth = 20
+		rightWidth = m.width - leftWidth
+	}
+	if rightWidth < 20 {
+		rightWidth = 20
+		leftWidth = m.width - rightWidth
+	}
+	if leftBoxHeight < 3 {
+		leftBoxHeight = 3
+	}
+	if rightBoxHeight < 6 {
+		rightBoxHeight = 6
+	}
+
+	// Calculate content area (subtract borders and padding)
+	leftContentWidth := leftWidth - 4
+	rightContentWidth := rightWidth - 4
+	leftContentHeight := leftBoxHeight - 4
+	rightContentHeight := rightBoxHeight - 4 // Right panel content height matches total available height
+
+	// Ensure content area is not negative and has reasonable minimums
+	if leftContentWidth < 5 {
+		leftContentWidth = 5
+	}
+	if rightContentWidth < 5 {
+		rightContentWidth = 5
+	}
+	if leftContentHeight < 1 {
+		leftContentHeight = 1
+	}
+	if rightContentHeight < 1 {
+		rightContentHeight = 1
+	}
+
+	projectsContent := m.renderProjects(leftContentHeight - 1)
+	reposContent := m.renderRepos(leftContentHeight - 1)
+
+	// Create dynamic styles based on focus
+	projectsStyle := baseBoxStyle.Copy()
+	reposStyle := baseBoxStyle.Copy()
+
+	if m.focusedPanel == 0 && !m.showRepoOptions {
+		projectsStyle = projectsStyle.BorderForeground(lipgloss.Color("12")) // Blue highlight
+	} else {
+		projectsStyle = projectsStyle.BorderForeground(lipgloss.Color("240")) // Gray
+	}
+
+	if (m.focusedPanel == 1 && !m.showRepoOptions) || m.showRepoOptions {
+		reposStyle = reposStyle.BorderForeground(lipgloss.Color("12")) // Blue highlight
+	} else {
+		reposStyle = reposStyle.BorderForeground(lipgloss.Color("240")) // Gray
+	}
+
+	// Create titles with border styling
+	projectsTitle := "┤ Projects ├"
+	reposTitle := "┤ Repositories ├"
+	if m.showRepoOptions {
+		reposTitle = "┤ Repository Options ├"
+	}
+	if m.selectedProject != nil && m.selectedProject.Name != nil {
+		reposTitle = "┤ " + *m.selectedProject.Name + " ├"
+	}
+
+	// Create boxes with titles embedded in content
+	projectsBox := projectsStyle.
+		Width(leftContentWidth).
+		Height(leftContentHeight).
+		Render(projectsTitle + "\n" + projectsContent)
+
+	reposBox := reposStyle.
+		Width(leftContentWidth).
+		Height(leftContentHeight).
+		Render(reposTitle + "\n" + reposContent)
+
+	// Create right panel content
+	var rightPanelContent string
+	var rightPanelTitle string
+
+	if m.showPRCreate {
+		rightPanelTitle = "┤ Create Pull Request ├"
+		rightPanelContent = m.renderPRCreate(rightContentHeight - 1)
+	} else if m.showRunPipeline {
+		rightPanelTitle = "┤ Run Pipeline ├"
+		if m.selectedPipeline != nil && m.selectedPipeline.Name != nil {
+			rightPanelTitle = "┤ Run " + *m.selectedPipeline.Name + " ├"
+		}
+		rightPanelContent = m.renderRunPipeline(rightContentHeight - 1)
+	} else if m.showPRDiff {
+		rightPanelTitle = "┤ Diff ├"
+		if m.selectedPRFile != nil {
+			rightPanelTitle = "┤ " + m.selectedPRFile.Path + " ├"
+		}
+		rightPanelContent = m.renderPRDiff(rightContentHeight - 1)
+	} else if m.showPRThreads {
+		rightPanelTitle = "┤ Comment Threads ├"
+		if m.selectedPR != nil && m.selectedPR.PullRequestId != nil {
+			rightPanelTitle = fmt.Sprintf("┤ PR #%d Comment Threads ├", *m.selectedPR.PullRequestId)
+		}
+		rightPanelContent = m.renderPRThreads(rightContentHeight - 1)
+	} else if m.showPRIterations {
+		rightPanelTitle = "┤ Iterations ├"
+		if m.selectedPR != nil && m.selectedPR.PullRequestId != nil {
+			rightPanelTitle = fmt.Sprintf("┤ PR #%d Iterations ├", *m.selectedPR.PullRequestId)
+		}
+		rightPanelContent = m.renderPRIterations(rightContentHeight - 1)
+	} else if m.showPRFiles {
+		rightPanelTitle = "┤ Files Changed ├"
+		if m.selectedPR != nil && m.selectedPR.PullRequestId != nil {
+			rightPanelTitle = fmt.Sprintf("┤ PR #%d Files Changed ├", *m.selectedPR.PullRequestId)
+		}
+		rightPanelContent = m.renderPRFiles(rightContentHeight - 1)
+	} else if m.showPRDetails {
+		if m.prOverrideMode {
+			rightPanelTitle = "┤ Override PR ├"
+			rightPanelContent = m.renderPROverride(rightContentHeight - 1)
+		} else if m.prReviewerMode {
+			rightPanelTitle = "┤ Edit Reviewers ├"
+			rightPanelContent = m.renderPRReviewers(rightContentHeight - 1)
+		} else if m.prLabelMode {
+			rightPanelTitle = "┤ Edit Labels ├"
+			rightPanelContent = m.renderPRLabels(rightContentHeight - 1)
+		} else if m.prCherryPickMode {
+			rightPanelTitle = "┤ " + m.cherryPickKind() + " ├"
+			rightPanelContent = m.renderPRCherryPick(rightContentHeight - 1)
+		} else if m.prLinkWorkItemMode {
+			rightPanelTitle = "┤ Link Work Item ├"
+			rightPanelContent = m.renderPRLinkWorkItem(rightContentHeight - 1)
+		} else if m.prCompleteMode {
+			rightPanelTitle = "┤ Complete PR ├"
+			if m.prCompleteAuto {
+				rightPanelTitle = "┤ Set Auto-Complete ├"
+			} else if m.prCompleteBypass != "" {
+				rightPanelTitle = "┤ Override PR ├"
+			}
+			rightPanelContent = m.renderPRComplete(rightContentHeight - 1)
+		} else {
+			rightPanelTitle = "┤ PR Details ├"
+			if m.selectedPR != nil && m.selectedPR.Title != nil {
+				rightPanelTitle = "┤ " + *m.selectedPR.Title + " ├"
+			}
+			rightPanelContent = m.renderPRDetails(rightContentHeight - 1)
+		}
+	} else if m.showRunApprovals {
+		rightPanelTitle = "┤ Approvals and Checks ├"
+		rightPanelContent = m.renderRunApprovals(rightContentHeight - 1)
+	} else if m.showRunLog {
+		rightPanelTitle = "┤ " + m.runLogName + " ├"
+		rightPanelContent = m.renderRunLog(rightContentHeight - 1)
+	} else if m.showRunDetails {
+		rightPanelTitle = "┤ Run Details ├"
+		if m.selectedRun != nil && m.selectedRun.Name != nil {
+			rightPanelTitle = "┤ " + *m.selectedRun.Name + " ├"
+		}
+		rightPanelContent = m.renderRunDetails(rightContentHeight - 1)
+	} else if m.showRuns {
+		rightPanelTitle = "┤ Pipeline Runs ├"
+		if m.selectedPipeline != nil && m.selectedPipeline.Name != nil {
+			rightPanelTitle = "┤ " + *m.selectedPipeline.Name + " Runs ├"
+		}
+		rightPanelContent = m.renderRuns(rightContentHeight - 1)
+	} else if m.showDashboard {
+		rightPanelTitle = "┤ My PRs · all projects ├"
+		if m.selectedProject != nil && m.selectedProject.Name != nil {
+			rightPanelTitle = "┤ My PRs · " + *m.selectedProject.Name + " ├"
+		}
+		rightPanelContent = m.renderDashboard(rightContentHeight - 1)
+	} else if m.showPRs {
+		rightPanelTitle = "┤ Pull Requests · " + m.prFilterLabel() + " ├"
+		if m.selectedRepo != nil && m.selectedRepo.Name != nil {
+			rightPanelTitle = "┤ " + *m.selectedRepo.Name + " PRs · " + m.prFilterLabel() + " ├"
+		}
+		rightPanelContent = m.renderPRs(rightContentHeight - 1)
+	} else if m.showPipelines {
+		rightPanelTitle = "┤ Build Pipelines ├"
+		if m.selectedRepo != nil && m.selectedRepo.Name != nil {
+			rightPanelTitle = "┤ " + *m.selectedRepo.Name + " Pipelines ├"
+		}
+		rightPanelContent = m.renderPipelines(rightContentHeight - 1)
+	} else {
+		// Show logo
+		rightPanelTitle = ""
+		rightPanelLogoText := `   ▄████████  ▄███████▄      ███     ███    █▄   ▄█ 
+  ███    ███ ██▀     ▄██ ▀█████████▄ ███    ███ ███ 
+  ███    ███       ▄███▀    ▀███▀▀██ ███    ███ ███▌
+  ███    ███  ▀█▀▄███▀▄▄     ███   ▀ ███    ███ ███▌
+▀███████████   ▄███▀   ▀     ███     ███    ███ ███▌
+  ███    ███ ▄███▀           ███     ███    ███ ███ 
+  ███    ███ ███▄     ▄█     ███     ███    ███ ███ 
+  ███    █▀   ▀████████▀    ▄████▀   ████████▀  █▀`
+
+		rightPanelLogoStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("12")).
+			Bold(true).
+			Align(lipgloss.Center)
+
+		// Fill the content to match the expected height
+		logoLines := strings.Split(rightPanelLogoText, "\n")
+		var paddedContent strings.Builder
+		paddedContent.WriteString(rightPanelLogoStyle.Render(rightPanelLogoText))
+
+		// Add empty lines to fill remaining space
+		usedLines := len(logoLines)
+		availableLines := rightContentHeight - 1 // -1 for title
+		for i := usedLines; i < availableLines; i++ {
+			paddedContent.WriteString("\n")
+		}
+
+		rightPanelContent = paddedContent.String()
+	}
+
+	// Update right panel style based on focus
+	rightStyle := rightPanelStyle.Copy()
+	if m.showPipelines || m.showRuns || m.showRunDetails || m.showPRs || m.showPRCreate || m.showPRDetails ||
+		m.showPRFiles || m.showPRIterations || m.showPRDiff || m.showPRThreads || m.showDashboard {
+		rightStyle = rightStyle.BorderForeground(lipgloss.Color("12"))
+	} else {
+		rightStyle = rightStyle.BorderForeground(lipgloss.Color("240"))
+	}
+
+	rightPanel := rightStyle.
+		Width(rightContentWidth).
+		Height(rightContentHeight).
+		Render(rightPanelTitle + "\n" + rightPanelContent)
+
+	// Create search bar at the top (always reserve space)
+	var searchBar string
+	if m.searchMode {
+		searchQuery := m.searchQuery
+		if searchQuery == "" {
+			searchQuery = "Type to search..."
+		}
+		searchBar = searchStyle.
+			Width(m.width - 4).
+			Render("🔍 Search: " + searchQuery)
+	} else {
+		// Empty space to maintain layout consistency - match search bar height
+		// Create a properly sized empty search bar with padding to match the active search bar
+		emptyContent := "\n\n" // Two newlines to match the content height of active search bar
+		searchBar = searchStyle.
+			Width(m.width - 4).
+			Render(emptyContent)
+	}
+
+	// Create left column by stacking projects and repos vertically
+	leftColumn := lipgloss.JoinVertical(lipgloss.Top, projectsBox, reposBox)
+
+	// Create main layout - no margins between panels
+	content := lipgloss.JoinHorizontal(lipgloss.Top, leftColumn, rightPanel)
+
+	// Simple instructions at bottom
+	instructions := m.getInstructions()
+	instructionsStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Align(lipgloss.Center)
+
+	return lipgloss.JoinVertical(lipgloss.Top,
+		searchBar,
+		content,
+		instructionsStyle.Render(instructions),
+	)
+}
+
+func (m model) renderProjects(visibleLines int) string {
+	// Show loading animation if projects are loading
+	if m.loadingProjects {
+		return m.renderLoadingAnimation(visibleLines, "Loading projects", m.projectsSpinner)
+	}
+
+	var content strings.Builder
+	linesUsed := 0
+
+	// Calculate content width for full-width highlighting
+	contentWidth := m.width/2 - 6 // Account for borders, padding, and margin
+
+	if m.searchMode && m.focusedPanel == 0 {
+		// Show filtered results
+		for i, item := range m.filteredItems {
+			if linesUsed >= visibleLines {
+				break
+			}
+
+			if project, ok := item.(core.TeamProjectReference); ok {
+				projectName := ""
+				if project.Name != nil {
+					projectName = *project.Name
 				}
-				if !foundPR {
-					m.cursor = 0
+
+				// Truncate if too long
+				maxLen := contentWidth - 4
+				if maxLen < 1 {
+					maxLen = 1
 				}
-				m.updateScroll()
-				return m, tea.Batch(cmds...)
-			} else if m.showPRs {
-				m.showPRs = false
-				m.showRepoOptions = true
-				m.cursor = 1 // Reset to "Pull Requests" option
-				return m, tea.Batch(cmds...)
-			} else if m.showPipelines {
-				m.showPipelines = false
-				m.showRepoOptions = true
-				m.cursor = 0 // Reset to "Pipelines" option
-				return m, tea.Batch(cmds...)
-			} else if m.showRepoOptions {
-				m.showRepoOptions = false
-				m.focusedPanel = 1
-				// Find the cursor position for the selected repo
-				foundRepo := false
-				for i, repo := range m.repos {
-					if m.selectedRepo != nil && repo.Id != nil && m.selectedRepo.Id != nil && *repo.Id == *m.selectedRepo.Id {
-						m.cursor = i
-						foundRepo = true
-						break
-					}
+				if len(projectName) > maxLen {
+					projectName = projectName[:maxLen-3] + "..."
 				}
-				if !foundRepo {
-					m.cursor = 0
+
+				line := fmt.Sprintf("  %s", projectName)
+
+				if m.cursor == i {
+					// Create full-width highlight
+					paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+					line = fullWidthHighlightStyle.Render(paddedLine)
 				}
-				m.updateScroll()
-				return m, tea.Batch(cmds...)
+
+				content.WriteString(line + "\n")
+				linesUsed++
 			}
-		case "up", "k":
-			if m.prCreateMode && m.prCreateStep == 4 {
-				// Navigate through filtered reviewers
-				if m.cursor > 0 {
-					m.cursor--
-				}
-				return m, tea.Batch(cmds...)
-			} else if m.prCreateMode && (m.prCreateStep == 2 || m.prCreateStep == 3) {
-				// Navigate through branches
-				if m.prCreateStep == 2 { // Source branch selection
-					currentIndex := -1
-					for i, branch := range m.branches {
-						if m.prSourceBranch != nil && branch.Name != nil && m.prSourceBranch.Name != nil &&
-							*branch.Name == *m.prSourceBranch.Name {
-							currentIndex = i
-							break
-						}
-					}
-					if currentIndex > 0 {
-						m.prSourceBranch = &m.branches[currentIndex-1]
-					}
-				} else if m.prCreateStep == 3 { // Target branch selection
-					currentIndex := -1
-					for i, branch := range m.branches {
-						if m.prTargetBranch != nil && branch.Name != nil && m.prTargetBranch.Name != nil &&
-							*branch.Name == *m.prTargetBranch.Name {
-							currentIndex = i
-							break
-						}
-					}
-					if currentIndex > 0 {
-						m.prTargetBranch = &m.branches[currentIndex-1]
-					}
-				}
-				return m, tea.Batch(cmds...)
-			} else if !m.searchMode && !m.prCreateMode {
-				if m.cursor > 0 {
-					m.cursor--
-					m.updateScroll()
-				}
+		}
+	} else {
+		// Normal mode - show all projects
+		start := m.projectsScroll
+		end := start + visibleLines
+		if end > len(m.projects) {
+			end = len(m.projects)
+		}
+
+		for i := start; i < end; i++ {
+			projectName := ""
+			if m.projects[i].Name != nil {
+				projectName = *m.projects[i].Name
 			}
-		case "down", "j":
-			if m.prCreateMode && m.prCreateStep == 4 {
-				// Navigate through filtered reviewers
-				if m.cursor < len(m.filteredReviewers)-1 {
-					m.cursor++
-				}
-				return m, tea.Batch(cmds...)
-			} else if m.prCreateMode && (m.prCreateStep == 2 || m.prCreateStep == 3) {
-				// Navigate through branches
-				if m.prCreateStep == 2 { // Source branch selection
-					currentIndex := -1
-					for i, branch := range m.branches {
-						if m.prSourceBranch != nil && branch.Name != nil && m.prSourceBranch.Name != nil &&
-							*branch.Name == *m.prSourceBranch.Name {
-							currentIndex = i
-							break
-						}
-					}
-					if currentIndex < len(m.branches)-1 {
-						m.prSourceBranch = &m.branches[currentIndex+1]
-					}
-				} else if m.prCreateStep == 3 { // Target branch selection
-					currentIndex := -1
-					for i, branch := range m.branches {
-						if m.prTargetBranch != nil && branch.Name != nil && m.prTargetBranch.Name != nil &&
-							*branch.Name == *m.prTargetBranch.Name {
-							currentIndex = i
-							break
-						}
-					}
-					if currentIndex < len(m.branches)-1 {
-						m.prTargetBranch = &m.branches[currentIndex+1]
-					}
-				}
-				return m, tea.Batch(cmds...)
-			} else if !m.searchMode && !m.prCreateMode {
-				if !m.showRepoOptions && !m.showPipelines && !m.showRuns && !m.showRunDetails && !m.showPRs {
-					if m.focusedPanel == 0 && m.cursor < len(m.projects)-1 {
-						m.cursor++
-						m.updateScroll()
-					} else if m.focusedPanel == 1 && m.cursor < len(m.repos)-1 {
-						m.cursor++
-						m.updateScroll()
-					}
-				} else if m.showRepoOptions && m.cursor < len(m.repoOptions)-1 {
-					m.cursor++
-				} else if m.showPipelines && m.cursor < len(m.pipelines)-1 {
-					m.cursor++
-					m.updateScroll()
-				} else if m.showRuns && m.cursor < len(m.runs)-1 {
-					m.cursor++
-					m.updateScroll()
-				} else if m.showPRs && m.cursor < len(m.prs)-1 {
-					m.cursor++
-					m.updateScroll()
-				} else if m.showRunDetails && m.timeline != nil && m.timeline.Records != nil && m.cursor < len(*m.timeline.Records)-1 {
-					m.cursor++
-					m.updateScroll()
-				}
+
+			// Truncate if too long
+			maxLen := contentWidth - 4
+			if maxLen < 1 {
+				maxLen = 1
 			}
-		case "left", "h":
-			if !m.searchMode && !m.prCreateMode {
-				if m.showRunDetails {
-					m.showRunDetails = false
-					m.showRuns = true
-					m.autoRefresh = false
-					// Find the cursor position for the selected run
-					foundRun := false
-					for i, run := range m.runs {
-						if m.selectedRun != nil && run.Id != nil && m.selectedRun.Id != nil && *run.Id == *m.selectedRun.Id {
-							m.cursor = i
-							foundRun = true
-							break
-						}
-					}
-					if !foundRun {
-						m.cursor = 0
-					}
-					m.updateScroll()
-					return m, tea.Batch(cmds...)
-				} else if m.showRuns {
-					m.showRuns = false
-					m.showPipelines = true
-					m.focusedPanel = 2
-					// Find the cursor position for the selected pipeline
-					foundPipeline := false
-					for i, pipeline := range m.pipelines {
-						if m.selectedPipeline != nil && pipeline.Id != nil && m.selectedPipeline.Id != nil && *pipeline.Id == *m.selectedPipeline.Id {
-							m.cursor = i
-							foundPipeline = true
-							break
-						}
-					}
-					if !foundPipeline {
-						m.cursor = 0
-					}
-					m.updateScroll()
-					return m, tea.Batch(cmds...)
-				} else if m.showPRs {
-					m.showPRs = false
-					m.showRepoOptions = true
-					m.cursor = 1 // Reset to "Pull Requests" option
-					return m, tea.Batch(cmds...)
-				} else if m.showPipelines {
-					m.showPipelines = false
-					m.showRepoOptions = true
-					m.cursor = 0 // Reset to "Pipelines" option
-					return m, tea.Batch(cmds...)
-				} else if !m.showRepoOptions {
-					m.focusedPanel = 0
-					m.cursor = 0
-				} else {
-					// Also allow left arrow to go back from repo options
-					m.showRepoOptions = false
-					m.focusedPanel = 1
-					// Find the cursor position for the selected repo
-					foundRepo := false
-					for i, repo := range m.repos {
-						if m.selectedRepo != nil && repo.Id != nil && m.selectedRepo.Id != nil && *repo.Id == *m.selectedRepo.Id {
-							m.cursor = i
-							foundRepo = true
-							break
-						}
-					}
-					if !foundRepo {
-						m.cursor = 0
-					}
-					m.updateScroll()
-					return m, tea.Batch(cmds...)
-				}
+			if len(projectName) > maxLen {
+				projectName = projectName[:maxLen-3] + "..."
+			}
+
+			line := fmt.Sprintf("  %s", projectName)
+
+			if m.focusedPanel == 0 && m.cursor == i && !m.showRepoOptions && !m.searchMode {
+				// Create full-width highlight
+				paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+				line = fullWidthHighlightStyle.Render(paddedLine)
+			}
+
+			content.WriteString(line + "\n")
+			linesUsed++
+		}
+	}
+
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
+		content.WriteString("\n")
+		linesUsed++
+	}
+
+	return content.String()
+}
+
+func (m model) renderRepos(visibleLines int) string {
+	// Show loading animation if repos are loading
+	if m.loadingRepos {
+		return m.renderLoadingAnimation(visibleLines, "Loading repositories", m.reposSpinner)
+	}
+
+	var content strings.Builder
+	linesUsed := 0
+
+	// Calculate content width for full-width highlighting
+	contentWidth := m.width/2 - 6 // Account for borders, padding, and margin
+
+	if m.showRepoOptions {
+		// Show repository options
+		if m.selectedRepo != nil && m.selectedRepo.Name != nil {
+			content.WriteString("Selected: " + *m.selectedRepo.Name + "\n\n")
+			linesUsed += 2
+		}
+
+		for i, option := range m.repoOptions {
+			line := fmt.Sprintf("  %s", option.name)
+
+			if m.cursor == i {
+				// Create full-width highlight
+				paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+				line = fullWidthHighlightStyle.Render(paddedLine)
 			}
-		case "right", "l":
-			if !m.searchMode && !m.showRepoOptions && !m.showPipelines && !m.showRuns && !m.showPRs && !m.prCreateMode {
-				m.focusedPanel = 1
-				m.cursor = 0
+
+			content.WriteString(line + "\n")
+			linesUsed++
+		}
+	} else if m.searchMode && m.focusedPanel == 1 {
+		// Show filtered results
+		for i, item := range m.filteredItems {
+			if linesUsed >= visibleLines {
+				break
 			}
-		case "enter":
-			if m.prOverrideMode {
-				// Submit override with message
-				if m.prOverrideInput.Value() != "" {
-					return m, tea.Batch(append(cmds, m.overridePR(m.prOverrideInput.Value()))...)
+
+			if repo, ok := item.(git.GitRepository); ok {
+				repoName := ""
+				if repo.Name != nil {
+					repoName = *repo.Name
 				}
-				return m, tea.Batch(cmds...)
-			} else if m.prCreateMode {
-				if m.prCreateStep == 4 && m.cursor < len(m.filteredReviewers) {
-					// Add reviewer
-					selectedUser := m.filteredReviewers[m.cursor]
-
-					// Check if already added
-					alreadyAdded := false
-					for _, reviewer := range m.prReviewers {
-						if reviewer.Descriptor != nil && selectedUser.Descriptor != nil &&
-							*reviewer.Descriptor == *selectedUser.Descriptor {
-							alreadyAdded = true
-							break
-						}
-					}
 
-					if !alreadyAdded {
-						m.prReviewers = append(m.prReviewers, selectedUser)
-					}
-					return m, tea.Batch(cmds...)
-				} else if m.prTitleInput.Value() != "" && m.prSourceBranch != nil && m.prTargetBranch != nil {
-					// Submit PR creation
-					return m, tea.Batch(append(cmds, m.createPR())...)
+				// Truncate if too long
+				maxLen := contentWidth - 4
+				if maxLen < 1 {
+					maxLen = 1
 				}
-				return m, tea.Batch(cmds...)
-			} else if !m.searchMode {
-				if m.showRunDetails {
-					// Handle step selection if needed
-					return m, tea.Batch(cmds...)
-				} else if m.showRuns && m.cursor < len(m.runs) {
-					m.selectedRun = &m.runs[m.cursor]
-					m.showRuns = false
-					m.showRunDetails = true
-					m.loadingTimeline = true
-					m.timeline = nil
-					m.cursor = 0
-					m.autoSelected = false // This is a manual selection
+				if len(repoName) > maxLen {
+					repoName = repoName[:maxLen-3] + "..."
+				}
+
+				line := fmt.Sprintf("  %s", repoName)
+
+				if m.cursor == i {
+					// Create full-width highlight
+					paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+					line = fullWidthHighlightStyle.Render(paddedLine)
+				}
+
+				content.WriteString(line + "\n")
+				linesUsed++
+			}
+		}
+	} else {
+		// Show repositories list
+		start := m.reposScroll
+		end := start + visibleLines
+		if end > len(m.repos) {
+			end = len(m.repos)
+		}
+
+		for i := start; i < end; i++ {
+			repoName := *m.repos[i].Name
+
+			// Truncate if too long
+			maxLen := contentWidth - 4
+			if maxLen < 1 {
+				maxLen = 1
+			}
+			if len(repoName) > maxLen {
+				repoName = repoName[:maxLen-3] + "..."
+			}
+
+			line := fmt.Sprintf("  %s", repoName)
+
+			if m.focusedPanel == 1 && m.cursor == i && !m.showRepoOptions && !m.searchMode {
+				// Create full-width highlight
+				paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+				line = fullWidthHighlightStyle.Render(paddedLine)
+			}
+
+			content.WriteString(line + "\n")
+			linesUsed++
+		}
+	}
+
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
+		content.WriteString("\n")
+		linesUsed++
+	}
+
+	return content.String()
+}
+
+func (m model) renderPipelines(visibleLines int) string {
+	// Show loading animation if pipelines are loading
+	if m.loadingPipelines {
+		return m.renderLoadingAnimation(visibleLines, "Loading pipelines", m.pipelinesSpinner)
+	}
+
+	var content strings.Builder
+	linesUsed := 0
+
+	// Calculate content width for full-width highlighting
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
+
+	// Show auto-detection success message if applicable
+	if m.autoDetectResult != nil && m.autoDetectResult.ShouldAutoLoad && m.selectedProject != nil && m.selectedRepo != nil {
+		successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("2")) // Green
+		content.WriteString(successStyle.Render("  🚀 Auto-detected current repository"))
+		content.WriteString("\n\n")
+		linesUsed += 2
+	}
+
+	// Show pipelines list
+	start := m.pipelinesScroll
+	end := start + visibleLines
+	if end > len(m.pipelines) {
+		end = len(m.pipelines)
+	}
+
+	for i := start; i < end; i++ {
+		pipelineName := ""
+		if m.pipelines[i].Name != nil {
+			pipelineName = *m.pipelines[i].Name
+		}
+
+		// Truncate if too long
+		maxLen := contentWidth - 4
+		if maxLen < 1 {
+			maxLen = 1
+		}
+		if len(pipelineName) > maxLen {
+			pipelineName = pipelineName[:maxLen-3] + "..."
+		}
+
+		line := fmt.Sprintf("  %s", pipelineName)
+
+		if m.cursor == i {
+			// Create full-width highlight
+			paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+			line = fullWidthHighlightStyle.Render(paddedLine)
+		}
+
+		content.WriteString(line + "\n")
+		linesUsed++
+	}
+
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
+		content.WriteString("\n")
+		linesUsed++
+	}
+
+	return content.String()
+}
+
+func (m model) renderRuns(visibleLines int) string {
+	// Show loading animation if runs are loading
+	if m.loadingRuns {
+		return m.renderLoadingAnimation(visibleLines, "Loading runs", m.runsSpinner)
+	}
+
+	var content strings.Builder
+	linesUsed := 0
+
+	// Calculate content width for full-width highlighting
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
+
+	// Show runs list
+	start := m.runsScroll
+	end := start + visibleLines
+	if end > len(m.runs) {
+		end = len(m.runs)
+	}
+
+	for i := start; i < end; i++ {
+		runDisplay := ""
+		if m.runs[i].Name != nil {
+			runDisplay = *m.runs[i].Name
+		} else if m.runs[i].Id != nil {
+			runDisplay = fmt.Sprintf("Run #%d", *m.runs[i].Id)
+		}
+
+		// Add status if available
+		if m.runs[i].State != nil {
+			runDisplay = fmt.Sprintf("%s (%s)", runDisplay, string(*m.runs[i].State))
+		}
+
+		// Truncate if too long
+		maxLen := contentWidth - 4
+		if maxLen < 1 {
+			maxLen = 1
+		}
+		if len(runDisplay) > maxLen {
+			runDisplay = runDisplay[:maxLen-3] + "..."
+		}
+
+		line := fmt.Sprintf("  %s", runDisplay)
+
+		if m.cursor == i {
+			// Create full-width highlight
+			paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+			line = fullWidthHighlightStyle.Render(paddedLine)
+		}
+
+		content.WriteString(line + "\n")
+		linesUsed++
+	}
+
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
+		content.WriteString("\n")
+		linesUsed++
+	}
+
+	return content.String()
+}
+
+func (m model) renderRunDetails(visibleLines int) string {
+	// Show loading animation if timeline is loading
+	if m.loadingTimeline {
+		return m.renderLoadingAnimation(visibleLines, "Loading timeline", m.timelineSpinner)
+	}
 
-					// Start auto-refresh for running builds
-					if m.selectedRun.State != nil && (*m.selectedRun.State == "inProgress" || *m.selectedRun.State == "notStarted") {
-						m.autoRefresh = true
-					}
+	var content strings.Builder
+	linesUsed := 0
 
-					if m.selectedProject != nil && m.selectedRun.Id != nil {
-						cmds = append(cmds, m.timelineSpinner.Tick, loadRunTimeline(*m.selectedProject.Name, *m.selectedRun.Id, m.config))
-						if m.autoRefresh {
-							cmds = append(cmds, tick())
-						}
-						return m, tea.Batch(cmds...)
-					}
-					return m, tea.Batch(cmds...)
-				} else if m.showPRs && m.cursor < len(m.prs) {
-					// View PR details
-					m.selectedPR = &m.prs[m.cursor]
-					m.showPRs = false
-					m.showPRDetails = true
-					m.loadingPRDetails = true
-					m.prDetails = nil
-					m.cursor = 0
+	// Calculate content width for full-width highlighting
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
 
-					if m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil && m.selectedPR.PullRequestId != nil {
-						repoID := m.selectedRepo.Id.String()
-						return m, tea.Batch(append(cmds,
-							m.prDetailsSpinner.Tick,
-							loadPRDetails(*m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, m.config),
-							loadPRComments(*m.selectedProject.Name, repoID, *m.selectedPR.PullRequestId, m.config))...)
-					}
-					return m, tea.Batch(cmds...)
-				} else if m.showPipelines && m.cursor < len(m.pipelines) {
-					m.selectedPipeline = &m.pipelines[m.cursor]
-					m.showPipelines = false
-					m.showRuns = true
-					m.loadingRuns = true
-					m.runs = []pipeline.Run{}
-					m.cursor = 0
-					if m.selectedProject != nil && m.selectedPipeline.Id != nil {
-						return m, tea.Batch(append(cmds, m.runsSpinner.Tick, loadPipelineRuns(*m.selectedProject.Name, *m.selectedPipeline.Id, m.config))...)
-					}
-					return m, tea.Batch(cmds...)
-				} else if m.showRepoOptions {
-					if m.cursor == 0 { // "Pipelines" option
-						m.showRepoOptions = false
-						m.showPipelines = true
-						m.focusedPanel = 2
-						m.loadingPipelines = true
-						m.pipelines = []pipeline.Pipeline{}
-						m.cursor = 0
-						if m.selectedProject != nil && m.selectedRepo != nil {
-							return m, tea.Batch(append(cmds, m.pipelinesSpinner.Tick, loadRepoPipelines(*m.selectedProject.Name, *m.selectedRepo.Name, m.config))...)
-						}
-					} else if m.cursor == 1 { // "Pull Requests" option
-						m.showRepoOptions = false
-						m.showPRs = true
-						m.focusedPanel = 2
-						m.loadingPRs = true
-						m.prs = []git.GitPullRequest{}
-						m.cursor = 0
-						if m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil {
-							repoID := m.selectedRepo.Id.String()
-							return m, tea.Batch(append(cmds, m.prsSpinner.Tick, loadRepoPRs(*m.selectedProject.Name, repoID, m.config))...)
-						}
-					}
-					return m, tea.Batch(cmds...)
-				} else if m.focusedPanel == 0 && m.cursor < len(m.projects) {
-					m.selectedProject = &m.projects[m.cursor]
-					m.loadingRepos = true
-					m.repos = []git.GitRepository{}
-					return m, tea.Batch(append(cmds, m.reposSpinner.Tick, loadProjectRepos(*m.selectedProject.Name, m.config))...)
-				} else if m.focusedPanel == 1 && m.cursor < len(m.repos) {
-					m.selectedRepo = &m.repos[m.cursor]
-					m.showRepoOptions = true
-					m.cursor = 0
-					return m, tea.Batch(cmds...)
-				}
-			}
-		case "tab":
-			if m.prCreateMode {
-				// Navigate between PR form fields
-				m.prCreateStep = (m.prCreateStep + 1) % 5 // 0: title, 1: desc, 2: source branch, 3: target branch, 4: reviewers
+	if m.timeline == nil || m.timeline.Records == nil {
+		content.WriteString("  No timeline data available\n")
+		linesUsed++
+	} else {
+		// Show run status and auto-selection info
+		if m.autoSelected {
+			content.WriteString("  🚀 Auto-selected in-progress run\n")
+			linesUsed++
+		}
 
-				// Focus appropriate input
-				if m.prCreateStep == 0 {
-					m.prTitleInput.Focus()
-					m.prDescInput.Blur()
-				} else if m.prCreateStep == 1 {
-					m.prTitleInput.Blur()
-					m.prDescInput.Focus()
-				} else {
-					m.prTitleInput.Blur()
-					m.prDescInput.Blur()
-				}
-				return m, tea.Batch(cmds...)
-			} else if !m.searchMode && !m.showRepoOptions {
-				if m.focusedPanel == 0 {
-					m.focusedPanel = 1
-				} else {
-					m.focusedPanel = 0
-				}
-				m.cursor = 0
+		// Show refresh info
+		if m.autoRefresh {
+			refreshTime := "Never"
+			if !m.lastRefresh.IsZero() {
+				refreshTime = m.lastRefresh.Format("15:04:05")
 			}
-		case "n":
-			if m.showPRs && !m.prCreateMode {
-				// Start PR creation process
-				m.showPRCreate = true
-				m.prCreateMode = true
-				m.prCreateStep = 0
-				m.cursor = 0
-				m.reviewerSearch = ""
+			content.WriteString(fmt.Sprintf("  🔄 Auto-refresh enabled (Last: %s)\n", refreshTime))
+			linesUsed++
+		}
+		if m.runCancelConfirm {
+			warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")) // Red
+			content.WriteString("  " + warningStyle.Render("Press x again to cancel this run, Esc to keep it") + "\n\n")
+		} else if m.runActionMessage != "" && time.Since(m.runActionTime) < 10*time.Second {
+			messageColor := lipgloss.Color("2") // Green for success
+			if strings.Contains(m.runActionMessage, "Failed") {
+				messageColor = lipgloss.Color("1") // Red for error
+			}
+			messageStyle := lipgloss.NewStyle().Foreground(messageColor)
+			content.WriteString("  " + messageStyle.Render(truncateRunes(m.runActionMessage, contentWidth)) + "\n\n")
+		} else {
+			content.WriteString("  Press 'r' to refresh manually, Esc to go back\n\n")
+		}
+		linesUsed += 2
 
-				// Initialize text inputs
-				m.prTitleInput = textinput.New()
-				m.prTitleInput.Placeholder = "Enter PR title..."
-				m.prTitleInput.Focus()
-				m.prTitleInput.Width = 50
+		if checks := m.runPendingChecks(); len(checks) > 0 {
+			waitingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3")) // Yellow
+			text := fmt.Sprintf("⏸ Waiting on %d approval or check", len(checks))
+			if len(checks) > 1 {
+				text = fmt.Sprintf("⏸ Waiting on %d approvals and checks", len(checks))
+			}
+			content.WriteString("  " + waitingStyle.Render(text+", press 'a' to review") + "\n")
+			linesUsed++
+		}
 
-				m.prDescInput = textinput.New()
-				m.prDescInput.Placeholder = "Enter PR description..."
-				m.prDescInput.Width = 50
+		// Show the timeline as a tree of stages, jobs and tasks
+		rows := m.timelineRows()
+		start := m.timelineScroll
+		end := start + visibleLines - linesUsed
+		if end > len(rows) {
+			end = len(rows)
+		}
 
-				// Clear previous selections
-				m.prReviewers = []graph.GraphUser{}
-				m.prSourceBranch = nil
-				m.prTargetBranch = nil
+		now := time.Now()
+		for i := start; i < end; i++ {
+			row := rows[i]
+			record := row.Node.Record
 
-				// Load branches and users
-				if m.selectedProject != nil && m.selectedRepo != nil && m.selectedRepo.Id != nil {
-					repoID := m.selectedRepo.Id.String()
-					m.loadingBranches = true
-					m.loadingUsers = true
-					return m, tea.Batch(append(cmds,
-						loadRepoBranches(*m.selectedProject.Name, repoID, m.config),
-						loadOrgUsers(m.config))...)
-				}
-				return m, tea.Batch(cmds...)
+			// Format step display
+			stepName := "Unknown Step"
+			if record.Name != nil {
+				stepName = *record.Name
 			}
-		case "x":
-			if m.prCreateMode && m.prCreateStep == 4 && len(m.prReviewers) > 0 {
-				// Remove the last added reviewer
-				if len(m.prReviewers) > 0 {
-					m.prReviewers = m.prReviewers[:len(m.prReviewers)-1]
+			marker := "  "
+			if len(row.Node.Children) > 0 {
+				marker = "▸ "
+				if m.timelineIsExpanded(row.Node) {
+					marker = "▾ "
 				}
-				return m, tea.Batch(cmds...)
-			}
-		case "a":
-			if m.showPRDetails && m.prDetails != nil && m.prDetails.Status != nil &&
-				*m.prDetails.Status == git.PullRequestStatusValues.Active {
-				// Approve PR
-				return m, tea.Batch(append(cmds, m.approvePR(10, "Approved via AZTUI"))...)
 			}
-		case "d":
-			if m.showPRDetails && m.prDetails != nil && m.prDetails.Status != nil &&
-				*m.prDetails.Status == git.PullRequestStatusValues.Active {
-				// Decline PR
-				return m, tea.Batch(append(cmds, m.approvePR(-10, "Declined via AZTUI"))...)
+			stepName = strings.Repeat("  ", row.Depth) + marker + stepName
+
+			status, statusColor := timelineStatus(row.Node)
+
+			durationText := ""
+			if duration, ok := timeline.Duration(record, now); ok {
+				durationText = formatDuration(duration)
 			}
-		case "c":
-			if m.showPRDetails && m.prDetails != nil && m.prDetails.Status != nil &&
-				*m.prDetails.Status == git.PullRequestStatusValues.Active {
-				// Complete PR
-				return m, tea.Batch(append(cmds, m.completePR())...)
+
+			// Truncate step name if too long
+			maxStepLen := contentWidth - 26 // Leave space for status and duration
+			if maxStepLen < 10 {
+				maxStepLen = 10
 			}
-		case "o":
-			if m.showPRDetails && m.prDetails != nil && m.prDetails.Status != nil &&
-				*m.prDetails.Status == git.PullRequestStatusValues.Active {
-				// Start override process
-				m.showPRReview = true
-				m.prOverrideMode = true
-				m.prOverrideInput = textinput.New()
-				m.prOverrideInput.Placeholder = "Enter override reason..."
-				m.prOverrideInput.Focus()
-				m.prOverrideInput.Width = 50
-				return m, tea.Batch(cmds...)
+			stepName = truncateRunes(stepName, maxStepLen)
+
+			line := fmt.Sprintf("  %-*s %-16s %7s", maxStepLen, stepName, status, durationText)
+
+			// Apply color to status
+			statusStyle := lipgloss.NewStyle().Foreground(statusColor)
+			coloredLine := fmt.Sprintf("  %-*s %s %7s", maxStepLen, stepName, statusStyle.Render(fmt.Sprintf("%-16s", status)), durationText)
+
+			if m.cursor == i {
+				// Create full-width highlight
+				paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+				coloredLine = fullWidthHighlightStyle.Render(paddedLine)
 			}
+
+			content.WriteString(coloredLine + "\n")
+			linesUsed++
 		}
 	}
-	return m, tea.Batch(cmds...)
+
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
+		content.WriteString("\n")
+		linesUsed++
+	}
+
+	return content.String()
 }
 
-func (m *model) updateScroll() {
-	boxHeight := (m.height - 8) / 2
-	visibleLines := boxHeight - 4
+func (m model) renderRunPipeline(visibleLines int) string {
+	var content strings.Builder
+	linesUsed := 0
 
-	if m.focusedPanel == 0 {
-		if m.cursor < m.projectsScroll {
-			m.projectsScroll = m.cursor
-		} else if m.cursor >= m.projectsScroll+visibleLines {
-			m.projectsScroll = m.cursor - visibleLines + 1
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
+
+	heading := func(step int, title string) string {
+		if m.runPipelineStep == step {
+			return highlightStyle.Render("→ " + title)
 		}
-	} else if m.focusedPanel == 1 {
-		if m.cursor < m.reposScroll {
-			m.reposScroll = m.cursor
-		} else if m.cursor >= m.reposScroll+visibleLines {
-			m.reposScroll = m.cursor - visibleLines + 1
+		return "  " + title
+	}
+	item := func(i int, text string) string {
+		prefix := "    "
+		if i == m.cursor {
+			prefix = "  → "
 		}
-	} else if m.focusedPanel == 2 || m.showPipelines {
-		if m.cursor < m.pipelinesScroll {
-			m.pipelinesScroll = m.cursor
-		} else if m.cursor >= m.pipelinesScroll+visibleLines {
-			m.pipelinesScroll = m.cursor - visibleLines + 1
+		return prefix + truncateRunes(text, contentWidth-4) + "\n"
+	}
+
+	// Branch, with the matching branches listed while the section is active
+	branchName := "(Select branch)"
+	if m.runBranch != "" {
+		branchName = strings.TrimPrefix(m.runBranch, "refs/heads/")
+	}
+	content.WriteString(heading(0, "Branch:") + " " + branchName + "\n")
+	linesUsed++
+	if m.runPipelineStep == 0 {
+		content.WriteString("    Filter: " + m.runBranchFilter + "\n")
+		linesUsed++
+		branches := m.runFilteredBranches()
+		start := 0
+		if m.cursor >= maxRunBranchLines {
+			start = m.cursor - maxRunBranchLines + 1
 		}
-	} else if m.showRuns {
-		if m.cursor < m.runsScroll {
-			m.runsScroll = m.cursor
-		} else if m.cursor >= m.runsScroll+visibleLines {
-			m.runsScroll = m.cursor - visibleLines + 1
+		for i := start; i < len(branches) && i < start+maxRunBranchLines; i++ {
+			content.WriteString(item(i, strings.TrimPrefix(*branches[i].Name, "refs/heads/")))
+			linesUsed++
 		}
-	} else if m.showPRs {
-		if m.cursor < m.prsScroll {
-			m.prsScroll = m.cursor
-		} else if m.cursor >= m.prsScroll+visibleLines {
-			m.prsScroll = m.cursor - visibleLines + 1
+	}
+	content.WriteString("\n")
+	linesUsed++
+
+	if m.loadingRunOptions {
+		content.WriteString("  " + m.pipelinesSpinner.View() + " Loading parameters and stages...\n\n")
+		linesUsed += 2
+	}
+
+	content.WriteString(heading(1, "Parameters:") + "\n")
+	linesUsed++
+	if len(m.runParameters) == 0 {
+		content.WriteString("    (None)\n")
+		linesUsed++
+	}
+	for i, parameter := range m.runParameters {
+		name := parameter.Name
+		if parameter.DisplayName != "" {
+			name = parameter.DisplayName
 		}
-	} else if m.showPRDetails {
-		// No scrolling needed for PR details view
-	} else if m.showRunDetails {
-		if m.cursor < m.timelineScroll {
-			m.timelineScroll = m.cursor
-		} else if m.cursor >= m.timelineScroll+visibleLines {
-			m.timelineScroll = m.cursor - visibleLines + 1
+		value := m.runParameterValues[parameter.Name]
+		if len(parameter.Values) > 0 {
+			value = "‹" + value + "›"
+		}
+		if m.runPipelineStep == 1 {
+			content.WriteString(item(i, name+": "+value))
+		} else {
+			content.WriteString(item(-1, name+": "+value))
 		}
+		linesUsed++
 	}
-}
+	content.WriteString("\n")
+	linesUsed++
 
-func (m model) renderLoadingAnimation(visibleLines int, message string, spinner spinner.Model) string {
-	var content strings.Builder
+	content.WriteString(heading(2, "Variables:") + "\n")
+	linesUsed++
+	if len(m.runVariables) == 0 {
+		content.WriteString("    (None settable at queue time)\n")
+		linesUsed++
+	}
+	for i, variable := range m.runVariables {
+		text := variable.Name + ": " + m.runVariableValues[variable.Name]
+		if m.runPipelineStep == 2 {
+			content.WriteString(item(i, text))
+		} else {
+			content.WriteString(item(-1, text))
+		}
+		linesUsed++
+	}
+	content.WriteString("\n")
+	linesUsed++
 
-	content.WriteString(fmt.Sprintf("  %s %s\n", spinner.View(), message))
+	content.WriteString(heading(3, "Stages to skip:") + "\n")
+	linesUsed++
+	if len(m.runStages) == 0 {
+		content.WriteString("    (None)\n")
+		linesUsed++
+	}
+	for i, stage := range m.runStages {
+		checkbox := "[ ] "
+		if m.runSkipStages[stage] {
+			checkbox = "[x] "
+		}
+		if m.runPipelineStep == 3 {
+			content.WriteString(item(i, checkbox+stage))
+		} else {
+			content.WriteString(item(-1, checkbox+stage))
+		}
+		linesUsed++
+	}
+	content.WriteString("\n")
+	linesUsed++
 
-	// Fill remaining space
-	for i := 1; i < visibleLines; i++ {
+	if m.queueingRun {
+		content.WriteString("  " + m.pipelinesSpinner.View() + " Queueing run...\n")
+		linesUsed++
+	} else if m.runPipelineError != "" {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")) // Red
+		content.WriteString("  " + errorStyle.Render(truncateRunes(m.runPipelineError, contentWidth)) + "\n")
+		linesUsed++
+	}
+
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
 		content.WriteString("\n")
+		linesUsed++
 	}
 
 	return content.String()
 }
 
-func (m model) renderInitialLoadingScreen() string {
-	logoText := `   ▄████████  ▄███████▄      ███     ███    █▄   ▄█ 
-   ███    ███ ██▀     ▄██ ▀█████████▄ ███    ███ ███ 
-   ███    ███       ▄███▀    ▀███▀▀██ ███    ███ ███▌
-   ███    ███  ▀█▀▄███▀▄▄     ███   ▀ ███    ███ ███▌
- ▀███████████   ▄███▀   ▀     ███     ███    ███ ███▌
-   ███    ███ ▄███▀           ███     ███    ███ ███ 
-   ███    ███ ███▄     ▄█     ███     ███    ███ ███ 
-   ███    █▀   ▀████████▀    ▄████▀   ████████▀  █▀`
+func (m model) renderRunApprovals(visibleLines int) string {
+	var content strings.Builder
+	linesUsed := 0
 
-	logoStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("12")).
-		Bold(true).
-		Align(lipgloss.Center)
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
 
-	loadingText := ""
-	if m.loadingProjects && !m.autoDetectDone {
-		loadingText = fmt.Sprintf("\n\n%s Loading projects and detecting current repository...", m.projectsSpinner.View())
-	} else if m.loadingProjects {
-		loadingText = fmt.Sprintf("\n\n%s Loading projects...", m.projectsSpinner.View())
-	} else if !m.autoDetectDone {
-		loadingText = fmt.Sprintf("\n\n%s Detecting current repository...", m.projectsSpinner.View())
+	checks := m.runPendingChecks()
+	if len(checks) == 0 {
+		content.WriteString("  The run is not waiting on any approvals or checks\n")
+		linesUsed++
 	}
 
-	loadingStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Align(lipgloss.Center)
+	// Lay out each check, then scroll so the selected one is in view
+	waitingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3")) // Yellow
+	var entries [][]string
+	for i, record := range checks {
+		var lines []string
+		name := "Check"
+		if record.Name != nil {
+			name = *record.Name
+		}
+		if stage := m.runRecordStage(record); stage != "" {
+			name = stage + " • " + name
+		}
+		title := truncateRunes(name, contentWidth-16) + "  " + waitingStyle.Render("⏳ Waiting")
+		if i == m.cursor {
+			lines = append(lines, highlightStyle.Render("→ ")+title)
+		} else {
+			lines = append(lines, "  "+title)
+		}
 
-	// Center everything vertically
-	contentHeight := 8 + 3 // Logo height + loading text
-	paddingTop := (m.height - contentHeight) / 2
-	if paddingTop < 0 {
-		paddingTop = 0
-	}
+		approval, ok := m.runApprovals[*record.Id]
+		if *record.Type != "Checkpoint.Approval" {
+			lines = append(lines, "    Waiting for the check to pass")
+		} else if !ok {
+			lines = append(lines, "    Loading approval...")
+		} else {
+			if approval.Instructions != nil && strings.TrimSpace(*approval.Instructions) != "" {
+				lines = append(lines, "    Instructions:")
+				for _, line := range markdown.Parse(*approval.Instructions, contentWidth-6) {
+					lines = append(lines, "      "+line.Text)
+				}
+			}
 
-	var content strings.Builder
+			approvers := "    Approvers:"
+			if approval.MinRequiredApprovers != nil && approval.Steps != nil && *approval.MinRequiredApprovers < len(*approval.Steps) {
+				approvers = fmt.Sprintf("    Approvers (%d of %d required):", *approval.MinRequiredApprovers, len(*approval.Steps))
+			}
+			lines = append(lines, approvers)
+			if approval.Steps != nil {
+				for _, step := range *approval.Steps {
+					lines = append(lines, "      "+truncateRunes(approvalStepText(step), contentWidth-8))
+				}
+			}
+		}
+		lines = append(lines, "")
+		entries = append(entries, lines)
+	}
 
-	// Add top padding
-	for i := 0; i < paddingTop; i++ {
-		content.WriteString("\n")
+	footerLines := 2
+	start := 0
+	for start < m.cursor {
+		used := 0
+		for i := start; i <= m.cursor && i < len(entries); i++ {
+			used += len(entries[i])
+		}
+		if used <= visibleLines-footerLines {
+			break
+		}
+		start++
+	}
+	for i := start; i < len(entries); i++ {
+		for _, line := range entries[i] {
+			if linesUsed >= visibleLines-footerLines {
+				break
+			}
+			content.WriteString(line + "\n")
+			linesUsed++
+		}
 	}
 
-	// Add logo
-	content.WriteString(logoStyle.Width(m.width).Render(logoText))
+	if m.runApprovalMode {
+		label := "Approve with comment:"
+		if m.runApprovalReject {
+			label = "Reject with comment:"
+		}
+		content.WriteString("  " + label + "\n")
+		content.WriteString("  " + m.runApprovalInput.View() + "\n")
+		linesUsed += 2
+	} else if m.runActionMessage != "" && time.Since(m.runActionTime) < 10*time.Second {
+		messageColor := lipgloss.Color("2") // Green for success
+		if strings.Contains(m.runActionMessage, "Failed") {
+			messageColor = lipgloss.Color("1") // Red for error
+		}
+		messageStyle := lipgloss.NewStyle().Foreground(messageColor)
+		content.WriteString("  " + messageStyle.Render(truncateRunes(m.runActionMessage, contentWidth)) + "\n")
+		linesUsed++
+	}
 
-	// Add loading text
-	content.WriteString(loadingStyle.Width(m.width).Render(loadingText))
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
+		content.WriteString("\n")
+		linesUsed++
+	}
 
 	return content.String()
 }
 
-func (m *model) filterItems() {
-	m.filteredItems = nil
-
-	if m.searchQuery == "" {
-		if m.focusedPanel == 0 {
-			for _, project := range m.projects {
-				m.filteredItems = append(m.filteredItems, project)
-			}
-		} else if m.focusedPanel == 1 {
-			for _, repo := range m.repos {
-				m.filteredItems = append(m.filteredItems, repo)
-			}
-		}
-		return
+// approvalStepText describes an approver and where their part of the approval stands
+func approvalStepText(step pipelinesapproval.ApprovalStep) string {
+	approver := "Unknown"
+	if step.AssignedApprover != nil && step.AssignedApprover.DisplayName != nil {
+		approver = *step.AssignedApprover.DisplayName
+	}
+	status := pipelinesapproval.ApprovalStatusValues.Pending
+	if step.Status != nil {
+		status = *step.Status
 	}
 
-	query := strings.ToLower(m.searchQuery)
+	var text string
+	switch status {
+	case pipelinesapproval.ApprovalStatusValues.Approved:
+		text = "✅ " + approver + " approved"
+	case pipelinesapproval.ApprovalStatusValues.Rejected:
+		text = "❌ " + approver + " rejected"
+	case pipelinesapproval.ApprovalStatusValues.Uninitiated:
+		text = "·  " + approver + " (after earlier approvers)"
+	default:
+		text = "⏳ " + approver
+	}
+	// A group can be assigned while one of its members approves
+	if step.ActualApprover != nil && step.ActualApprover.DisplayName != nil && *step.ActualApprover.DisplayName != approver {
+		text += " by " + *step.ActualApprover.DisplayName
+	}
+	if step.Comment != nil && *step.Comment != "" {
+		text += ": " + *step.Comment
+	}
+	return text
+}
 
-	if m.focusedPanel == 0 {
-		for _, project := range m.projects {
-			if project.Name != nil && strings.Contains(strings.ToLower(*project.Name), query) {
-				m.filteredItems = append(m.filteredItems, project)
-			}
+// timelineStatus describes a timeline node's state, with the progress of its children while it runs
+func timelineStatus(node *timeline.Node) (string, lipgloss.Color) {
+	state, result := timeline.Status(node)
+	switch state {
+	case build.TimelineRecordStateValues.Completed:
+		if result == nil {
+			return "✓ Completed", lipgloss.Color("2") // Green
 		}
-	} else if m.focusedPanel == 1 {
-		for _, repo := range m.repos {
-			if repo.Name != nil && strings.Contains(strings.ToLower(*repo.Name), query) {
-				m.filteredItems = append(m.filteredItems, repo)
-			}
+		switch *result {
+		case build.TaskResultValues.Succeeded:
+			return "✓ Succeeded", lipgloss.Color("2") // Green
+		case build.TaskResultValues.SucceededWithIssues:
+			return "! Warnings", lipgloss.Color("3") // Yellow
+		case build.TaskResultValues.Skipped:
+			return "⊘ Skipped", lipgloss.Color("240") // Gray
+		case build.TaskResultValues.Canceled, build.TaskResultValues.Abandoned:
+			return "✗ Canceled", lipgloss.Color("1") // Red
+		}
+		return "✗ Failed", lipgloss.Color("1") // Red
+	case build.TimelineRecordStateValues.InProgress:
+		if completed, total := timeline.Progress(node); total > 0 {
+			return fmt.Sprintf("⏳ Running %d/%d", completed, total), lipgloss.Color("3") // Yellow
 		}
+		return "⏳ Running", lipgloss.Color("3") // Yellow
 	}
+	return "⏸ Pending", lipgloss.Color("240") // Gray
 }
 
-func (m *model) filterReviewers() {
-	m.filteredReviewers = nil
+// formatDuration shows a duration the way the web UI does, such as 45s, 3m 12s or 1h 5m
+func formatDuration(duration time.Duration) string {
+	duration = duration.Round(time.Second)
+	switch {
+	case duration < time.Minute:
+		return fmt.Sprintf("%ds", int(duration.Seconds()))
+	case duration < time.Hour:
+		return fmt.Sprintf("%dm %ds", int(duration.Minutes()), int(duration.Seconds())%60)
+	}
+	return fmt.Sprintf("%dh %dm", int(duration.Hours()), int(duration.Minutes())%60)
+}
 
-	if m.reviewerSearch == "" {
-		m.filteredReviewers = m.users
-		return
+func (m model) renderRunLog(visibleLines int) string {
+	// Show loading animation while the log is fetched
+	if m.loadingRunLog {
+		return m.renderLoadingAnimation(visibleLines, "Loading log", m.timelineSpinner)
 	}
 
-	query := strings.ToLower(m.reviewerSearch)
+	var content strings.Builder
+	linesUsed := 0
 
-	for _, user := range m.users {
-		if user.DisplayName != nil && strings.Contains(strings.ToLower(*user.DisplayName), query) {
-			m.filteredReviewers = append(m.filteredReviewers, user)
-		} else if user.MailAddress != nil && strings.Contains(strings.ToLower(*user.MailAddress), query) {
-			m.filteredReviewers = append(m.filteredReviewers, user)
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
+
+	status := fmt.Sprintf("  %d lines", len(m.runLogLines))
+	if m.runLogTailing {
+		status += "   •   ⏳ Running"
+		if m.runLogFollow {
+			status += ", following new output"
 		}
 	}
-}
+	content.WriteString(status + "\n\n")
+	linesUsed += runLogHeaderLines
 
-func (m model) View() string {
-	// Show config modal if needed
-	if m.showConfigModal {
-		return m.configModal.View()
+	if m.runLogId == 0 {
+		content.WriteString("  No log yet, it appears once the step starts\n")
+		linesUsed++
+	} else if len(m.runLogLines) == 0 {
+		content.WriteString("  The log is empty\n")
+		linesUsed++
 	}
 
-	// Show initial loading screen with logo
-	if m.initialLoading {
-		return m.renderInitialLoadingScreen()
+	for i := m.runLogStart(visibleLines - linesUsed); i < len(m.runLogLines) && linesUsed < visibleLines; i++ {
+		content.WriteString("  " + formatLogLine(m.runLogLines[i], contentWidth-2) + "\n")
+		linesUsed++
 	}
 
-	// Calculate responsive layout with proper margins
-	instructionHeight := 2 // Reduced space for instructions
-	searchHeight := 3      // Always reserve space for search bar
-	totalAvailableHeight := m.height - instructionHeight - searchHeight
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
+		content.WriteString("\n")
+		linesUsed++
+	}
 
-	// Ensure we have enough space to work with
-	if totalAvailableHeight < 10 {
-		totalAvailableHeight = 10
+	return content.String()
+}
+
+// formatLogLine drops the timestamp and terminal escapes from a log line and colors the
+// logging commands the way the web log viewer does
+func formatLogLine(line string, width int) string {
+	line = logTimestampPattern.ReplaceAllString(line, "")
+	line = ansiEscapePattern.ReplaceAllString(line, "")
+	line = strings.ReplaceAll(line, "\t", "    ")
+
+	var style *lipgloss.Style
+	for _, command := range []struct {
+		prefix string
+		color  lipgloss.Color
+	}{
+		{"##[error]", lipgloss.Color("1")},   // Red
+		{"##[warning]", lipgloss.Color("3")}, // Yellow
+		{"##[section]", lipgloss.Color("2")}, // Green
+		{"##[command]", lipgloss.Color("6")}, // Cyan
+		{"##[group]", lipgloss.Color("6")},   // Cyan
+		{"##[endgroup]", lipgloss.Color("240")},
+	} {
+		if strings.HasPrefix(line, command.prefix) {
+			line = strings.TrimPrefix(line, command.prefix)
+			commandStyle := lipgloss.NewStyle().Foreground(command.color)
+			style = &commandStyle
+			break
+		}
 	}
-	if m.width < 40 {
-		// For very small terminals, just return a simple message
-		return "Terminal too small. Please resize."
+
+	line = truncateRunes(line, width)
+	if style != nil {
+		return style.Render(line)
 	}
+	return line
+}
 
-	// Calculate exact dimensions - left takes half, right takes half
-	leftWidth := m.width / 2
-	rightWidth := m.width - leftWidth
-	leftBoxHeight := totalAvailableHeight / 2 // Each left box gets exactly half
-	rightBoxHeight := totalAvailableHeight    // Right panel height matches total available height
+func (m model) renderPRs(visibleLines int) string {
+	// Show loading animation if PRs are loading
+	if m.loadingPRs {
+		return m.renderLoadingAnimation(visibleLines, "Loading pull requests", m.prsSpinner)
+	}
 
-	// Ensure minimum dimensions
-	if leftWidth < 20 {
-		leftWidth = 20
-		rightWidth = m.width - leftWidth
+	var content strings.Builder
+	linesUsed := 0
+
+	// Calculate content width for full-width highlighting
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
+
+	// Show PRs list
+	start := m.prsScroll
+	end := start + visibleLines
+	if end > len(m.prs) {
+		end = len(m.prs)
 	}
-	if rightWidth < 20 {
-		rightWidth = 20
-		leftWidth = m.width - rightWidth
+
+	if m.prsError != "" {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")) // Red
+		content.WriteString("  " + errorStyle.Render(truncateRunes(m.prsError, contentWidth-2)) + "\n")
+		linesUsed++
+	} else if len(m.prs) == 0 {
+		content.WriteString("  No pull requests match the current filters\n")
+		linesUsed++
 	}
-	if leftBoxHeight < 3 {
-		leftBoxHeight = 3
+
+	for i := start; i < end; i++ {
+		pr := m.prs[i]
+		prDisplay := ""
+
+		if pr.Title != nil {
+			prDisplay = *pr.Title
+		} else if pr.PullRequestId != nil {
+			prDisplay = fmt.Sprintf("PR #%d", *pr.PullRequestId)
+		}
+
+		// Add labels if any
+		if labels := prs.LabelNames(pr); len(labels) > 0 {
+			prDisplay = fmt.Sprintf("%s [%s]", prDisplay, strings.Join(labels, ", "))
+		}
+
+		// Add status if available
+		if pr.Status != nil {
+			statusText := string(*pr.Status)
+			if pr.IsDraft != nil && *pr.IsDraft {
+				statusText += ", draft"
+			}
+			if pr.AutoCompleteSetBy != nil && *pr.Status == git.PullRequestStatusValues.Active {
+				statusText += ", auto-complete"
+			}
+			prDisplay = fmt.Sprintf("%s (%s)", prDisplay, statusText)
+		}
+
+		// Truncate if too long
+		maxLen := contentWidth - 4
+		if maxLen < 1 {
+			maxLen = 1
+		}
+		if len(prDisplay) > maxLen {
+			prDisplay = prDisplay[:maxLen-3] + "..."
+		}
+
+		line := fmt.Sprintf("  %s", prDisplay)
+
+		if m.cursor == i {
+			// Create full-width highlight
+			paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+			line = fullWidthHighlightStyle.Render(paddedLine)
+		}
+
+		content.WriteString(line + "\n")
+		linesUsed++
 	}
-	if rightBoxHeight < 6 {
-		rightBoxHeight = 6
+
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
+		content.WriteString("\n")
+		linesUsed++
 	}
 
-	// Calculate content area (subtract borders and padding)
-	leftContentWidth := leftWidth - 4
-	rightContentWidth := rightWidth - 4
-	leftContentHeight := leftBoxHeight - 4
-	rightContentHeight := rightBoxHeight - 4 // Right panel content height matches total available height
+	return content.String()
+}
 
-	// Ensure content area is not negative and has reasonable minimums
-	if leftContentWidth < 5 {
-		leftContentWidth = 5
-	}
-	if rightContentWidth < 5 {
-		rightContentWidth = 5
+func (m model) renderDashboard(visibleLines int) string {
+	// Show loading animation while querying every repository
+	if m.loadingDashboard {
+		return m.renderLoadingAnimation(visibleLines, "Loading your pull requests", m.prsSpinner)
 	}
-	if leftContentHeight < 1 {
-		leftContentHeight = 1
+
+	var content strings.Builder
+	linesUsed := 0
+
+	// Calculate content width for full-width highlighting
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
+
+	if len(m.dashboardPRs) == 0 {
+		content.WriteString("  No active pull requests created by you or waiting on you\n")
+		linesUsed++
 	}
-	if rightContentHeight < 1 {
-		rightContentHeight = 1
+
+	userID := ""
+	if m.currentUser != nil && m.currentUser.Id != nil {
+		userID = *m.currentUser.Id
 	}
 
-	projectsContent := m.renderProjects(leftContentHeight - 1)
-	reposContent := m.renderRepos(leftContentHeight - 1)
+	// Build all lines first so the cursor can be kept in view
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	var lines []string
+	cursorLine := 0
+	group := -1
+	for i, pr := range m.dashboardPRs {
+		if prGroup := dashboardGroup(pr, userID); prGroup != group {
+			if group >= 0 {
+				lines = append(lines, "")
+			}
+			group = prGroup
+			lines = append(lines, "  "+headerStyle.Render(dashboardGroupNames[group]))
+		}
 
-	// Create dynamic styles based on focus
-	projectsStyle := baseBoxStyle.Copy()
-	reposStyle := baseBoxStyle.Copy()
+		prDisplay := fmt.Sprintf("PR #%d", *pr.PullRequestId)
+		if pr.Title != nil {
+			prDisplay = *pr.Title
+		}
+		if pr.Repository != nil && pr.Repository.Name != nil {
+			prDisplay = fmt.Sprintf("[%s] %s", *pr.Repository.Name, prDisplay)
+		}
+		if pr.IsDraft != nil && *pr.IsDraft {
+			prDisplay += " (draft)"
+		}
 
-	if m.focusedPanel == 0 && !m.showRepoOptions {
-		projectsStyle = projectsStyle.BorderForeground(lipgloss.Color("12")) // Blue highlight
-	} else {
-		projectsStyle = projectsStyle.BorderForeground(lipgloss.Color("240")) // Gray
-	}
+		// Truncate if too long
+		maxLen := contentWidth - 6
+		if maxLen < 4 {
+			maxLen = 4
+		}
+		prDisplay = truncateRunes(prDisplay, maxLen)
 
-	if (m.focusedPanel == 1 && !m.showRepoOptions) || m.showRepoOptions {
-		reposStyle = reposStyle.BorderForeground(lipgloss.Color("12")) // Blue highlight
-	} else {
-		reposStyle = reposStyle.BorderForeground(lipgloss.Color("240")) // Gray
+		line := "    " + prDisplay
+		if m.cursor == i {
+			cursorLine = len(lines)
+			paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+			line = fullWidthHighlightStyle.Render(paddedLine)
+		}
+		lines = append(lines, line)
 	}
 
-	// Create titles with border styling
-	projectsTitle := "┤ Projects ├"
-	reposTitle := "┤ Repositories ├"
-	if m.showRepoOptions {
-		reposTitle = "┤ Repository Options ├"
+	start := 0
+	if cursorLine >= visibleLines-linesUsed {
+		start = cursorLine - (visibleLines - linesUsed) + 1
 	}
-	if m.selectedProject != nil && m.selectedProject.Name != nil {
-		reposTitle = "┤ " + *m.selectedProject.Name + " ├"
+	for i := start; i < len(lines) && linesUsed < visibleLines; i++ {
+		content.WriteString(lines[i] + "\n")
+		linesUsed++
 	}
 
-	// Create boxes with titles embedded in content
-	projectsBox := projectsStyle.
-		Width(leftContentWidth).
-		Height(leftContentHeight).
-		Render(projectsTitle + "\n" + projectsContent)
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
+		content.WriteString("\n")
+		linesUsed++
+	}
 
-	reposBox := reposStyle.
-		Width(leftContentWidth).
-		Height(leftContentHeight).
-		Render(reposTitle + "\n" + reposContent)
+	return content.String()
+}
 
-	// Create right panel content
-	var rightPanelContent string
-	var rightPanelTitle string
+func (m model) renderPRCreate(visibleLines int) string {
+	var content strings.Builder
+	linesUsed := 0
 
-	if m.showPRCreate {
-		rightPanelTitle = "┤ Create Pull Request ├"
-		rightPanelContent = m.renderPRCreate(rightContentHeight - 1)
-	} else if m.showPRDetails {
-		if m.prOverrideMode {
-			rightPanelTitle = "┤ Override PR ├"
-			rightPanelContent = m.renderPROverride(rightContentHeight - 1)
+	if m.prCreateMode {
+		content.WriteString("  Create New Pull Request\n\n")
+		linesUsed += 2
+
+		// Show form fields with highlighting for current step
+		titleStyle := ""
+		if m.prCreateStep == 0 {
+			titleStyle = highlightStyle.Render("→ Title:")
 		} else {
-			rightPanelTitle = "┤ PR Details ├"
-			if m.selectedPR != nil && m.selectedPR.Title != nil {
-				rightPanelTitle = "┤ " + *m.selectedPR.Title + " ├"
+			titleStyle = "  Title:"
+		}
+		content.WriteString(titleStyle + "\n")
+		content.WriteString("  " + m.prTitleInput.View() + "\n\n")
+		linesUsed += 3
+
+		descStyle := ""
+		if m.prCreateStep == 1 {
+			descStyle = highlightStyle.Render("→ Description:")
+		} else {
+			descStyle = "  Description:"
+		}
+		content.WriteString(descStyle + "\n")
+		for _, line := range strings.Split(m.prDescInput.View(), "\n") {
+			content.WriteString("  " + line + "\n")
+			linesUsed++
+		}
+		content.WriteString("\n")
+		linesUsed += 2
+
+		// Show branch selection with highlighting
+		sourceStyle := ""
+		if m.prCreateStep == 2 {
+			sourceStyle = highlightStyle.Render("→ Source Branch:")
+		} else {
+			sourceStyle = "  Source Branch:"
+		}
+		content.WriteString(sourceStyle + " ")
+		if m.prSourceBranch != nil && m.prSourceBranch.Name != nil {
+			branchName := strings.TrimPrefix(*m.prSourceBranch.Name, "refs/heads/")
+			content.WriteString(branchName)
+		} else {
+			content.WriteString("(Select branch)")
+		}
+		if m.prCreateStep == 2 {
+			content.WriteString(" ↑/↓ to change")
+		}
+		content.WriteString("\n")
+		linesUsed++
+
+		// Offer to push a local branch the server does not have yet
+		warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3")) // Yellow
+		if m.pushingBranch {
+			content.WriteString("  " + warningStyle.Render(fmt.Sprintf("Pushing %s...", m.prLocalBranch)) + "\n")
+			linesUsed++
+		} else if m.localBranchUnpushed() {
+			content.WriteString("  " + warningStyle.Render(fmt.Sprintf("Local branch %s is not pushed. Ctrl+P: Push it", m.prLocalBranch)) + "\n")
+			linesUsed++
+		}
+		if m.prActionMessage != "" && time.Since(m.prActionTime) < 10*time.Second {
+			messageColor := lipgloss.Color("2") // Green for success
+			if strings.Contains(m.prActionMessage, "Failed") {
+				messageColor = lipgloss.Color("1") // Red for error
 			}
-			rightPanelContent = m.renderPRDetails(rightContentHeight - 1)
+			messageStyle := lipgloss.NewStyle().Foreground(messageColor)
+			content.WriteString("  " + messageStyle.Render(truncateRunes(m.prActionMessage, m.width-m.width/2-8)) + "\n")
+			linesUsed++
 		}
-	} else if m.showRunDetails {
-		rightPanelTitle = "┤ Run Details ├"
-		if m.selectedRun != nil && m.selectedRun.Name != nil {
-			rightPanelTitle = "┤ " + *m.selectedRun.Name + " ├"
+
+		targetStyle := ""
+		if m.prCreateStep == 3 {
+			targetStyle = highlightStyle.Render("→ Target Branch:")
+		} else {
+			targetStyle = "  Target Branch:"
 		}
-		rightPanelContent = m.renderRunDetails(rightContentHeight - 1)
-	} else if m.showRuns {
-		rightPanelTitle = "┤ Pipeline Runs ├"
-		if m.selectedPipeline != nil && m.selectedPipeline.Name != nil {
-			rightPanelTitle = "┤ " + *m.selectedPipeline.Name + " Runs ├"
+		content.WriteString(targetStyle + " ")
+		if m.prTargetBranch != nil && m.prTargetBranch.Name != nil {
+			branchName := strings.TrimPrefix(*m.prTargetBranch.Name, "refs/heads/")
+			content.WriteString(branchName)
+		} else {
+			content.WriteString("main")
 		}
-		rightPanelContent = m.renderRuns(rightContentHeight - 1)
-	} else if m.showPRs {
-		rightPanelTitle = "┤ Pull Requests ├"
-		if m.selectedRepo != nil && m.selectedRepo.Name != nil {
-			rightPanelTitle = "┤ " + *m.selectedRepo.Name + " PRs ├"
+		if m.prCreateStep == 3 {
+			content.WriteString(" ↑/↓ to change")
 		}
-		rightPanelContent = m.renderPRs(rightContentHeight - 1)
-	} else if m.showPipelines {
-		rightPanelTitle = "┤ Build Pipelines ├"
-		if m.selectedRepo != nil && m.selectedRepo.Name != nil {
-			rightPanelTitle = "┤ " + *m.selectedRepo.Name + " Pipelines ├"
+		content.WriteString("\n")
+		linesUsed++
+
+		// Show the commits the PR would bring in
+		if m.loadingPRCommits {
+			content.WriteString("  Commits: loading...\n\n")
+		} else if len(m.prCommits) == 0 {
+			content.WriteString("  Commits: none ahead of the target branch\n\n")
+		} else {
+			content.WriteString(fmt.Sprintf("  Commits: %d   (Ctrl+G: fill title and description from commits)\n\n", len(m.prCommits)))
 		}
-		rightPanelContent = m.renderPipelines(rightContentHeight - 1)
-	} else {
-		// Show logo
-		rightPanelTitle = ""
-		rightPanelLogoText := `   ▄████████  ▄███████▄      ███     ███    █▄   ▄█ 
-  ███    ███ ██▀     ▄██ ▀█████████▄ ███    ███ ███ 
-  ███    ███       ▄███▀    ▀███▀▀██ ███    ███ ███▌
-  ███    ███  ▀█▀▄███▀▄▄     ███   ▀ ███    ███ ███▌
-▀███████████   ▄███▀   ▀     ███     ███    ███ ███▌
-  ███    ███ ▄███▀           ███     ███    ███ ███ 
-  ███    ███ ███▄     ▄█     ███     ███    ███ ███ 
-  ███    █▀   ▀████████▀    ▄████▀   ████████▀  █▀`
+		linesUsed += 2
+
+		reviewerStyle := ""
+		if m.prCreateStep == 4 {
+			reviewerStyle = highlightStyle.Render("→ Reviewers:")
+		} else {
+			reviewerStyle = "  Reviewers:"
+		}
+		content.WriteString(reviewerStyle + " ")
+		if len(m.prReviewers) > 0 {
+			for i, reviewer := range m.prReviewers {
+				if i > 0 {
+					content.WriteString(", ")
+				}
+				if reviewer.DisplayName != nil {
+					content.WriteString(*reviewer.DisplayName)
+				}
+				if reviewer.IsRequired != nil && *reviewer.IsRequired {
+					content.WriteString(" (required)")
+				}
+			}
+		} else {
+			content.WriteString("(None selected)")
+		}
+		content.WriteString("\n")
+		linesUsed++
+
+		// Show reviewer search if step 4
+		if m.prCreateStep == 4 {
+			content.WriteString("  Search: " + m.reviewerSearch + "\n")
+			linesUsed++
+
+			// Show filtered reviewers
+			if len(m.filteredReviewers) > 0 {
+				content.WriteString("  Available reviewers:\n")
+				linesUsed++
+				// Show 5 reviewers at a time, keeping the cursor in view
+				start := 0
+				if m.cursor >= 5 {
+					start = m.cursor - 4
+				}
+				for i := start; i < len(m.filteredReviewers) && i < start+5; i++ {
+					prefix := "    "
+					if i == m.cursor {
+						prefix = "  → "
+					}
+					content.WriteString(prefix + truncateRunes(reviewerName(m.filteredReviewers[i]), m.width-m.width/2-12) + "\n")
+					linesUsed++
+				}
+			}
+		}
+		content.WriteString("\n")
+		linesUsed++
+
+		workItemStyle := ""
+		if m.prCreateStep == 5 {
+			workItemStyle = highlightStyle.Render("→ Work Items:")
+		} else {
+			workItemStyle = "  Work Items:"
+		}
+		content.WriteString(workItemStyle + " ")
+		if len(m.prWorkItems) > 0 {
+			for i, item := range m.prWorkItems {
+				if i > 0 {
+					content.WriteString(", ")
+				}
+				content.WriteString(fmt.Sprintf("#%d", *item.Id))
+			}
+		} else {
+			content.WriteString("(None linked)")
+		}
+		content.WriteString("\n")
+		linesUsed++
 
-		rightPanelLogoStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("12")).
-			Bold(true).
-			Align(lipgloss.Center)
+		// Show work item search if step 5
+		if m.prCreateStep == 5 {
+			linesUsed += m.renderWorkItemSearch(&content)
+		}
+		content.WriteString("\n")
+		linesUsed++
 
-		// Fill the content to match the expected height
-		logoLines := strings.Split(rightPanelLogoText, "\n")
-		var paddedContent strings.Builder
-		paddedContent.WriteString(rightPanelLogoStyle.Render(rightPanelLogoText))
+		draftStyle := ""
+		if m.prCreateStep == 6 {
+			draftStyle = highlightStyle.Render("→ Create as draft:")
+		} else {
+			draftStyle = "  Create as draft:"
+		}
+		if m.prCreateDraft {
+			content.WriteString(draftStyle + " [x]\n\n")
+		} else {
+			content.WriteString(draftStyle + " [ ]\n\n")
+		}
+		linesUsed += 2
 
-		// Add empty lines to fill remaining space
-		usedLines := len(logoLines)
-		availableLines := rightContentHeight - 1 // -1 for title
-		for i := usedLines; i < availableLines; i++ {
-			paddedContent.WriteString("\n")
+		// Show current step instructions
+		var instructions string
+		switch m.prCreateStep {
+		case 0:
+			instructions = "  Type to edit   •   Tab: Next   •   Enter: Submit   •   Esc: Cancel"
+		case 1:
+			instructions = "  Type markdown   •   Enter: New line   •   Ctrl+E: Open $EDITOR   •   Tab: Next   •   Esc: Cancel"
+		case 2, 3:
+			instructions = "  ↑/↓: Select branch   •   Tab: Next   •   Enter: Submit   •   Esc: Cancel"
+		case 4:
+			instructions = "  Type to search   •   Enter: Add optional   •   Ctrl+R: Add/toggle required   •   Backspace on empty: Remove last   •   Tab: Next"
+		case 5:
+			instructions = "  Type ID or title   •   Enter: Search/Add   •   ↑/↓: Navigate   •   Backspace on empty: Remove last   •   Tab: Next"
+		case 6:
+			instructions = "  Space: Toggle draft   •   Tab: Next   •   Enter: Submit   •   Esc: Cancel"
 		}
+		content.WriteString(instructions + "\n")
+		linesUsed++
+	}
 
-		rightPanelContent = paddedContent.String()
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
+		content.WriteString("\n")
+		linesUsed++
 	}
 
-	// Update right panel style based on focus
-	rightStyle := rightPanelStyle.Copy()
-	if m.showPipelines || m.showRuns || m.showRunDetails || m.showPRs || m.showPRCreate || m.showPRDetails {
-		rightStyle = rightStyle.BorderForeground(lipgloss.Color("12"))
-	} else {
-		rightStyle = rightStyle.BorderForeground(lipgloss.Color("240"))
+	return content.String()
+}
+
+func (m model) renderPRDetails(visibleLines int) string {
+	// Show loading animation if PR details are loading
+	if m.loadingPRDetails {
+		return m.renderLoadingAnimation(visibleLines, "Loading PR details", m.prDetailsSpinner)
 	}
 
-	rightPanel := rightStyle.
-		Width(rightContentWidth).
-		Height(rightContentHeight).
-		Render(rightPanelTitle + "\n" + rightPanelContent)
+	var content strings.Builder
+	linesUsed := 0
 
-	// Create search bar at the top (always reserve space)
-	var searchBar string
-	if m.searchMode {
-		searchQuery := m.searchQuery
-		if searchQuery == "" {
-			searchQuery = "Type to search..."
-		}
-		searchBar = searchStyle.
-			Width(m.width - 4).
-			Render("🔍 Search: " + searchQuery)
+	if m.prDetails == nil {
+		content.WriteString("  No PR details available\n")
+		linesUsed++
 	} else {
-		// Empty space to maintain layout consistency - match search bar height
-		// Create a properly sized empty search bar with padding to match the active search bar
-		emptyContent := "\n\n" // Two newlines to match the content height of active search bar
-		searchBar = searchStyle.
-			Width(m.width - 4).
-			Render(emptyContent)
-	}
+		pr := m.prDetails
 
-	// Create left column by stacking projects and repos vertically
-	leftColumn := lipgloss.JoinVertical(lipgloss.Top, projectsBox, reposBox)
+		// Show PR basic info
+		if pr.Title != nil {
+			content.WriteString("  Title: " + *pr.Title + "\n")
+			linesUsed++
+		}
 
-	// Create main layout - no margins between panels
-	content := lipgloss.JoinHorizontal(lipgloss.Top, leftColumn, rightPanel)
+		if pr.CreatedBy != nil && pr.CreatedBy.DisplayName != nil {
+			content.WriteString("  Author: " + *pr.CreatedBy.DisplayName + "\n")
+			linesUsed++
+		}
 
-	// Simple instructions at bottom
-	instructions := m.getInstructions()
-	instructionsStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("240")).
-		Align(lipgloss.Center)
+		if pr.Status != nil {
+			statusColor := lipgloss.Color("240")
+			statusText := string(*pr.Status)
+			switch *pr.Status {
+			case git.PullRequestStatusValues.Active:
+				statusColor = lipgloss.Color("3") // Yellow
+				statusText = "🟡 Active"
+			case git.PullRequestStatusValues.Completed:
+				statusColor = lipgloss.Color("2") // Green
+				statusText = "✅ Completed"
+			case git.PullRequestStatusValues.Abandoned:
+				statusColor = lipgloss.Color("1") // Red
+				statusText = "❌ Abandoned"
+			}
+			if pr.IsDraft != nil && *pr.IsDraft && *pr.Status == git.PullRequestStatusValues.Active {
+				statusText += " (📝 Draft)"
+			}
+			statusStyle := lipgloss.NewStyle().Foreground(statusColor)
+			content.WriteString("  Status: " + statusStyle.Render(statusText) + "\n")
+			linesUsed++
+		}
 
-	return lipgloss.JoinVertical(lipgloss.Top,
-		searchBar,
-		content,
-		instructionsStyle.Render(instructions),
-	)
-}
+		// Show auto-complete state
+		if pr.AutoCompleteSetBy != nil && pr.Status != nil && *pr.Status == git.PullRequestStatusValues.Active {
+			setBy := "someone"
+			if pr.AutoCompleteSetBy.DisplayName != nil {
+				setBy = *pr.AutoCompleteSetBy.DisplayName
+			}
+			autoText := "🔁 Auto-complete set by " + setBy
+			if pr.CompletionOptions != nil && pr.CompletionOptions.MergeStrategy != nil {
+				for _, option := range mergeStrategyOptions {
+					if option.strategy == *pr.CompletionOptions.MergeStrategy {
+						autoText += " (" + option.name + ")"
+						break
+					}
+				}
+			}
+			autoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("6")) // Cyan
+			content.WriteString("  " + autoStyle.Render(autoText) + "\n")
+			linesUsed++
+		}
 
-func (m model) renderProjects(visibleLines int) string {
-	// Show loading animation if projects are loading
-	if m.loadingProjects {
-		return m.renderLoadingAnimation(visibleLines, "Loading projects", m.projectsSpinner)
-	}
+		// Show the bypass reason on PRs completed with an override
+		if pr.CompletionOptions != nil && pr.CompletionOptions.BypassPolicy != nil && *pr.CompletionOptions.BypassPolicy {
+			bypassText := "⚠️  Policies bypassed"
+			if pr.CompletionOptions.BypassReason != nil && *pr.CompletionOptions.BypassReason != "" {
+				bypassText += ": " + *pr.CompletionOptions.BypassReason
+			}
+			bypassStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3")) // Yellow
+			content.WriteString("  " + bypassStyle.Render(bypassText) + "\n")
+			linesUsed++
+		}
 
-	var content strings.Builder
-	linesUsed := 0
+		// Show labels
+		if labels := prs.LabelNames(*pr); len(labels) > 0 {
+			labelStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("5")) // Magenta
+			content.WriteString("  Labels: " + labelStyle.Render(strings.Join(labels, ", ")) + "\n")
+			linesUsed++
+		}
 
-	// Calculate content width for full-width highlighting
-	contentWidth := m.width/2 - 6 // Account for borders, padding, and margin
+		// Show branch info
+		if pr.SourceRefName != nil && pr.TargetRefName != nil {
+			sourceBranch := strings.TrimPrefix(*pr.SourceRefName, "refs/heads/")
+			targetBranch := strings.TrimPrefix(*pr.TargetRefName, "refs/heads/")
+			content.WriteString(fmt.Sprintf("  Branches: %s → %s\n", sourceBranch, targetBranch))
+			linesUsed++
+		}
 
-	if m.searchMode && m.focusedPanel == 0 {
-		// Show filtered results
-		for i, item := range m.filteredItems {
-			if linesUsed >= visibleLines {
-				break
-			}
+		content.WriteString("\n")
+		linesUsed++
 
-			if project, ok := item.(core.TeamProjectReference); ok {
-				projectName := ""
-				if project.Name != nil {
-					projectName = *project.Name
+		// Show description if available
+		if pr.Description != nil && *pr.Description != "" {
+			content.WriteString("  Description:\n")
+			linesUsed++
+			linesUsed += renderMarkdown(&content, *pr.Description, m.width-m.width/2-10, maxDescriptionLines)
+			content.WriteString("\n")
+			linesUsed++
+		}
+
+		// Show reviewers
+		if pr.Reviewers != nil && len(*pr.Reviewers) > 0 {
+			content.WriteString("  Reviewers:\n")
+			linesUsed++
+			for _, reviewer := range *pr.Reviewers {
+				reviewerName := "Unknown"
+				if reviewer.DisplayName != nil {
+					reviewerName = *reviewer.DisplayName
 				}
 
-				// Truncate if too long
-				maxLen := contentWidth - 4
-				if maxLen < 1 {
-					maxLen = 1
+				voteText := "⏳ Pending"
+				voteColor := lipgloss.Color("240")
+				if reviewer.Vote != nil {
+					switch *reviewer.Vote {
+					case 10: // Approved
+						voteText = "✅ Approved"
+						voteColor = lipgloss.Color("2")
+					case -10: // Rejected
+						voteText = "❌ Rejected"
+						voteColor = lipgloss.Color("1")
+					case -5: // Waiting for author
+						voteText = "⏸ Waiting"
+						voteColor = lipgloss.Color("3")
+					case 5: // Approved with suggestions
+						voteText = "✅ Approved*"
+						voteColor = lipgloss.Color("2")
+					}
 				}
-				if len(projectName) > maxLen {
-					projectName = projectName[:maxLen-3] + "..."
+
+				if m.currentUser != nil && m.currentUser.Id != nil && reviewer.Id != nil && strings.EqualFold(*reviewer.Id, *m.currentUser.Id) {
+					reviewerName += " (you)"
+				}
+				if reviewer.IsRequired != nil && *reviewer.IsRequired {
+					reviewerName += " [required]"
 				}
 
-				line := fmt.Sprintf("  %s", projectName)
+				voteStyle := lipgloss.NewStyle().Foreground(voteColor)
+				content.WriteString(fmt.Sprintf("    %s: %s\n", reviewerName, voteStyle.Render(voteText)))
+				linesUsed++
+			}
+			content.WriteString("\n")
+			linesUsed++
+		}
 
-				if m.cursor == i {
-					// Create full-width highlight
-					paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
-					line = fullWidthHighlightStyle.Render(paddedLine)
-				}
+		// Show linked work items
+		if len(m.prLinkedWorkItems) > 0 {
+			content.WriteString("  Work Items:\n")
+			linesUsed++
+			for _, item := range m.prLinkedWorkItems {
+				content.WriteString("    " + formatWorkItem(item) + "\n")
+				linesUsed++
+			}
+			content.WriteString("\n")
+			linesUsed++
+		}
 
-				content.WriteString(line + "\n")
+		// Show policy evaluations, skipping policies that do not apply
+		var applicablePolicies []policy.PolicyEvaluationRecord
+		for _, evaluation := range m.prPolicies {
+			if evaluation.Status != nil && *evaluation.Status == policy.PolicyEvaluationStatusValues.NotApplicable {
+				continue
+			}
+			applicablePolicies = append(applicablePolicies, evaluation)
+		}
+		if len(applicablePolicies) > 0 {
+			content.WriteString("  Policies:\n")
+			linesUsed++
+			for _, evaluation := range applicablePolicies {
+				statusText, statusColor := policyStatusLabel(evaluation)
+				name := policies.EvaluationName(evaluation)
+				if !policies.IsBlocking(evaluation) {
+					name += " (optional)"
+				}
+				statusStyle := lipgloss.NewStyle().Foreground(statusColor)
+				content.WriteString(fmt.Sprintf("    %s: %s\n", name, statusStyle.Render(statusText)))
 				linesUsed++
 			}
+			content.WriteString("\n")
+			linesUsed++
+		}
+
+		// Show comment thread summary
+		threads := m.visibleThreads()
+		if len(threads) > 0 {
+			activeThreads := 0
+			for _, thread := range threads {
+				if thread.Status != nil && (*thread.Status == git.CommentThreadStatusValues.Active || *thread.Status == git.CommentThreadStatusValues.Pending) {
+					activeThreads++
+				}
+			}
+			content.WriteString(fmt.Sprintf("  Comments: %d threads, %d active\n\n", len(threads), activeThreads))
+			linesUsed += 2
 		}
-	} else {
-		// Normal mode - show all projects
-		start := m.projectsScroll
-		end := start + visibleLines
-		if end > len(m.projects) {
-			end = len(m.projects)
+
+		// Show recent action message if available
+		if m.prActionMessage != "" && time.Since(m.prActionTime) < 10*time.Second {
+			messageColor := lipgloss.Color("2") // Green for success
+			if strings.Contains(m.prActionMessage, "Failed") {
+				messageColor = lipgloss.Color("1") // Red for error
+			}
+			messageStyle := lipgloss.NewStyle().Foreground(messageColor)
+			content.WriteString("  " + messageStyle.Render(m.prActionMessage) + "\n\n")
+			linesUsed += 2
 		}
 
-		for i := start; i < end; i++ {
-			projectName := ""
-			if m.projects[i].Name != nil {
-				projectName = *m.projects[i].Name
+		// Show vote picker or action buttons if PR is active
+		if m.prVoteMode {
+			content.WriteString("  Cast your vote:\n")
+			linesUsed++
+			for i, option := range voteOptions {
+				prefix := "    "
+				if i == m.prVoteCursor {
+					prefix = "  → "
+				}
+				optionStyle := lipgloss.NewStyle().Foreground(option.color)
+				content.WriteString(prefix + optionStyle.Render(option.name) + "\n")
+				linesUsed++
+			}
+		} else if pr.Status != nil && *pr.Status == git.PullRequestStatusValues.Active {
+			content.WriteString("  Actions:\n")
+			content.WriteString("  a: Approve   •   d: Decline   •   v: Vote...   •   c: Complete   •   o: Override & Complete\n")
+			linesUsed += 2
+			if pr.AutoCompleteSetBy != nil {
+				content.WriteString("  u: Cancel auto-complete")
+			} else {
+				content.WriteString("  u: Set auto-complete")
 			}
-
-			// Truncate if too long
-			maxLen := contentWidth - 4
-			if maxLen < 1 {
-				maxLen = 1
+			if m.hasFailedBuild() {
+				content.WriteString("   •   b: Requeue failed builds")
 			}
-			if len(projectName) > maxLen {
-				projectName = projectName[:maxLen-3] + "..."
+			content.WriteString("\n")
+			if pr.IsDraft != nil && *pr.IsDraft {
+				content.WriteString("  p: Publish   •   x: Abandon   •   w: Link work item   •   e: Edit reviewers   •   L: Labels\n")
+			} else {
+				content.WriteString("  p: Mark as draft   •   x: Abandon   •   w: Link work item   •   e: Edit reviewers   •   L: Labels\n")
 			}
-
-			line := fmt.Sprintf("  %s", projectName)
-
-			if m.focusedPanel == 0 && m.cursor == i && !m.showRepoOptions && !m.searchMode {
-				// Create full-width highlight
-				paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
-				line = fullWidthHighlightStyle.Render(paddedLine)
+			linesUsed += 2
+			if m.prAbandonConfirm {
+				warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")) // Red
+				content.WriteString("  " + warningStyle.Render("Press x again to abandon this PR, Esc to cancel") + "\n")
+				linesUsed++
 			}
-
-			content.WriteString(line + "\n")
-			linesUsed++
+		} else if pr.Status != nil && *pr.Status == git.PullRequestStatusValues.Abandoned {
+			content.WriteString("  Actions:\n")
+			content.WriteString("  r: Reactivate\n")
+			linesUsed += 2
+		} else if pr.Status != nil && *pr.Status == git.PullRequestStatusValues.Completed {
+			content.WriteString("  Actions:\n")
+			content.WriteString("  p: Cherry-pick   •   r: Revert\n")
+			linesUsed += 2
 		}
+		content.WriteString("  f: View changed files   •   t: View comment threads\n")
+		linesUsed++
+
+		content.WriteString("\n  Press Esc to go back\n")
+		linesUsed += 2
 	}
 
 	// Fill remaining space with empty lines to maintain fixed height
@@ -1883,100 +7020,165 @@ func (m model) renderProjects(visibleLines int) string {
 	return content.String()
 }
 
-func (m model) renderRepos(visibleLines int) string {
-	// Show loading animation if repos are loading
-	if m.loadingRepos {
-		return m.renderLoadingAnimation(visibleLines, "Loading repositories", m.reposSpinner)
-	}
-
+func (m model) renderPRReviewers(visibleLines int) string {
 	var content strings.Builder
 	linesUsed := 0
 
-	// Calculate content width for full-width highlighting
-	contentWidth := m.width/2 - 6 // Account for borders, padding, and margin
-
-	if m.showRepoOptions {
-		// Show repository options
-		if m.selectedRepo != nil && m.selectedRepo.Name != nil {
-			content.WriteString("Selected: " + *m.selectedRepo.Name + "\n\n")
-			linesUsed += 2
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6
+
+	// Current reviewers are selectable while not searching
+	reviewers := m.prDetailReviewers()
+	content.WriteString("  Current reviewers:\n")
+	linesUsed++
+	if len(reviewers) == 0 {
+		content.WriteString("    (None)\n")
+		linesUsed++
+	}
+	for i, reviewer := range reviewers {
+		prefix := "    "
+		if m.reviewerSearch == "" && i == m.cursor {
+			prefix = "  → "
 		}
+		name := "Unknown"
+		if reviewer.DisplayName != nil {
+			name = *reviewer.DisplayName
+		}
+		if reviewer.IsContainer != nil && *reviewer.IsContainer {
+			name += " [group]"
+		}
+		if reviewer.IsRequired != nil && *reviewer.IsRequired {
+			name += " [required]"
+		}
+		vote := 0
+		if reviewer.Vote != nil {
+			vote = *reviewer.Vote
+		}
+		content.WriteString(prefix + truncateRunes(name, contentWidth-30) + " - " + voteLabel(vote) + "\n")
+		linesUsed++
+	}
+	content.WriteString("\n")
+	linesUsed++
 
-		for i, option := range m.repoOptions {
-			line := fmt.Sprintf("  %s", option.name)
-
-			if m.cursor == i {
-				// Create full-width highlight
-				paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
-				line = fullWidthHighlightStyle.Render(paddedLine)
-			}
-
-			content.WriteString(line + "\n")
+	content.WriteString("  Add reviewer: " + m.reviewerSearch + "\n")
+	linesUsed++
+	if m.loadingUsers {
+		content.WriteString("    Loading users and groups...\n")
+		linesUsed++
+	} else if m.reviewerSearch != "" {
+		if len(m.filteredReviewers) == 0 {
+			content.WriteString("    No matching users or groups\n")
 			linesUsed++
 		}
-	} else if m.searchMode && m.focusedPanel == 1 {
-		// Show filtered results
-		for i, item := range m.filteredItems {
-			if linesUsed >= visibleLines {
-				break
+		// Show 5 results at a time, keeping the cursor in view
+		start := 0
+		if m.cursor >= 5 {
+			start = m.cursor - 4
+		}
+		for i := start; i < len(m.filteredReviewers) && i < start+5; i++ {
+			prefix := "    "
+			if i == m.cursor {
+				prefix = "  → "
 			}
+			content.WriteString(prefix + truncateRunes(reviewerName(m.filteredReviewers[i]), contentWidth-6) + "\n")
+			linesUsed++
+		}
+	}
+	content.WriteString("\n")
+	linesUsed++
 
-			if repo, ok := item.(git.GitRepository); ok {
-				repoName := ""
-				if repo.Name != nil {
-					repoName = *repo.Name
-				}
+	// Show recent action message if available
+	if m.prReviewerRemoveConfirm != "" {
+		warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")) // Red
+		content.WriteString("  " + warningStyle.Render("This reviewer is required. Press Ctrl+D again to remove them") + "\n\n")
+		linesUsed += 2
+	} else if m.prActionMessage != "" && time.Since(m.prActionTime) < 10*time.Second {
+		messageColor := lipgloss.Color("2") // Green for success
+		if strings.Contains(m.prActionMessage, "Failed") {
+			messageColor = lipgloss.Color("1") // Red for error
+		}
+		messageStyle := lipgloss.NewStyle().Foreground(messageColor)
+		content.WriteString("  " + messageStyle.Render(truncateRunes(m.prActionMessage, contentWidth)) + "\n\n")
+		linesUsed += 2
+	}
 
-				// Truncate if too long
-				maxLen := contentWidth - 4
-				if maxLen < 1 {
-					maxLen = 1
-				}
-				if len(repoName) > maxLen {
-					repoName = repoName[:maxLen-3] + "..."
-				}
+	if m.reviewerSearch == "" {
+		content.WriteString("  Type to find a user or group to add\n")
+		content.WriteString("  Ctrl+R toggles required, Ctrl+D removes the highlighted reviewer\n")
+	} else {
+		content.WriteString("  Enter adds as optional, Ctrl+R adds as required\n")
+		content.WriteString("  Clear the search to edit current reviewers\n")
+	}
+	linesUsed += 2
 
-				line := fmt.Sprintf("  %s", repoName)
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
+		content.WriteString("\n")
+		linesUsed++
+	}
 
-				if m.cursor == i {
-					// Create full-width highlight
-					paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
-					line = fullWidthHighlightStyle.Render(paddedLine)
-				}
+	return content.String()
+}
 
-				content.WriteString(line + "\n")
-				linesUsed++
-			}
-		}
-	} else {
-		// Show repositories list
-		start := m.reposScroll
-		end := start + visibleLines
-		if end > len(m.repos) {
-			end = len(m.repos)
-		}
+func (m model) renderPRCherryPick(visibleLines int) string {
+	var content strings.Builder
+	linesUsed := 0
 
-		for i := start; i < end; i++ {
-			repoName := *m.repos[i].Name
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6
 
-			// Truncate if too long
-			maxLen := contentWidth - 4
-			if maxLen < 1 {
-				maxLen = 1
-			}
-			if len(repoName) > maxLen {
-				repoName = repoName[:maxLen-3] + "..."
-			}
+	if m.prDetails != nil && m.prDetails.PullRequestId != nil && m.prDetails.Title != nil {
+		content.WriteString("  " + truncateRunes(fmt.Sprintf("!%d %s", *m.prDetails.PullRequestId, *m.prDetails.Title), contentWidth) + "\n\n")
+		linesUsed += 2
+	}
 
-			line := fmt.Sprintf("  %s", repoName)
+	// Once started, show the operation's progress instead of the branch picker
+	if m.prCherryPickRunning || m.prCherryPickID != 0 || m.prCherryPickError != "" {
+		content.WriteString("  Onto:   " + strings.TrimPrefix(m.prCherryPickOnto, "refs/heads/") + "\n")
+		content.WriteString("  Branch: " + strings.TrimPrefix(m.prCherryPickRef, "refs/heads/") + "\n\n")
+		linesUsed += 3
 
-			if m.focusedPanel == 1 && m.cursor == i && !m.showRepoOptions && !m.searchMode {
-				// Create full-width highlight
-				paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
-				line = fullWidthHighlightStyle.Render(paddedLine)
-			}
+		switch {
+		case m.prCherryPickError != "":
+			errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")) // Red
+			content.WriteString("  " + errorStyle.Render(truncateRunes("Failed: "+m.prCherryPickError, contentWidth)) + "\n\n")
+			content.WriteString("  Press Esc and pick again to retry\n")
+			linesUsed += 3
+		case m.prCherryPickStatus == git.GitAsyncOperationStatusValues.Completed:
+			successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("2")) // Green
+			content.WriteString("  " + successStyle.Render("Completed") + "\n\n")
+			content.WriteString("  Press Enter to create a pull request into " + strings.TrimPrefix(m.prCherryPickOnto, "refs/heads/") + "\n")
+			linesUsed += 3
+		default:
+			progressStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3")) // Yellow
+			content.WriteString("  " + progressStyle.Render(fmt.Sprintf("Status: %s...", m.prCherryPickStatus)) + "\n")
+			linesUsed++
+		}
+	} else {
+		content.WriteString(fmt.Sprintf("  %s onto: %s\n\n", m.cherryPickKind(), m.prCherryPickFilter))
+		linesUsed += 2
 
-			content.WriteString(line + "\n")
+		branches := m.cherryPickBranches()
+		if m.loadingBranches {
+			content.WriteString("  Loading branches...\n")
+			linesUsed++
+		} else if len(branches) == 0 {
+			content.WriteString("  No matching branches\n")
+			linesUsed++
+		}
+
+		// Keep the cursor within the lines left for the list
+		available := visibleLines - linesUsed
+		start := 0
+		if m.cursor >= available {
+			start = m.cursor - available + 1
+		}
+		for i := start; i < len(branches) && linesUsed < visibleLines; i++ {
+			prefix := "    "
+			if i == m.cursor {
+				prefix = "  → "
+			}
+			content.WriteString(prefix + truncateRunes(strings.TrimPrefix(*branches[i].Name, "refs/heads/"), contentWidth-4) + "\n")
 			linesUsed++
 		}
 	}
@@ -1990,61 +7192,85 @@ func (m model) renderRepos(visibleLines int) string {
 	return content.String()
 }
 
-func (m model) renderPipelines(visibleLines int) string {
-	// Show loading animation if pipelines are loading
-	if m.loadingPipelines {
-		return m.renderLoadingAnimation(visibleLines, "Loading pipelines", m.pipelinesSpinner)
-	}
-
+func (m model) renderPRLabels(visibleLines int) string {
 	var content strings.Builder
 	linesUsed := 0
 
-	// Calculate content width for full-width highlighting
 	rightWidth := m.width - m.width/2
-	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
+	contentWidth := rightWidth - 6
+
+	// Current labels are selectable while nothing is typed
+	labels := m.prDetailLabels()
+	content.WriteString("  Labels:\n")
+	linesUsed++
+	if len(labels) == 0 {
+		content.WriteString("    (None)\n")
+		linesUsed++
+	}
+	for i, name := range labels {
+		prefix := "    "
+		if m.prLabelInput == "" && i == m.cursor {
+			prefix = "  → "
+		}
+		content.WriteString(prefix + truncateRunes(name, contentWidth-6) + "\n")
+		linesUsed++
+	}
+	content.WriteString("\n")
+	linesUsed++
 
-	// Show auto-detection success message if applicable
-	if m.autoDetectResult != nil && m.autoDetectResult.ShouldAutoLoad && m.selectedProject != nil && m.selectedRepo != nil {
-		successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("2")) // Green
-		content.WriteString(successStyle.Render("  🚀 Auto-detected current repository"))
-		content.WriteString("\n\n")
+	content.WriteString("  New label: " + m.prLabelInput + "\n\n")
+	linesUsed += 2
+
+	// Show recent action message if available
+	if m.prActionMessage != "" && time.Since(m.prActionTime) < 10*time.Second {
+		messageColor := lipgloss.Color("2") // Green for success
+		if strings.Contains(m.prActionMessage, "Failed") {
+			messageColor = lipgloss.Color("1") // Red for error
+		}
+		messageStyle := lipgloss.NewStyle().Foreground(messageColor)
+		content.WriteString("  " + messageStyle.Render(truncateRunes(m.prActionMessage, contentWidth)) + "\n\n")
 		linesUsed += 2
 	}
 
-	// Show pipelines list
-	start := m.pipelinesScroll
-	end := start + visibleLines
-	if end > len(m.pipelines) {
-		end = len(m.pipelines)
+	content.WriteString("  Type a label and press Enter to add it\n")
+	content.WriteString("  Ctrl+D removes the highlighted label\n")
+	linesUsed += 2
+
+	// Fill remaining space with empty lines to maintain fixed height
+	for linesUsed < visibleLines {
+		content.WriteString("\n")
+		linesUsed++
 	}
 
-	for i := start; i < end; i++ {
-		pipelineName := ""
-		if m.pipelines[i].Name != nil {
-			pipelineName = *m.pipelines[i].Name
-		}
+	return content.String()
+}
 
-		// Truncate if too long
-		maxLen := contentWidth - 4
-		if maxLen < 1 {
-			maxLen = 1
-		}
-		if len(pipelineName) > maxLen {
-			pipelineName = pipelineName[:maxLen-3] + "..."
-		}
+func (m model) renderPRLinkWorkItem(visibleLines int) string {
+	var content strings.Builder
+	linesUsed := 0
 
-		line := fmt.Sprintf("  %s", pipelineName)
+	content.WriteString("  Link a work item to this pull request\n\n")
+	linesUsed += 2
 
-		if m.cursor == i {
-			// Create full-width highlight
-			paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
-			line = fullWidthHighlightStyle.Render(paddedLine)
-		}
+	linesUsed += m.renderWorkItemSearch(&content)
+	content.WriteString("\n")
+	linesUsed++
 
-		content.WriteString(line + "\n")
-		linesUsed++
+	// Show recent action message if available
+	if m.prActionMessage != "" && time.Since(m.prActionTime) < 10*time.Second {
+		messageColor := lipgloss.Color("2") // Green for success
+		if strings.Contains(m.prActionMessage, "Failed") {
+			messageColor = lipgloss.Color("1") // Red for error
+		}
+		messageStyle := lipgloss.NewStyle().Foreground(messageColor)
+		content.WriteString("  " + messageStyle.Render(m.prActionMessage) + "\n\n")
+		linesUsed += 2
 	}
 
+	content.WriteString("  Type an ID or part of a title and press Enter to search\n")
+	content.WriteString("  Press Enter on a result to link it, Esc to cancel\n")
+	linesUsed += 2
+
 	// Fill remaining space with empty lines to maintain fixed height
 	for linesUsed < visibleLines {
 		content.WriteString("\n")
@@ -2054,60 +7280,53 @@ func (m model) renderPipelines(visibleLines int) string {
 	return content.String()
 }
 
-func (m model) renderRuns(visibleLines int) string {
-	// Show loading animation if runs are loading
-	if m.loadingRuns {
-		return m.renderLoadingAnimation(visibleLines, "Loading runs", m.runsSpinner)
-	}
-
+func (m model) renderPROverride(visibleLines int) string {
 	var content strings.Builder
 	linesUsed := 0
 
-	// Calculate content width for full-width highlighting
-	rightWidth := m.width - m.width/2
-	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
-
-	// Show runs list
-	start := m.runsScroll
-	end := start + visibleLines
-	if end > len(m.runs) {
-		end = len(m.runs)
-	}
+	content.WriteString("  Override PR Completion\n\n")
+	linesUsed += 2
 
-	for i := start; i < end; i++ {
-		runDisplay := ""
-		if m.runs[i].Name != nil {
-			runDisplay = *m.runs[i].Name
-		} else if m.runs[i].Id != nil {
-			runDisplay = fmt.Sprintf("Run #%d", *m.runs[i].Id)
-		}
+	content.WriteString("  This action will complete the PR even if required\n")
+	content.WriteString("  policies are not met. The reason is recorded on the\n")
+	content.WriteString("  PR and posted as a comment.\n\n")
+	linesUsed += 4
 
-		// Add status if available
-		if m.runs[i].State != nil {
-			runDisplay = fmt.Sprintf("%s (%s)", runDisplay, string(*m.runs[i].State))
-		}
+	content.WriteString("  Override Reason:\n")
+	content.WriteString("  " + m.prOverrideInput.View() + "\n\n")
+	linesUsed += 3
 
-		// Truncate if too long
-		maxLen := contentWidth - 4
-		if maxLen < 1 {
-			maxLen = 1
-		}
-		if len(runDisplay) > maxLen {
-			runDisplay = runDisplay[:maxLen-3] + "..."
+	// Show pending policies that the override will bypass
+	if pending := m.pendingPolicies(); len(pending) > 0 {
+		content.WriteString("  Policies that will be bypassed:\n")
+		linesUsed++
+		for _, evaluation := range pending {
+			statusText, _ := policyStatusLabel(evaluation)
+			content.WriteString(fmt.Sprintf("    %s: %s\n", policies.EvaluationName(evaluation), statusText))
+			linesUsed++
 		}
+		content.WriteString("\n")
+		linesUsed++
+	}
 
-		line := fmt.Sprintf("  %s", runDisplay)
+	content.WriteString("  ⚠️  WARNING: This bypasses branch policies!\n\n")
+	linesUsed += 2
 
-		if m.cursor == i {
-			// Create full-width highlight
-			paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
-			line = fullWidthHighlightStyle.Render(paddedLine)
+	// Show recent action message if available, permission errors surface here
+	if m.prActionMessage != "" && time.Since(m.prActionTime) < 10*time.Second {
+		messageColor := lipgloss.Color("2") // Green for success
+		if strings.Contains(m.prActionMessage, "Failed") {
+			messageColor = lipgloss.Color("1") // Red for error
 		}
-
-		content.WriteString(line + "\n")
-		linesUsed++
+		messageStyle := lipgloss.NewStyle().Foreground(messageColor)
+		content.WriteString("  " + messageStyle.Render(m.prActionMessage) + "\n\n")
+		linesUsed += 2
 	}
 
+	content.WriteString("  Press Enter to choose the merge options\n")
+	content.WriteString("  Press Esc to cancel\n")
+	linesUsed += 2
+
 	// Fill remaining space with empty lines to maintain fixed height
 	for linesUsed < visibleLines {
 		content.WriteString("\n")
@@ -2117,102 +7336,81 @@ func (m model) renderRuns(visibleLines int) string {
 	return content.String()
 }
 
-func (m model) renderRunDetails(visibleLines int) string {
-	// Show loading animation if timeline is loading
-	if m.loadingTimeline {
-		return m.renderLoadingAnimation(visibleLines, "Loading timeline", m.timelineSpinner)
+func (m model) renderPRIterations(visibleLines int) string {
+	if m.loadingPRIterations {
+		return m.renderLoadingAnimation(visibleLines, "Loading iterations", m.prDetailsSpinner)
 	}
 
 	var content strings.Builder
 	linesUsed := 0
 
-	// Calculate content width for full-width highlighting
 	rightWidth := m.width - m.width/2
-	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
+	contentWidth := rightWidth - 6
 
-	if m.timeline == nil || m.timeline.Records == nil {
-		content.WriteString("  No timeline data available\n")
+	if len(m.prIterations) == 0 {
+		content.WriteString("  No iterations found\n")
 		linesUsed++
 	} else {
-		// Show run status and auto-selection info
-		if m.autoSelected {
-			content.WriteString("  🚀 Auto-selected in-progress run\n")
-			linesUsed++
-		}
-
-		// Show refresh info
-		if m.autoRefresh {
-			refreshTime := "Never"
-			if !m.lastRefresh.IsZero() {
-				refreshTime = m.lastRefresh.Format("15:04:05")
-			}
-			content.WriteString(fmt.Sprintf("  🔄 Auto-refresh enabled (Last: %s)\n", refreshTime))
-			linesUsed++
+		if m.prIterationBase != 0 {
+			content.WriteString(fmt.Sprintf("  Comparing against iteration %d\n\n", m.prIterationBase))
+		} else {
+			content.WriteString("  Enter shows an iteration's changes since the one before it\n\n")
 		}
-		content.WriteString("  Press 'r' to refresh manually, Esc to go back\n\n")
 		linesUsed += 2
 
-		// Show timeline records (steps)
-		records := *m.timeline.Records
-		start := m.timelineScroll
-		end := start + visibleLines - linesUsed
-		if end > len(records) {
-			end = len(records)
+		// Keep the cursor in view
+		listLines := visibleLines - linesUsed
+		start := 0
+		if m.cursor >= listLines {
+			start = m.cursor - listLines + 1
 		}
 
-		for i := start; i < end; i++ {
-			record := records[i]
+		dateStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+		baseStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3")) // Yellow
+		for i := start; i < len(m.prIterations) && linesUsed < visibleLines; i++ {
+			iteration := m.prIterations[i]
 
-			// Format step display
-			stepName := "Unknown Step"
-			if record.Name != nil {
-				stepName = *record.Name
+			id := 0
+			if iteration.Id != nil {
+				id = *iteration.Id
 			}
-
-			status := "Unknown"
-			statusColor := lipgloss.Color("240") // Gray
-			if record.State != nil {
-				status = string(*record.State)
-				switch *record.State {
-				case "completed":
-					if record.Result != nil && *record.Result == "succeeded" {
-						statusColor = lipgloss.Color("2") // Green
-						status = "✓ Succeeded"
-					} else {
-						statusColor = lipgloss.Color("1") // Red
-						status = "✗ Failed"
-					}
-				case "inProgress":
-					statusColor = lipgloss.Color("3") // Yellow
-					status = "⏳ Running"
-				case "pending":
-					statusColor = lipgloss.Color("240") // Gray
-					status = "⏸ Pending"
-				}
+			date := ""
+			if iteration.CreatedDate != nil {
+				date = iteration.CreatedDate.Time.Local().Format("2006-01-02 15:04")
 			}
-
-			// Truncate step name if too long
-			maxStepLen := contentWidth - 20 // Leave space for status
-			if maxStepLen < 10 {
-				maxStepLen = 10
+			author := "Unknown"
+			if iteration.Author != nil && iteration.Author.DisplayName != nil {
+				author = *iteration.Author.DisplayName
 			}
-			if len(stepName) > maxStepLen {
-				stepName = stepName[:maxStepLen-3] + "..."
+			commits := 0
+			if iteration.Commits != nil {
+				commits = len(*iteration.Commits)
+			}
+			commitText := fmt.Sprintf("%d commits", commits)
+			if commits == 1 {
+				commitText = "1 commit"
+			}
+			if iteration.HasMoreCommits != nil && *iteration.HasMoreCommits {
+				commitText = fmt.Sprintf("%d+ commits", commits)
+			}
+			reason := ""
+			if iteration.Reason != nil {
+				reason = " (" + string(*iteration.Reason) + ")"
 			}
 
-			line := fmt.Sprintf("  %-*s %s", maxStepLen, stepName, status)
-
-			// Apply color to status
-			statusStyle := lipgloss.NewStyle().Foreground(statusColor)
-			coloredLine := fmt.Sprintf("  %-*s %s", maxStepLen, stepName, statusStyle.Render(status))
-
-			if m.cursor == i {
+			marker := "  "
+			if id != 0 && id == m.prIterationBase {
+				marker = baseStyle.Render("◆ ")
+			}
+			line := truncateRunes(fmt.Sprintf("%-4s %s  %s, %s%s", fmt.Sprintf("#%d", id), date, author, commitText, reason), contentWidth-2)
+			if i == m.cursor {
 				// Create full-width highlight
-				paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
-				coloredLine = fullWidthHighlightStyle.Render(paddedLine)
+				paddedLine := fmt.Sprintf("%-*s", contentWidth-2, line)
+				line = fullWidthHighlightStyle.Render(paddedLine)
+			} else if date != "" {
+				line = strings.Replace(line, date, dateStyle.Render(date), 1)
 			}
-
-			content.WriteString(coloredLine + "\n")
+			content.WriteString(marker + line + "\n")
 			linesUsed++
 		}
 	}
@@ -2226,10 +7424,10 @@ func (m model) renderRunDetails(visibleLines int) string {
 	return content.String()
 }
 
-func (m model) renderPRs(visibleLines int) string {
-	// Show loading animation if PRs are loading
-	if m.loadingPRs {
-		return m.renderLoadingAnimation(visibleLines, "Loading pull requests", m.prsSpinner)
+func (m model) renderPRFiles(visibleLines int) string {
+	// Show loading animation if the file list is loading
+	if m.loadingPRFiles {
+		return m.renderLoadingAnimation(visibleLines, "Loading changed files", m.prDetailsSpinner)
 	}
 
 	var content strings.Builder
@@ -2239,48 +7437,50 @@ func (m model) renderPRs(visibleLines int) string {
 	rightWidth := m.width - m.width/2
 	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
 
-	// Show PRs list
-	start := m.prsScroll
-	end := start + visibleLines
-	if end > len(m.prs) {
-		end = len(m.prs)
-	}
-
-	for i := start; i < end; i++ {
-		pr := m.prs[i]
-		prDisplay := ""
+	if len(m.prFiles) == 0 {
+		content.WriteString("  No changed files found\n")
+		linesUsed++
+	} else {
+		content.WriteString(fmt.Sprintf("  %d files changed in %s\n\n", len(m.prFiles), m.prFilesRangeLabel()))
+		linesUsed += 2
 
-		if pr.Title != nil {
-			prDisplay = *pr.Title
-		} else if pr.PullRequestId != nil {
-			prDisplay = fmt.Sprintf("PR #%d", *pr.PullRequestId)
+		start := m.prFilesScroll
+		end := start + visibleLines - linesUsed
+		if end > len(m.prFiles) {
+			end = len(m.prFiles)
 		}
 
-		// Add status if available
-		if pr.Status != nil {
-			statusText := string(*pr.Status)
-			prDisplay = fmt.Sprintf("%s (%s)", prDisplay, statusText)
-		}
+		for i := start; i < end; i++ {
+			file := m.prFiles[i]
+			label, labelColor := changeTypeLabel(file.ChangeType)
 
-		// Truncate if too long
-		maxLen := contentWidth - 4
-		if maxLen < 1 {
-			maxLen = 1
-		}
-		if len(prDisplay) > maxLen {
-			prDisplay = prDisplay[:maxLen-3] + "..."
-		}
+			path := file.Path
+			if file.OriginalPath != "" && file.OriginalPath != file.Path {
+				path = file.OriginalPath + " → " + file.Path
+			}
 
-		line := fmt.Sprintf("  %s", prDisplay)
+			// Truncate if too long
+			maxLen := contentWidth - 6
+			if maxLen < 4 {
+				maxLen = 4
+			}
+			if len(path) > maxLen {
+				path = "..." + path[len(path)-maxLen+3:]
+			}
 
-		if m.cursor == i {
-			// Create full-width highlight
-			paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
-			line = fullWidthHighlightStyle.Render(paddedLine)
-		}
+			line := fmt.Sprintf("  %s %s", label, path)
+			labelStyle := lipgloss.NewStyle().Foreground(labelColor)
+			coloredLine := fmt.Sprintf("  %s %s", labelStyle.Render(label), path)
 
-		content.WriteString(line + "\n")
-		linesUsed++
+			if m.cursor == i {
+				// Create full-width highlight
+				paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+				coloredLine = fullWidthHighlightStyle.Render(paddedLine)
+			}
+
+			content.WriteString(coloredLine + "\n")
+			linesUsed++
+		}
 	}
 
 	// Fill remaining space with empty lines to maintain fixed height
@@ -2292,137 +7492,122 @@ func (m model) renderPRs(visibleLines int) string {
 	return content.String()
 }
 
-func (m model) renderPRCreate(visibleLines int) string {
+func (m model) renderPRDiff(visibleLines int) string {
+	// Show loading animation if the diff is loading
+	if m.loadingPRDiff {
+		return m.renderLoadingAnimation(visibleLines, "Loading diff", m.prDetailsSpinner)
+	}
+
 	var content strings.Builder
 	linesUsed := 0
 
-	if m.prCreateMode {
-		content.WriteString("  Create New Pull Request\n\n")
-		linesUsed += 2
-
-		// Show form fields with highlighting for current step
-		titleStyle := ""
-		if m.prCreateStep == 0 {
-			titleStyle = highlightStyle.Render("→ Title:")
-		} else {
-			titleStyle = "  Title:"
-		}
-		content.WriteString(titleStyle + "\n")
-		content.WriteString("  " + m.prTitleInput.View() + "\n\n")
-		linesUsed += 3
+	// Calculate content width for full-width highlighting
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
 
-		descStyle := ""
-		if m.prCreateStep == 1 {
-			descStyle = highlightStyle.Render("→ Description:")
-		} else {
-			descStyle = "  Description:"
-		}
-		content.WriteString(descStyle + "\n")
-		content.WriteString("  " + m.prDescInput.View() + "\n\n")
-		linesUsed += 3
+	if m.selectedPRFile != nil {
+		label, labelColor := changeTypeLabel(m.selectedPRFile.ChangeType)
+		labelStyle := lipgloss.NewStyle().Foreground(labelColor)
+		content.WriteString(fmt.Sprintf("  %s %s\n\n", labelStyle.Render(label), string(m.selectedPRFile.ChangeType)))
+		linesUsed += 2
+	}
 
-		// Show branch selection with highlighting
-		sourceStyle := ""
-		if m.prCreateStep == 2 {
-			sourceStyle = highlightStyle.Render("→ Source Branch:")
-		} else {
-			sourceStyle = "  Source Branch:"
-		}
-		content.WriteString(sourceStyle + " ")
-		if m.prSourceBranch != nil && m.prSourceBranch.Name != nil {
-			branchName := strings.TrimPrefix(*m.prSourceBranch.Name, "refs/heads/")
-			content.WriteString(branchName)
-		} else {
-			content.WriteString("(Select branch)")
-		}
-		if m.prCreateStep == 2 {
-			content.WriteString(" ↑/↓ to change")
-		}
-		content.WriteString("\n")
+	if m.prDiffError != "" {
+		errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1")) // Red
+		content.WriteString("  " + errorStyle.Render(truncateRunes(m.prDiffError, contentWidth-2)) + "\n")
+		linesUsed++
+	} else if m.prDiffBinary {
+		content.WriteString("  Binary file not shown\n")
 		linesUsed++
+	} else if len(m.prDiffLines) == 0 {
+		content.WriteString("  No textual changes\n")
+		linesUsed++
+	} else {
+		rangeStart, rangeEnd := m.prDiffRange()
 
-		targetStyle := ""
-		if m.prCreateStep == 3 {
-			targetStyle = highlightStyle.Render("→ Target Branch:")
-		} else {
-			targetStyle = "  Target Branch:"
-		}
-		content.WriteString(targetStyle + " ")
-		if m.prTargetBranch != nil && m.prTargetBranch.Name != nil {
-			branchName := strings.TrimPrefix(*m.prTargetBranch.Name, "refs/heads/")
-			content.WriteString(branchName)
-		} else {
-			content.WriteString("main")
+		start := m.prDiffScroll
+		end := start + visibleLines - linesUsed - prDiffFooterLines
+		if end > len(m.prDiffLines) {
+			end = len(m.prDiffLines)
 		}
-		if m.prCreateStep == 3 {
-			content.WriteString(" ↑/↓ to change")
-		}
-		content.WriteString("\n\n")
-		linesUsed += 2
 
-		reviewerStyle := ""
-		if m.prCreateStep == 4 {
-			reviewerStyle = highlightStyle.Render("→ Reviewers:")
-		} else {
-			reviewerStyle = "  Reviewers:"
-		}
-		content.WriteString(reviewerStyle + " ")
-		if len(m.prReviewers) > 0 {
-			for i, reviewer := range m.prReviewers {
-				if i > 0 {
-					content.WriteString(", ")
-				}
-				if reviewer.DisplayName != nil {
-					content.WriteString(*reviewer.DisplayName)
-				}
+		for i := start; i < end; i++ {
+			diffLine := m.prDiffLines[i]
+			hasThread := len(m.diffLineThreads(diffLine)) > 0
+			line, coloredLine := formatDiffLine(diffLine, contentWidth, hasThread)
+
+			if m.cursor == i {
+				// Create full-width highlight
+				paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+				coloredLine = fullWidthHighlightStyle.Render(paddedLine)
+			} else if m.prDiffAnchor >= 0 && i >= rangeStart && i <= rangeEnd {
+				paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+				coloredLine = diffRangeStyle.Render(paddedLine)
 			}
-		} else {
-			content.WriteString("(None selected)")
+
+			content.WriteString(coloredLine + "\n")
+			linesUsed++
 		}
+	}
+
+	// Pad the diff body so the footer stays at the bottom
+	for linesUsed < visibleLines-prDiffFooterLines {
 		content.WriteString("\n")
 		linesUsed++
+	}
 
-		// Show reviewer search if step 4
-		if m.prCreateStep == 4 {
-			content.WriteString("  Search: " + m.reviewerSearch + "\n")
-			linesUsed++
+	content.WriteString("  " + strings.Repeat("─", contentWidth-4) + "\n")
+	linesUsed++
 
-			// Show filtered reviewers
-			if len(m.filteredReviewers) > 0 {
-				content.WriteString("  Available reviewers:\n")
-				linesUsed++
-				for i, user := range m.filteredReviewers {
-					if i >= 5 { // Limit to 5 reviewers
-						break
-					}
-					prefix := "    "
-					if i == m.cursor {
-						prefix = "  → "
-					}
-					displayName := "Unknown User"
-					if user.DisplayName != nil {
-						displayName = *user.DisplayName
-					}
-					content.WriteString(prefix + displayName + "\n")
-					linesUsed++
-				}
-			}
+	if m.prReplyMode {
+		content.WriteString("  Comment on " + m.prDiffRangeLabel() + ":\n")
+		content.WriteString("  " + m.prCommentInput.View() + "\n")
+		linesUsed += 2
+	} else if m.prActionMessage != "" && time.Since(m.prActionTime) < 10*time.Second {
+		messageColor := lipgloss.Color("2") // Green for success
+		if strings.Contains(m.prActionMessage, "Failed") {
+			messageColor = lipgloss.Color("1") // Red for error
 		}
-		content.WriteString("\n")
+		messageStyle := lipgloss.NewStyle().Foreground(messageColor)
+		content.WriteString("  " + messageStyle.Render(m.prActionMessage) + "\n")
 		linesUsed++
-
-		// Show current step instructions
-		var instructions string
-		switch m.prCreateStep {
-		case 0, 1:
-			instructions = "  Type to edit   •   Tab: Next   •   Enter: Submit   •   Esc: Cancel"
-		case 2, 3:
-			instructions = "  ↑/↓: Select branch   •   Tab: Next   •   Enter: Submit   •   Esc: Cancel"
-		case 4:
-			instructions = "  Type to search   •   ↑/↓: Navigate   •   Enter: Add reviewer   •   x: Remove last   •   Tab: Next   •   Esc: Cancel"
+	} else if m.cursor < len(m.prDiffLines) {
+		// Preview threads anchored to the highlighted line
+		footerUsed := 0
+		for _, thread := range m.diffLineThreads(m.prDiffLines[m.cursor]) {
+			if thread.Comments == nil {
+				continue
+			}
+			statusText, statusColor := threadStatusLabel(git.CommentThreadStatusValues.Unknown)
+			if thread.Status != nil {
+				statusText, statusColor = threadStatusLabel(*thread.Status)
+			}
+			for _, comment := range *thread.Comments {
+				if footerUsed >= prDiffFooterLines-1 {
+					break
+				}
+				if comment.CommentType != nil && *comment.CommentType == git.CommentTypeValues.System {
+					continue
+				}
+				author := "Unknown"
+				if comment.Author != nil && comment.Author.DisplayName != nil {
+					author = *comment.Author.DisplayName
+				}
+				text := ""
+				if comment.Content != nil {
+					text = strings.ReplaceAll(*comment.Content, "\n", " ")
+				}
+				statusStyle := lipgloss.NewStyle().Foreground(statusColor)
+				text = truncateRunes(author+": "+text, contentWidth-len(statusText)-7)
+				content.WriteString("  " + diffThreadStyle.Render("●") + " " + statusStyle.Render(statusText) + " " + text + "\n")
+				linesUsed++
+				footerUsed++
+			}
+		}
+		if footerUsed == 0 && m.prDiffAnchor >= 0 {
+			content.WriteString("  Selecting " + m.prDiffRangeLabel() + "   •   c: Comment   •   Esc: Clear\n")
+			linesUsed++
 		}
-		content.WriteString(instructions + "\n")
-		linesUsed++
 	}
 
 	// Fill remaining space with empty lines to maintain fixed height
@@ -2434,106 +7619,196 @@ func (m model) renderPRCreate(visibleLines int) string {
 	return content.String()
 }
 
-func (m model) renderPRDetails(visibleLines int) string {
-	// Show loading animation if PR details are loading
-	if m.loadingPRDetails {
-		return m.renderLoadingAnimation(visibleLines, "Loading PR details", m.prDetailsSpinner)
+// prDiffRange returns the diff line indexes covered by the selection, which is just the cursor without an anchor
+func (m model) prDiffRange() (int, int) {
+	if m.prDiffAnchor < 0 {
+		return m.cursor, m.cursor
 	}
+	if m.prDiffAnchor < m.cursor {
+		return m.prDiffAnchor, m.cursor
+	}
+	return m.cursor, m.prDiffAnchor
+}
 
-	var content strings.Builder
-	linesUsed := 0
-
-	if m.prDetails == nil {
-		content.WriteString("  No PR details available\n")
-		linesUsed++
-	} else {
-		pr := m.prDetails
+// prDiffThreadContext maps the selected diff lines to file positions, preferring the new (right) side
+func (m model) prDiffThreadContext() (*git.CommentThreadContext, bool) {
+	rangeStart, rangeEnd := m.prDiffRange()
+	if rangeStart < 0 || rangeEnd >= len(m.prDiffLines) {
+		return nil, false
+	}
 
-		// Show PR basic info
-		if pr.Title != nil {
-			content.WriteString("  Title: " + *pr.Title + "\n")
-			linesUsed++
+	rightFirst, rightLast, leftFirst, leftLast := 0, 0, 0, 0
+	rightLastLen, leftLastLen := 0, 0
+	for _, line := range m.prDiffLines[rangeStart : rangeEnd+1] {
+		switch line.Kind {
+		case diff.Added, diff.Context:
+			if rightFirst == 0 {
+				rightFirst = line.NewLine
+			}
+			rightLast = line.NewLine
+			rightLastLen = len([]rune(line.Text))
+		case diff.Removed:
+			if leftFirst == 0 {
+				leftFirst = line.OldLine
+			}
+			leftLast = line.OldLine
+			leftLastLen = len([]rune(line.Text))
 		}
+	}
 
-		if pr.CreatedBy != nil && pr.CreatedBy.DisplayName != nil {
-			content.WriteString("  Author: " + *pr.CreatedBy.DisplayName + "\n")
-			linesUsed++
-		}
+	startOffset := 1
+	if rightFirst > 0 {
+		endOffset := rightLastLen + 1
+		return &git.CommentThreadContext{
+			RightFileStart: &git.CommentPosition{Line: &rightFirst, Offset: &startOffset},
+			RightFileEnd:   &git.CommentPosition{Line: &rightLast, Offset: &endOffset},
+		}, true
+	}
+	if leftFirst > 0 {
+		endOffset := leftLastLen + 1
+		return &git.CommentThreadContext{
+			LeftFileStart: &git.CommentPosition{Line: &leftFirst, Offset: &startOffset},
+			LeftFileEnd:   &git.CommentPosition{Line: &leftLast, Offset: &endOffset},
+		}, true
+	}
+	return nil, false
+}
 
-		if pr.Status != nil {
-			statusColor := lipgloss.Color("240")
-			statusText := string(*pr.Status)
-			switch *pr.Status {
-			case git.PullRequestStatusValues.Active:
-				statusColor = lipgloss.Color("3") // Yellow
-				statusText = "🟡 Active"
-			case git.PullRequestStatusValues.Completed:
-				statusColor = lipgloss.Color("2") // Green
-				statusText = "✅ Completed"
-			case git.PullRequestStatusValues.Abandoned:
-				statusColor = lipgloss.Color("1") // Red
-				statusText = "❌ Abandoned"
-			}
-			statusStyle := lipgloss.NewStyle().Foreground(statusColor)
-			content.WriteString("  Status: " + statusStyle.Render(statusText) + "\n")
-			linesUsed++
+func (m model) prDiffRangeLabel() string {
+	threadContext, ok := m.prDiffThreadContext()
+	if !ok {
+		return "selection"
+	}
+	side := "line"
+	startPos, endPos := threadContext.RightFileStart, threadContext.RightFileEnd
+	if startPos == nil {
+		side = "removed line"
+		startPos, endPos = threadContext.LeftFileStart, threadContext.LeftFileEnd
+	}
+	if *startPos.Line == *endPos.Line {
+		return fmt.Sprintf("%s %d", side, *startPos.Line)
+	}
+	return fmt.Sprintf("%ss %d-%d", side, *startPos.Line, *endPos.Line)
+}
+
+// diffLineThreads returns the threads of the open file that end on the given diff line
+func (m model) diffLineThreads(line diff.Line) []git.GitPullRequestCommentThread {
+	if m.selectedPRFile == nil || line.Kind == diff.HunkHeader {
+		return nil
+	}
+
+	var threads []git.GitPullRequestCommentThread
+	for _, thread := range m.visibleThreads() {
+		threadContext := thread.ThreadContext
+		if threadContext == nil || threadContext.FilePath == nil || *threadContext.FilePath != m.selectedPRFile.Path {
+			continue
 		}
 
-		// Show branch info
-		if pr.SourceRefName != nil && pr.TargetRefName != nil {
-			sourceBranch := strings.TrimPrefix(*pr.SourceRefName, "refs/heads/")
-			targetBranch := strings.TrimPrefix(*pr.TargetRefName, "refs/heads/")
-			content.WriteString(fmt.Sprintf("  Branches: %s → %s\n", sourceBranch, targetBranch))
-			linesUsed++
+		if line.Kind != diff.Removed && line.NewLine > 0 && anchoredLine(threadContext.RightFileStart, threadContext.RightFileEnd) == line.NewLine {
+			threads = append(threads, thread)
+		} else if line.Kind != diff.Added && line.OldLine > 0 && anchoredLine(threadContext.LeftFileStart, threadContext.LeftFileEnd) == line.OldLine {
+			threads = append(threads, thread)
 		}
+	}
+	return threads
+}
 
-		content.WriteString("\n")
+// anchoredLine is the line a thread is shown under, the end of its span like the web UI
+func anchoredLine(start, end *git.CommentPosition) int {
+	if end != nil && end.Line != nil {
+		return *end.Line
+	}
+	if start != nil && start.Line != nil {
+		return *start.Line
+	}
+	return 0
+}
+
+func (m model) renderPRThreads(visibleLines int) string {
+	var content strings.Builder
+	linesUsed := 0
+
+	// Calculate content width for full-width highlighting
+	rightWidth := m.width - m.width/2
+	contentWidth := rightWidth - 6 // Account for borders, padding, and margin
+
+	threads := m.visibleThreads()
+	if len(threads) == 0 {
+		content.WriteString("  No comment threads yet\n")
 		linesUsed++
+	} else {
+		activeThreads := 0
+		for _, thread := range threads {
+			if thread.Status != nil && (*thread.Status == git.CommentThreadStatusValues.Active || *thread.Status == git.CommentThreadStatusValues.Pending) {
+				activeThreads++
+			}
+		}
+		content.WriteString(fmt.Sprintf("  %d threads, %d active\n\n", len(threads), activeThreads))
+		linesUsed += 2
 
-		// Show description if available
-		if pr.Description != nil && *pr.Description != "" {
-			content.WriteString("  Description:\n")
-			content.WriteString("  " + *pr.Description + "\n\n")
-			linesUsed += 3
+		// Show thread list
+		start := m.prThreadsScroll
+		end := start + m.prThreadListLines()
+		if end > len(threads) {
+			end = len(threads)
 		}
 
-		// Show reviewers
-		if pr.Reviewers != nil && len(*pr.Reviewers) > 0 {
-			content.WriteString("  Reviewers:\n")
-			linesUsed++
-			for _, reviewer := range *pr.Reviewers {
-				reviewerName := "Unknown"
-				if reviewer.DisplayName != nil {
-					reviewerName = *reviewer.DisplayName
-				}
+		for i := start; i < end; i++ {
+			thread := threads[i]
+			statusText, statusColor := threadStatusLabel(git.CommentThreadStatusValues.Unknown)
+			if thread.Status != nil {
+				statusText, statusColor = threadStatusLabel(*thread.Status)
+			}
 
-				voteText := "⏳ Pending"
-				voteColor := lipgloss.Color("240")
-				if reviewer.Vote != nil {
-					switch *reviewer.Vote {
-					case 10: // Approved
-						voteText = "✅ Approved"
-						voteColor = lipgloss.Color("2")
-					case -10: // Rejected
-						voteText = "❌ Rejected"
-						voteColor = lipgloss.Color("1")
-					case -5: // Waiting for author
-						voteText = "⏸ Waiting"
-						voteColor = lipgloss.Color("3")
-					case 5: // Approved with suggestions
-						voteText = "✅ Approved*"
-						voteColor = lipgloss.Color("2")
+			summary := ""
+			author := "Unknown"
+			replies := 0
+			for _, comment := range *thread.Comments {
+				if comment.CommentType != nil && *comment.CommentType == git.CommentTypeValues.System {
+					continue
+				}
+				if summary == "" && comment.Content != nil {
+					summary = strings.ReplaceAll(*comment.Content, "\n", " ")
+					if comment.Author != nil && comment.Author.DisplayName != nil {
+						author = *comment.Author.DisplayName
 					}
+				} else {
+					replies++
+				}
+			}
+			if thread.ThreadContext != nil && thread.ThreadContext.FilePath != nil {
+				location := *thread.ThreadContext.FilePath
+				if thread.ThreadContext.RightFileStart != nil && thread.ThreadContext.RightFileStart.Line != nil {
+					location = fmt.Sprintf("%s:%d", location, *thread.ThreadContext.RightFileStart.Line)
+				} else if thread.ThreadContext.LeftFileStart != nil && thread.ThreadContext.LeftFileStart.Line != nil {
+					location = fmt.Sprintf("%s:%d", location, *thread.ThreadContext.LeftFileStart.Line)
 				}
+				summary = location + " " + summary
+			}
 
-				voteStyle := lipgloss.NewStyle().Foreground(voteColor)
-				content.WriteString(fmt.Sprintf("    %s: %s\n", reviewerName, voteStyle.Render(voteText)))
-				linesUsed++
+			text := fmt.Sprintf("%s: %s", author, summary)
+			if replies > 0 {
+				text = fmt.Sprintf("%s (%d replies)", text, replies)
 			}
-			content.WriteString("\n")
+			text = truncateRunes(text, contentWidth-len(statusText)-5)
+
+			line := fmt.Sprintf("  %s  %s", statusText, text)
+			statusStyle := lipgloss.NewStyle().Foreground(statusColor)
+			coloredLine := fmt.Sprintf("  %s  %s", statusStyle.Render(statusText), text)
+
+			if m.cursor == i {
+				// Create full-width highlight
+				paddedLine := fmt.Sprintf("%-*s", contentWidth, line)
+				coloredLine = fullWidthHighlightStyle.Render(paddedLine)
+			}
+
+			content.WriteString(coloredLine + "\n")
 			linesUsed++
 		}
 
+		content.WriteString("  " + strings.Repeat("─", contentWidth-4) + "\n")
+		linesUsed++
+
 		// Show recent action message if available
 		if m.prActionMessage != "" && time.Since(m.prActionTime) < 10*time.Second {
 			messageColor := lipgloss.Color("2") // Green for success
@@ -2541,19 +7816,71 @@ func (m model) renderPRDetails(visibleLines int) string {
 				messageColor = lipgloss.Color("1") // Red for error
 			}
 			messageStyle := lipgloss.NewStyle().Foreground(messageColor)
-			content.WriteString("  " + messageStyle.Render(m.prActionMessage) + "\n\n")
-			linesUsed += 2
+			content.WriteString("  " + messageStyle.Render(m.prActionMessage) + "\n")
+			linesUsed++
 		}
 
-		// Show action buttons if PR is active
-		if pr.Status != nil && *pr.Status == git.PullRequestStatusValues.Active {
-			content.WriteString("  Actions:\n")
-			content.WriteString("  a: Approve   •   d: Decline   •   o: Override & Complete\n")
-			linesUsed += 2
+		if m.prThreadStatusMode {
+			// Show status picker
+			content.WriteString("  Set thread status:\n")
+			linesUsed++
+			for i, status := range threadStatusOptions {
+				statusText, statusColor := threadStatusLabel(status)
+				prefix := "    "
+				if i == m.prThreadStatusCursor {
+					prefix = "  → "
+				}
+				statusStyle := lipgloss.NewStyle().Foreground(statusColor)
+				content.WriteString(prefix + statusStyle.Render(statusText) + "\n")
+				linesUsed++
+			}
+		} else if m.cursor < len(threads) {
+			// Show the selected conversation
+			authorStyle := lipgloss.NewStyle().Bold(true)
+			dateStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+			for _, comment := range *threads[m.cursor].Comments {
+				if linesUsed >= visibleLines-3 {
+					break
+				}
+				if comment.CommentType != nil && *comment.CommentType == git.CommentTypeValues.System {
+					continue
+				}
+				if comment.IsDeleted != nil && *comment.IsDeleted {
+					continue
+				}
+
+				author := "Unknown"
+				if comment.Author != nil && comment.Author.DisplayName != nil {
+					author = *comment.Author.DisplayName
+				}
+				published := ""
+				if comment.PublishedDate != nil {
+					published = comment.PublishedDate.Time.Local().Format("2006-01-02 15:04")
+				}
+				content.WriteString("  " + authorStyle.Render(author) + " " + dateStyle.Render(published) + "\n")
+				linesUsed++
+
+				if comment.Content != nil {
+					for _, contentLine := range strings.Split(*comment.Content, "\n") {
+						if linesUsed >= visibleLines-3 {
+							break
+						}
+						content.WriteString("    " + truncateRunes(contentLine, contentWidth-6) + "\n")
+						linesUsed++
+					}
+				}
+			}
 		}
+	}
 
-		content.WriteString("\n  Press Esc to go back\n")
-		linesUsed += 2
+	if m.prReplyMode {
+		label := "Reply:"
+		if m.prReplyThreadId == 0 {
+			label = "New comment:"
+		}
+		content.WriteString("\n  " + label + "\n")
+		content.WriteString("  " + m.prCommentInput.View() + "\n")
+		linesUsed += 3
 	}
 
 	// Fill remaining space with empty lines to maintain fixed height
@@ -2565,25 +7892,216 @@ func (m model) renderPRDetails(visibleLines int) string {
 	return content.String()
 }
 
-func (m model) renderPROverride(visibleLines int) string {
+func threadStatusLabel(status git.CommentThreadStatus) (string, lipgloss.Color) {
+	switch status {
+	case git.CommentThreadStatusValues.Active:
+		return "Active", lipgloss.Color("3") // Yellow
+	case git.CommentThreadStatusValues.Pending:
+		return "Pending", lipgloss.Color("3")
+	case git.CommentThreadStatusValues.Fixed:
+		return "Resolved", lipgloss.Color("2") // Green
+	case git.CommentThreadStatusValues.WontFix:
+		return "Won't fix", lipgloss.Color("240") // Gray
+	case git.CommentThreadStatusValues.Closed:
+		return "Closed", lipgloss.Color("240")
+	case git.CommentThreadStatusValues.ByDesign:
+		return "By design", lipgloss.Color("240")
+	}
+	return "Unknown", lipgloss.Color("240")
+}
+
+// formatDiffLine returns the plain and colored rendering of a diff line with its line number gutter
+func formatDiffLine(line diff.Line, width int, hasThread bool) (string, string) {
+	if line.Kind == diff.HunkHeader {
+		text := truncateRunes(line.Text, width)
+		return text, diffHunkStyle.Render(text)
+	}
+
+	oldNum, newNum := "", ""
+	if line.OldLine > 0 {
+		oldNum = fmt.Sprintf("%d", line.OldLine)
+	}
+	if line.NewLine > 0 {
+		newNum = fmt.Sprintf("%d", line.NewLine)
+	}
+	gutter := fmt.Sprintf("%4s %4s", oldNum, newNum)
+	threadMarker := " "
+	if hasThread {
+		threadMarker = "●"
+	}
+
+	marker := " "
+	switch line.Kind {
+	case diff.Added:
+		marker = "+"
+	case diff.Removed:
+		marker = "-"
+	}
+
+	text := strings.ReplaceAll(line.Text, "\t", "    ")
+	text = truncateRunes(marker+text, width-len(gutter)-3)
+
+	plain := gutter + " " + threadMarker + " " + text
+	coloredGutter := diffGutterStyle.Render(gutter) + " " + diffThreadStyle.Render(threadMarker) + " "
+	switch line.Kind {
+	case diff.Added:
+		return plain, coloredGutter + diffAddedStyle.Render(text)
+	case diff.Removed:
+		return plain, coloredGutter + diffRemovedStyle.Render(text)
+	}
+	return plain, coloredGutter + text
+}
+
+func changeTypeLabel(changeType git.VersionControlChangeType) (string, lipgloss.Color) {
+	switch {
+	case prs.HasChangeType(changeType, git.VersionControlChangeTypeValues.Add),
+		prs.HasChangeType(changeType, git.VersionControlChangeTypeValues.Undelete):
+		return "A", lipgloss.Color("2") // Green
+	case prs.HasChangeType(changeType, git.VersionControlChangeTypeValues.Delete):
+		return "D", lipgloss.Color("1") // Red
+	case prs.HasChangeType(changeType, git.VersionControlChangeTypeValues.Rename):
+		return "R", lipgloss.Color("5") // Magenta
+	}
+	return "M", lipgloss.Color("3") // Yellow
+}
+
+// Descriptions longer than this are cut off in the PR details view
+const maxDescriptionLines = 15
+
+var markdownHeadingStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+var markdownQuoteStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+var markdownCodeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("6"))
+
+// renderMarkdown writes markdown indented under a section label, returning the lines used
+func renderMarkdown(content *strings.Builder, text string, width int, maxLines int) int {
+	lines := markdown.Parse(text, width)
+	linesUsed := 0
+	for i, line := range lines {
+		if i >= maxLines {
+			content.WriteString(fmt.Sprintf("    ... %d more lines\n", len(lines)-maxLines))
+			linesUsed++
+			break
+		}
+
+		rendered := line.Text
+		switch line.Kind {
+		case markdown.Heading:
+			if line.Level <= 2 {
+				rendered = markdownHeadingStyle.Underline(true).Render(line.Text)
+			} else {
+				rendered = markdownHeadingStyle.Render(line.Text)
+			}
+		case markdown.Quote, markdown.Rule:
+			rendered = markdownQuoteStyle.Render(line.Text)
+		case markdown.Code:
+			rendered = markdownCodeStyle.Render(line.Text)
+		}
+		content.WriteString("    " + rendered + "\n")
+		linesUsed++
+	}
+	return linesUsed
+}
+
+func truncateRunes(text string, maxLen int) string {
+	if maxLen < 1 {
+		return ""
+	}
+	runes := []rune(text)
+	if len(runes) <= maxLen {
+		return text
+	}
+	if maxLen <= 3 {
+		return string(runes[:maxLen])
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
+
+func (m model) renderPRComplete(visibleLines int) string {
 	var content strings.Builder
 	linesUsed := 0
 
-	content.WriteString("  Override PR Completion\n\n")
-	linesUsed += 2
+	if m.prCompleteAuto {
+		content.WriteString("  Set Auto-Complete\n\n")
+		content.WriteString("  The PR will complete with these options once all policies pass.\n\n")
+		linesUsed += 4
+	} else if m.prCompleteBypass != "" {
+		content.WriteString("  Override PR Completion\n\n")
+		content.WriteString("  ⚠️  The PR will complete with these options, bypassing branch policies.\n")
+		content.WriteString("  Reason: " + m.prCompleteBypass + "\n\n")
+		linesUsed += 5
+	} else {
+		content.WriteString("  Complete Pull Request\n\n")
+		linesUsed += 2
+	}
 
-	content.WriteString("  This action will complete the PR even if all\n")
-	content.WriteString("  required reviews are not met.\n\n")
-	linesUsed += 3
+	// Warn about blocking policies before the server rejects the completion
+	if pending := m.pendingPolicies(); len(pending) > 0 && !m.prCompleteAuto && m.prCompleteBypass == "" {
+		warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3")) // Yellow
+		content.WriteString("  " + warningStyle.Render(fmt.Sprintf("⚠ %d required policies have not passed, completion will likely fail:", len(pending))) + "\n")
+		linesUsed++
+		for _, evaluation := range pending {
+			statusText, _ := policyStatusLabel(evaluation)
+			content.WriteString(fmt.Sprintf("    %s: %s\n", policies.EvaluationName(evaluation), statusText))
+			linesUsed++
+		}
+		content.WriteString("  Press Esc, then u to set auto-complete or o to override instead.\n\n")
+		linesUsed += 2
+	}
 
-	content.WriteString("  Override Reason:\n")
-	content.WriteString("  " + m.prOverrideInput.View() + "\n\n")
-	linesUsed += 3
+	label := func(step int, text string) string {
+		if m.prCompleteStep == step {
+			return highlightStyle.Render("→ " + text)
+		}
+		return "  " + text
+	}
+	checkbox := func(checked bool) string {
+		if checked {
+			return "[x]"
+		}
+		return "[ ]"
+	}
 
-	content.WriteString("  ⚠️  WARNING: This bypasses branch policies!\n\n")
+	// Merge strategy selector
+	content.WriteString(label(0, "Merge type:") + " " + mergeStrategyOptions[m.prMergeStrategy].name)
+	if m.prCompleteStep == 0 {
+		content.WriteString(" ←/→ to change")
+	}
+	content.WriteString("\n\n")
+	linesUsed += 2
+
+	content.WriteString(label(1, "Merge commit message:") + "\n")
+	content.WriteString("  " + m.prMergeMessageInput.View() + "\n")
 	linesUsed += 2
+	if mergeStrategyOptions[m.prMergeStrategy].strategy == git.GitPullRequestMergeStrategyValues.Rebase {
+		content.WriteString("  (not used when rebasing with fast-forward)\n")
+		linesUsed++
+	}
+	content.WriteString("\n")
+	linesUsed++
+
+	content.WriteString(label(2, "Delete source branch:") + " " + checkbox(m.prDeleteSourceBranch) + "\n")
+	content.WriteString(label(3, "Complete linked work items:") + " " + checkbox(m.prTransitionWorkItems) + "\n")
+	content.WriteString(label(4, "Save as default for this repository:") + " " + checkbox(m.prSaveCompleteDefaults) + "\n\n")
+	linesUsed += 4
+
+	// Show recent action message if available
+	if m.prActionMessage != "" && time.Since(m.prActionTime) < 10*time.Second {
+		messageColor := lipgloss.Color("2") // Green for success
+		if strings.Contains(m.prActionMessage, "Failed") {
+			messageColor = lipgloss.Color("1") // Red for error
+		}
+		messageStyle := lipgloss.NewStyle().Foreground(messageColor)
+		content.WriteString("  " + messageStyle.Render(m.prActionMessage) + "\n\n")
+		linesUsed += 2
+	}
 
-	content.WriteString("  Press Enter to confirm override\n")
+	if m.prCompleteAuto {
+		content.WriteString("  Press Enter to set auto-complete\n")
+	} else if m.prCompleteBypass != "" {
+		content.WriteString("  Press Enter to complete with override\n")
+	} else {
+		content.WriteString("  Press Enter to complete\n")
+	}
 	content.WriteString("  Press Esc to cancel\n")
 	linesUsed += 2
 
@@ -2598,7 +8116,37 @@ func (m model) renderPROverride(visibleLines int) string {
 
 func (m model) getInstructions() string {
 	if m.prOverrideMode {
-		return "Type override reason   •   Enter Confirm   •   Esc Cancel   •   q Quit"
+		return "Type override reason   •   Enter Next   •   Esc Cancel   •   q Quit"
+	}
+	if m.prCompleteMode {
+		return "Tab/↑↓ Field   •   ←/→ Merge Type   •   Space Toggle   •   Enter Complete   •   Esc Cancel"
+	}
+	if m.prReplyMode {
+		return "Type comment   •   Enter Post   •   Esc Cancel"
+	}
+	if m.prThreadStatusMode {
+		return "↑/↓ Choose status   •   Enter Apply   •   Esc Cancel"
+	}
+	if m.prVoteMode {
+		return "↑/↓ Choose vote   •   Enter Vote   •   Esc Cancel"
+	}
+	if m.prLinkWorkItemMode {
+		return "Type to search   •   Enter Search/Link   •   ↑/↓ Navigate   •   Esc Cancel"
+	}
+	if m.showRunPipeline {
+		if m.runPipelineStep == 0 {
+			return "Type to filter   •   ↑/↓ Navigate   •   Enter Select Branch   •   Tab Next Section   •   Esc Cancel"
+		}
+		return "Tab/Shift+Tab Section   •   ↑/↓ Navigate   •   Type to edit   •   Space Toggle/Cycle   •   Enter Run   •   Esc Cancel"
+	}
+	if m.prCherryPickMode {
+		return "Type to filter   •   ↑/↓ Navigate   •   Tab Cherry-pick/Revert   •   Enter Start/Create PR   •   Esc Close"
+	}
+	if m.prLabelMode {
+		return "Type a label   •   Enter Add   •   ↑/↓ Navigate   •   Ctrl+D Remove   •   Esc Done"
+	}
+	if m.prReviewerMode {
+		return "Type to search   •   ↑/↓ Navigate   •   Enter Add   •   Ctrl+R Required   •   Ctrl+D Remove   •   Esc Done"
 	}
 	if m.prCreateMode {
 		return "Tab Navigate   •   Enter Submit   •   Esc Cancel   •   q Quit"
@@ -2606,29 +8154,73 @@ func (m model) getInstructions() string {
 	if m.searchMode {
 		return "Type to search   •   ↑/↓ Navigate   •   Enter Select   •   Esc Cancel   •   q Quit"
 	}
+	if m.showPRDiff {
+		return "↑/↓ Move   •   PgUp/PgDn Page   •   v Select Range   •   c Comment   •   [/] Prev/Next File   •   Esc/← Back   •   q Quit"
+	}
+	if m.showPRThreads {
+		return "↑/↓ Navigate   •   r Reply   •   n New Comment   •   s Set Status   •   Esc/← Back   •   q Quit"
+	}
+	if m.showPRFiles {
+		return "↑/↓ Navigate   •   Enter View Diff   •   Esc/← Back   •   q Quit"
+	}
+	if m.showPRIterations {
+		return "↑/↓ Navigate   •   Space Mark Base   •   Enter View Changes   •   Esc/← Back   •   q Quit"
+	}
+	if m.runApprovalMode {
+		if m.runApprovalReject {
+			return "Type comment   •   Enter Reject   •   Esc Cancel"
+		}
+		return "Type comment   •   Enter Approve   •   Esc Cancel"
+	}
+	if m.showRunApprovals {
+		return "↑/↓ Navigate   •   a Approve   •   x Reject   •   r Refresh   •   Esc/← Back   •   q Quit"
+	}
+	if m.showRunLog {
+		return "↑/↓ Scroll   •   PgUp/PgDn Page   •   G Follow   •   r Reload   •   Esc/← Back   •   q Quit"
+	}
 	if m.showRunDetails {
 		refreshText := ""
 		if m.autoRefresh {
 			refreshText = " (Auto-refresh ON)"
 		}
-		return "↑/↓ Navigate   •   r Refresh" + refreshText + "   •   Esc/← Back   •   q Quit"
+		actions := "R Rerun"
+		if m.runInProgress() {
+			actions = "x Cancel"
+			if len(m.runPendingChecks()) > 0 {
+				actions = "a Approvals   •   x Cancel"
+			}
+		} else if stage := m.runStageAt(m.cursor); stage != nil && stageRetryable(stage) {
+			actions = "t Retry Stage   •   R Rerun"
+		}
+		return "↑/↓ Navigate   •   Enter View Log   •   Space Expand/Collapse   •   " + actions + "   •   r Refresh" + refreshText + "   •   Esc/← Back   •   q Quit"
 	}
 	if m.showRuns {
 		return "↑/↓ Navigate   •   Enter View Run   •   Esc/← Back   •   q Quit"
 	}
 	if m.showPRDetails {
-		return "a Approve   •   d Decline   •   c Complete   •   o Override   •   Esc/← Back   •   q Quit"
+		if m.prDetails != nil && m.prDetails.Status != nil {
+			switch *m.prDetails.Status {
+			case git.PullRequestStatusValues.Active:
+				return "a Approve   •   d Decline   •   v Vote   •   c Complete   •   u Auto-complete   •   o Override   •   f Files   •   i Iterations   •   t Threads   •   Esc/← Back"
+			case git.PullRequestStatusValues.Abandoned:
+				return "r Reactivate   •   f Files   •   i Iterations   •   t Threads   •   Esc/← Back   •   q Quit"
+			}
+		}
+		return "f Files   •   i Iterations   •   t Threads   •   Esc/← Back   •   q Quit"
 	}
 	if m.showPRs {
-		return "↑/↓ Navigate   •   Enter View PR   •   n New PR   •   Esc/← Back   •   q Quit"
+		return "↑/↓ Navigate   •   Enter View PR   •   n New PR   •   s Status   •   m Mine   •   a Reviewer   •   b Target   •   L Label   •   Esc/← Back"
+	}
+	if m.showDashboard {
+		return "↑/↓ Navigate   •   Enter View PR   •   Esc/← Back   •   q Quit"
 	}
 	if m.showPipelines {
-		return "↑/↓ Navigate   •   Enter View Runs   •   Esc/← Back   •   q Quit"
+		return "↑/↓ Navigate   •   Enter View Runs   •   n New Run   •   Esc/← Back   •   q Quit"
 	}
 	if m.showRepoOptions {
-		return "↑/↓ Navigate   •   Enter Select   •   Esc/← Back   •   q Quit"
+		return "↑/↓ Navigate   •   Enter Select   •   m My PRs   •   Esc/← Back   •   q Quit"
 	}
-	return "↑/↓ Navigate   •   ←/→ Switch Panels   •   Enter Select   •   / Search   •   Tab Focus   •   q Quit"
+	return "↑/↓ Navigate   •   ←/→ Switch Panels   •   Enter Select   •   / Search   •   m My PRs   •   Tab Focus   •   q Quit"
 }
 
 func main() {
@@ -2683,7 +8275,8 @@ func main() {
 		timeline:          nil,
 		prs:               []git.GitPullRequest{},
 		branches:          []git.GitRef{},
-		users:             []graph.GraphUser{},
+		users:             []identity.Reviewer{},
+		currentUser:       nil,
 		showRepoOptions:   false,
 		showPipelines:     false,
 		showRuns:          false,
@@ -2699,6 +8292,7 @@ func main() {
 		pipelinesScroll:   0,
 		runsScroll:        0,
 		timelineScroll:    0,
+		timelineExpanded:  map[uuid.UUID]bool{},
 		prsScroll:         0,
 		selectedProject:   nil,
 		selectedRepo:      nil,
@@ -2736,17 +8330,29 @@ func main() {
 		showConfigModal:   showModal,
 		prCreateMode:      false,
 		prTitleInput:      textinput.New(),
-		prDescInput:       textinput.New(),
+		prDescInput:       textarea.New(),
+		prDescTemplate:    "",
+		prDefaultSource:   "",
+		prDefaultTarget:   "",
+		prLocalBranch:     "",
+		pushingBranch:     false,
+		prCommits:         []git.GitCommitRef{},
+		loadingPRCommits:  false,
+		prGeneratedTitle:  "",
+		prGeneratedDesc:   "",
 		prSourceBranch:    nil,
 		prTargetBranch:    nil,
-		prReviewers:       []graph.GraphUser{},
+		prReviewers:       []git.IdentityRefWithVote{},
 		prCreateStep:      0,
 		reviewerSearch:    "",
-		filteredReviewers: []graph.GraphUser{},
+		filteredReviewers: []identity.Reviewer{},
+		prCreateDraft:     false,
+		prWorkItems:       []workitemtracking.WorkItem{},
 		// PR Details fields
 		showPRDetails:     false,
 		prDetails:         nil,
 		prComments:        []git.GitPullRequestCommentThread{},
+		prPolicies:        []policy.PolicyEvaluationRecord{},
 		loadingPRDetails:  false,
 		loadingPRComments: false,
 		prDetailsSpinner:  s1, // Reuse existing spinner
@@ -2759,6 +8365,122 @@ func main() {
 		prOverrideMode:  false,
 		prActionMessage: "",
 		prActionTime:    time.Time{},
+		// PR Diff fields
+		showPRFiles:    false,
+		showPRDiff:     false,
+		prFiles:        []prs.PRFileChange{},
+		prFilesScroll:  0,
+		selectedPRFile: nil,
+		prDiffLines:    nil,
+		prDiffBinary:   false,
+		prDiffError:    "",
+		prDiffScroll:   0,
+		prDiffAnchor:   -1,
+		loadingPRFiles: false,
+		loadingPRDiff:  false,
+		// PR Iteration fields
+		showPRIterations:      false,
+		prIterations:          []git.GitPullRequestIteration{},
+		loadingPRIterations:   false,
+		prIterationBase:       0,
+		prFilesIteration:      0,
+		prFilesCompareTo:      0,
+		prFilesFromIterations: false,
+		// PR Thread fields
+		showPRThreads:        false,
+		prThreadsScroll:      0,
+		prReplyMode:          false,
+		prReplyThreadId:      0,
+		prReplyInline:        false,
+		prThreadStatusMode:   false,
+		prThreadStatusCursor: 0,
+		prVoteMode:           false,
+		prVoteCursor:         0,
+		prAbandonConfirm:     false,
+		// PR Completion fields
+		prCompleteMode:         false,
+		prCompleteStep:         0,
+		prMergeStrategy:        0,
+		prMergeMessageInput:    textinput.New(),
+		prDeleteSourceBranch:   true,
+		prTransitionWorkItems:  false,
+		prSaveCompleteDefaults: false,
+		prCompleteAuto:         false,
+		prCompleteBypass:       "",
+		// PR List Filter fields
+		prStatusFilter:   0,
+		prFilterMine:     false,
+		prFilterReviewer: false,
+		prFilterTarget:   "",
+		prLabelFilter:    "",
+		prLabelOptions:   []string{},
+		prsError:         "",
+		// My PRs Dashboard fields
+		showDashboard:    false,
+		dashboardPRs:     []git.GitPullRequest{},
+		loadingDashboard: false,
+		prFromDashboard:  false,
+		dashboardScope:   []string{},
+		// Work Item fields
+		workItemSearch:     "",
+		workItemSearched:   "",
+		workItemResults:    []workitemtracking.WorkItem{},
+		searchingWorkItems: false,
+		prLinkedWorkItems:  []workitemtracking.WorkItem{},
+		prLinkWorkItemMode: false,
+		// PR Reviewer fields
+		prReviewerMode:          false,
+		prReviewerRemoveConfirm: "",
+		// PR Label fields
+		prLabelMode:  false,
+		prLabelInput: "",
+		// PR Cherry-pick fields
+		prCherryPickMode:    false,
+		prCherryPickRevert:  false,
+		prCherryPickFilter:  "",
+		prCherryPickRunning: false,
+		prCherryPickID:      0,
+		prCherryPickStatus:  "",
+		prCherryPickError:   "",
+		prCherryPickOnto:    "",
+		prCherryPickRef:     "",
+		prCherryPickCreated: map[string]bool{},
+		// Run Log fields
+		showRunLog:     false,
+		runLogRecordId: uuid.Nil,
+		runLogName:     "",
+		runLogId:       0,
+		runLogLines:    []string{},
+		loadingRunLog:  false,
+		runLogScroll:   0,
+		runLogFollow:   true,
+		runLogTailing:  false,
+		// Run Pipeline fields
+		showRunPipeline:    false,
+		runPipelineStep:    0,
+		loadingRunOptions:  false,
+		runBranches:        []git.GitRef{},
+		runBranchFilter:    "",
+		runBranch:          "",
+		runParameters:      []pipelineyaml.Parameter{},
+		runParameterValues: map[string]string{},
+		runVariables:       []pipelines.Variable{},
+		runVariableValues:  map[string]string{},
+		runStages:          []string{},
+		runSkipStages:      map[string]bool{},
+		queueingRun:        false,
+		queuedRunId:        0,
+		runPipelineError:   "",
+		// Run Action fields
+		runCancelConfirm: false,
+		runActionMessage: "",
+		runActionTime:    time.Time{},
+		// Run Approval fields
+		showRunApprovals:  false,
+		runApprovals:      map[uuid.UUID]pipelinesapproval.Approval{},
+		runApprovalMode:   false,
+		runApprovalReject: false,
+		runApprovalInput:  textinput.New(),
 	}
 
 	if _, err := tea.NewProgram(m, tea.WithAltScreen()).Run(); err != nil {